@@ -0,0 +1,50 @@
+"""Compare indexing with a cold and a warm parse cache on a synthetic project.
+
+Usage:
+    python benchmarks/bench_cache.py [--files 400] [--workers 1]
+"""
+
+from __future__ import annotations
+
+import argparse
+import tempfile
+import time
+from pathlib import Path
+
+from bench_index import generate
+
+from codemap.core.indexer import Indexer
+from codemap.utils.config import Config
+
+
+def run(root: Path, cache_dir: Path | None, workers: int) -> float:
+    """Index root, optionally with a parse cache, and return the elapsed seconds."""
+    config = Config(languages=["python"], workers=workers, cache_dir=str(cache_dir) if cache_dir else None)
+    indexer = Indexer(root=root, config=config)
+    start = time.perf_counter()
+    indexer.index_all()
+    return time.perf_counter() - start
+
+
+def main() -> None:
+    parser = argparse.ArgumentParser(description=__doc__.splitlines()[0])
+    parser.add_argument("--files", type=int, default=400, help="Number of generated files")
+    parser.add_argument("--workers", type=int, default=1, help="Parallel workers")
+    args = parser.parse_args()
+
+    with tempfile.TemporaryDirectory() as tmp, tempfile.TemporaryDirectory() as cache:
+        root = Path(tmp)
+        generate(root, args.files)
+        uncached = run(root, None, args.workers)
+        cold = run(root, Path(cache), args.workers)
+        warm = run(root, Path(cache), args.workers)
+
+    print(f"{args.files} files, {args.workers} workers")
+    print(f"no cache:   {uncached:.2f}s")
+    print(f"cold cache: {cold:.2f}s")
+    print(f"warm cache: {warm:.2f}s")
+    print(f"speedup:    {uncached / warm:.2f}x")
+
+
+if __name__ == "__main__":
+    main()
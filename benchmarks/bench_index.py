@@ -0,0 +1,82 @@
+"""Compare serial and parallel indexing on a synthetic project.
+
+Usage:
+    python benchmarks/bench_index.py [--files 400] [--workers N]
+"""
+
+from __future__ import annotations
+
+import argparse
+import os
+import tempfile
+import time
+from pathlib import Path
+
+from codemap.core.indexer import Indexer
+
+MODULE_TEMPLATE = '''"""Generated module {index}."""
+
+
+class Service{index}:
+    """A generated service."""
+
+    def __init__(self, name: str) -> None:
+        self.name = name
+
+{methods}
+
+def helper_{index}(values: list[int]) -> int:
+    """Sum the values."""
+    return sum(v * {index} for v in values)
+'''
+
+METHOD_TEMPLATE = '''    def method_{n}(self, x: int, y: int = {n}) -> int:
+        """Generated method {n}."""
+        total = 0
+        for i in range(x):
+            if i % 2:
+                total += i * y
+            else:
+                total -= i
+        return total
+'''
+
+
+def generate(root: Path, files: int) -> None:
+    """Write a project of generated Python modules into root."""
+    for index in range(files):
+        package = root / f"pkg{index % 20}"
+        package.mkdir(exist_ok=True)
+        methods = "\n".join(METHOD_TEMPLATE.format(n=n) for n in range(30))
+        (package / f"module_{index}.py").write_text(MODULE_TEMPLATE.format(index=index, methods=methods))
+
+
+def run(root: Path, workers: int) -> float:
+    """Index root with the given worker count and return the elapsed seconds."""
+    indexer = Indexer(root=root, languages=["python"])
+    indexer.config.workers = workers
+    start = time.perf_counter()
+    indexer.index_all()
+    return time.perf_counter() - start
+
+
+def main() -> None:
+    parser = argparse.ArgumentParser(description=__doc__.splitlines()[0])
+    parser.add_argument("--files", type=int, default=400, help="Number of generated files")
+    parser.add_argument("--workers", type=int, default=os.cpu_count() or 1, help="Parallel workers")
+    args = parser.parse_args()
+
+    with tempfile.TemporaryDirectory() as tmp:
+        root = Path(tmp)
+        generate(root, args.files)
+        serial = run(root, 1)
+        parallel = run(root, args.workers)
+
+    print(f"{args.files} files")
+    print(f"serial:            {serial:.2f}s")
+    print(f"parallel ({args.workers} workers): {parallel:.2f}s")
+    print(f"speedup:           {serial / parallel:.2f}x")
+
+
+if __name__ == "__main__":
+    main()
@@ -0,0 +1,79 @@
+"""Cross-file analysis over an indexed codebase."""
+
+from .callgraph import CallEdge, call_edges, call_graph
+from .collapse import collapse_large_files
+from .complexity import filter_complexity
+from .deprecated import filter_deprecated
+from .docs import DocOptions, first_sentence, strip_symbol_name, trim_doc, trim_docs
+from .diff import MapDiff, SymbolChange, SymbolRecord, diff_documents, symbol_records
+from .exported import filter_exported
+from .go_module import GoModule, ModuleResolver, read_module_path
+from .go_packages import GoPackage, collect_go_packages, link_enums, link_methods, package_doc
+from .implements import (
+    MethodDiff,
+    MethodSet,
+    MethodSetEntry,
+    PackageIndex,
+    link_implementations,
+    method_set,
+    missing_methods,
+)
+from .imports import ImportGraph, build_import_graph
+from .ordering import SORT_MODES, order_symbols, sort_symbols
+from .query import SymbolIndex, SymbolMatch
+from .stats import KindCount, MapStats, PackageStats, map_stats
+from .symbol_ids import assign_ids, assign_module_ids, go_qualifier, link_ids
+from .type_refs import ImportScope, assumed_package_name, resolve_type_refs
+
+__all__ = [
+    "GoPackage",
+    "collect_go_packages",
+    "link_methods",
+    "link_enums",
+    "package_doc",
+    "PackageIndex",
+    "link_implementations",
+    "MethodSet",
+    "MethodSetEntry",
+    "method_set",
+    "MethodDiff",
+    "missing_methods",
+    "filter_exported",
+    "filter_deprecated",
+    "filter_complexity",
+    "collapse_large_files",
+    "DocOptions",
+    "trim_doc",
+    "trim_docs",
+    "first_sentence",
+    "strip_symbol_name",
+    "SORT_MODES",
+    "order_symbols",
+    "sort_symbols",
+    "MapDiff",
+    "SymbolChange",
+    "SymbolRecord",
+    "diff_documents",
+    "symbol_records",
+    "GoModule",
+    "ModuleResolver",
+    "read_module_path",
+    "ImportGraph",
+    "build_import_graph",
+    "SymbolIndex",
+    "SymbolMatch",
+    "CallEdge",
+    "call_edges",
+    "call_graph",
+    "KindCount",
+    "MapStats",
+    "PackageStats",
+    "map_stats",
+    "assign_ids",
+    "assign_module_ids",
+    "go_qualifier",
+    "link_ids",
+    "ImportScope",
+    "assumed_package_name",
+    "resolve_type_refs",
+]
@@ -0,0 +1,191 @@
+"""Call graph of Go functions and methods within each package.
+
+The Go parser records each call in a function body as a chain of names
+("helper", "Service.repo.Get", "NewStore().Open"), and the chains are
+resolved here against the declarations of the caller's package: package
+functions, methods of the receiver's type (including methods promoted
+through embedded fields), the types of struct fields and package variables,
+and the first result of functions and methods called along the way. A call
+reaching an interface is an "interface call" edge to the interface method.
+
+Resolution is syntax-based, without type checking, so it misses calls it
+can't follow rather than guessing:
+
+- Calls into other packages, including imported ones, are not edges.
+- Local variables are typed only from their declaration (receivers,
+  parameters, "x := T{}", "x := NewT()", "var x T"); values from indexing,
+  range loops, channel receives or type switches are untyped.
+- Scoping within a function is flat, so a shadowed name keeps the type of
+  its last declaration, and calls in function literals belong to the
+  enclosing function.
+- Calls through function values, and interface calls to the concrete
+  methods that might run, are not followed.
+"""
+
+from __future__ import annotations
+
+from dataclasses import dataclass
+from typing import TYPE_CHECKING, Optional
+
+from ..parsers.base import Symbol
+from ..parsers.go_parser import TEST_FUNCTION_KINDS
+from .go_packages import GoPackage, collect_go_packages, receiver_base
+
+if TYPE_CHECKING:
+    from ..core.map_store import MapStore
+
+# Symbol types with a body that can make calls
+CALLER_TYPES = {"function", "method", *TEST_FUNCTION_KINDS.values()}
+
+
+@dataclass
+class CallEdge:
+    """A call from one Go function or method to another in its package."""
+
+    caller: str  # Path of the calling function, e.g. "sample.DefaultService.GetUser"
+    callee: str  # Path of the called function or method, e.g. "sample.validate"
+    interface: bool = False  # Call through an interface; callee is the interface method
+
+
+def call_edges(store: MapStore) -> list[CallEdge]:
+    """Get the intra-package call edges of every Go function and method.
+
+    Args:
+        store: Loaded MapStore.
+
+    Returns:
+        Edges in file order of the callers, then call order within each
+        caller; each callee appears once per caller.
+    """
+    index = store.symbol_index()
+    edges = []
+    for package in collect_go_packages(store.get_all_files()):
+        resolver = _Resolver(package)
+        for symbol in _callers(package):
+            caller = index.path_of(symbol)
+            seen: set[str] = set()
+            for call in symbol.calls:
+                resolved = resolver.resolve(call)
+                if resolved is None:
+                    continue
+                callee = index.path_of(resolved[0])
+                if callee is not None and callee not in seen:
+                    seen.add(callee)
+                    edges.append(CallEdge(caller=caller, callee=callee, interface=resolved[1]))
+    return edges
+
+
+def call_graph(store: MapStore) -> dict[str, list[str]]:
+    """Map every Go function and method to the functions and methods it calls.
+
+    Keys and callees are paths as used by MapStore.lookup. Functions that
+    call nothing in their package map to an empty list, and recursion
+    shows up as cycles. Interface calls are listed as the interface method.
+
+    Args:
+        store: Loaded MapStore.
+
+    Returns:
+        Caller path -> callee paths in call order.
+    """
+    index = store.symbol_index()
+    graph = {
+        index.path_of(symbol): []
+        for package in collect_go_packages(store.get_all_files())
+        for symbol in _callers(package)
+    }
+    for edge in call_edges(store):
+        graph[edge.caller].append(edge.callee)
+    return graph
+
+
+def _callers(package: GoPackage) -> list[Symbol]:
+    return [s for _, entry in package.files for s in entry.symbols if s.type in CALLER_TYPES]
+
+
+class _Resolver:
+    """Resolves recorded call chains against one package's declarations."""
+
+    def __init__(self, package: GoPackage):
+        self.package = package
+        self.functions: dict[str, Symbol] = {}
+        self.variables: dict[str, Symbol] = {}
+        for _, entry in package.files:
+            for symbol in entry.symbols:
+                if symbol.type == "function":
+                    self.functions[symbol.name] = symbol
+                elif symbol.type == "var":
+                    self.variables[symbol.name] = symbol
+
+    def resolve(self, call: str) -> Optional[tuple[Symbol, bool]]:
+        """Resolve a call chain to (callee, whether it's an interface call), or None."""
+        head, *rest = call.split(".")
+        if not rest:
+            callee = self.functions.get(head)
+            return (callee, False) if callee is not None else None
+        current = self._head_type(head)
+        for part in rest[:-1]:
+            if current is None:
+                return None
+            current = self._member_type(current, part)
+        return self._method(current, rest[-1]) if current is not None else None
+
+    def _head_type(self, head: str) -> Optional[str]:
+        """Type of the package-level name a chain starts at."""
+        if head.endswith("()"):
+            function = self.functions.get(head[:-2])
+            return self._result_type(function) if function is not None else None
+        if head in self.package.type_names:
+            return head
+        variable = self.variables.get(head)
+        return self._local_type(variable.signature) if variable is not None else None
+
+    def _member_type(self, type_name: str, member: str) -> Optional[str]:
+        """Type of a field, or of a method call's first result ("Get()")."""
+        if member.endswith("()"):
+            method = self._method(type_name, member[:-2])
+            return self._result_type(method[0]) if method is not None else None
+        for symbol in self._embedding_order(type_name):
+            for field in symbol.fields:
+                # Embedded fields are named by their type: s.Cache for "*Cache"
+                if (field.name or receiver_base(field.type)[0].rsplit(".", 1)[-1]) == member:
+                    return self._local_type(field.type)
+        return None
+
+    def _method(self, type_name: str, name: str) -> Optional[tuple[Symbol, bool]]:
+        """Find a method of a type, declared or promoted, shallowest first."""
+        for symbol in self._embedding_order(type_name):
+            if symbol.type == "interface":
+                for method in symbol.children:
+                    if method.type == "method" and method.name == name:
+                        return method, True
+                continue
+            for method in self.package.methods.get(symbol.name, []):
+                if method.name == name:
+                    return method, False
+        return None
+
+    def _embedding_order(self, type_name: str) -> list[Symbol]:
+        """A type followed by the package types it embeds, breadth first."""
+        order: list[Symbol] = []
+        seen: set[str] = set()
+        queue = [type_name]
+        while queue:
+            name = queue.pop(0)
+            symbol = self.package.types.get(name) or self.package.interfaces.get(name)
+            if symbol is None or name in seen:
+                continue
+            seen.add(name)
+            order.append(symbol)
+            queue.extend(t for t in (self._local_type(e) for e in symbol.embeds) if t is not None)
+        return order
+
+    def _result_type(self, function: Symbol) -> Optional[str]:
+        return self._local_type(function.results[0].type) if function.results else None
+
+    def _local_type(self, type_text: Optional[str]) -> Optional[str]:
+        """Base name of a type declared in the package, e.g. "*Store" -> "Store"."""
+        if not type_text:
+            return None
+        base, _ = receiver_base(type_text)
+        return base if base in self.package.type_names else None
@@ -0,0 +1,41 @@
+"""Collapse the listings of files with many symbols into counts."""
+
+from __future__ import annotations
+
+from collections import Counter
+from typing import TYPE_CHECKING, Iterator
+
+from ..parsers.base import Symbol
+
+if TYPE_CHECKING:
+    from ..core.map_store import MapStore
+
+
+def collapse_large_files(store: MapStore, max_symbols: int) -> MapStore:
+    """Get a copy of an index with large files reduced to symbol counts.
+
+    A file with more than max_symbols symbols, nested ones included, keeps
+    no symbols; its FileEntry.collapsed holds the count per symbol type
+    instead, e.g. {"function": 12, "method": 340, "struct": 40}. Files
+    without symbols keep collapsed as None, so the two stay distinct.
+
+    Args:
+        store: Loaded MapStore. It is not modified.
+        max_symbols: Largest symbol count listed in full.
+
+    Returns:
+        In-memory MapStore holding the collapsed index.
+    """
+    collapsed = store.copy()
+    for _, entry in collapsed.get_all_files():
+        counts = Counter(s.type for s in _walk(entry.symbols))
+        if sum(counts.values()) > max_symbols:
+            entry.collapsed = dict(sorted(counts.items()))
+            entry.symbols = []
+    return collapsed
+
+
+def _walk(symbols: list[Symbol]) -> Iterator[Symbol]:
+    for symbol in symbols:
+        yield symbol
+        yield from _walk(symbol.children)
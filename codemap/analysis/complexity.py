@@ -0,0 +1,42 @@
+"""Keep only the functions and methods above a complexity threshold."""
+
+from __future__ import annotations
+
+from typing import TYPE_CHECKING
+
+from ..parsers.base import Symbol
+
+if TYPE_CHECKING:
+    from ..core.map_store import MapStore
+
+
+def filter_complexity(store: MapStore, min_complexity: int) -> MapStore:
+    """Get a copy of an index holding only symbols at least min_complexity complex.
+
+    Only symbols with a recorded complexity (Go functions and methods) can
+    pass; types, constants, variables and symbols of other languages are
+    left out. Files keep their entries, so imports and notes stay listed.
+
+    Args:
+        store: Loaded MapStore. It is not modified.
+        min_complexity: Lowest cyclomatic complexity to keep.
+
+    Returns:
+        In-memory MapStore holding the filtered index.
+    """
+    filtered = store.copy()
+    for _, entry in filtered.get_all_files():
+        entry.symbols = _kept(entry.symbols, min_complexity)
+    return filtered
+
+
+def _kept(symbols: list[Symbol], min_complexity: int) -> list[Symbol]:
+    kept = []
+    for symbol in symbols:
+        children = _kept(symbol.children, min_complexity)
+        if symbol.complexity is not None and symbol.complexity >= min_complexity:
+            symbol.children = children
+            kept.append(symbol)
+        else:
+            kept.extend(children)
+    return kept
@@ -0,0 +1,36 @@
+"""Drop deprecated symbols from an index."""
+
+from __future__ import annotations
+
+from typing import TYPE_CHECKING
+
+from ..parsers.base import Symbol
+
+if TYPE_CHECKING:
+    from ..core.map_store import MapStore
+
+
+def filter_deprecated(store: MapStore) -> MapStore:
+    """Get a copy of an index without deprecated symbols and struct fields.
+
+    Args:
+        store: Loaded MapStore. It is not modified.
+
+    Returns:
+        In-memory MapStore holding the filtered index.
+    """
+    filtered = store.copy()
+    for _, entry in filtered.get_all_files():
+        entry.symbols = _kept(entry.symbols)
+    return filtered
+
+
+def _kept(symbols: list[Symbol]) -> list[Symbol]:
+    kept = []
+    for symbol in symbols:
+        if symbol.deprecated:
+            continue
+        symbol.children = _kept(symbol.children)
+        symbol.fields = [f for f in symbol.fields if not f.deprecated]
+        kept.append(symbol)
+    return kept
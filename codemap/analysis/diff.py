@@ -0,0 +1,209 @@
+"""Compare two code maps symbol by symbol.
+
+Maps are compared in the JSON export form (formatters.json_formatter), so a
+saved export from an earlier revision can be diffed against the current
+index. Symbols are keyed by a fully-qualified path such as
+"sample.DefaultService.GetUser": the package name (or, for languages
+without packages, the module path like "codemap.cli"), then the type, then
+the member. A rename therefore shows up as a removal plus an addition.
+"""
+
+from __future__ import annotations
+
+from dataclasses import dataclass, field
+from typing import Any, Iterator, Optional
+
+from ..utils.file_utils import module_path
+from .go_packages import receiver_base
+
+
+@dataclass
+class SymbolRecord:
+    """The comparable facts about one symbol."""
+
+    path: str
+    type: str
+    file: str
+    lines: tuple[int, int]
+    signature: Optional[str] = None  # As indexed, so cut when very long
+    docstring: Optional[str] = None
+    value: Optional[str] = None
+    is_alias: bool = False
+    params: list[str] = field(default_factory=list)  # "name type" per parameter, kept whole
+    results: list[str] = field(default_factory=list)  # Results in the same form
+    fields: list[str] = field(default_factory=list)  # "Name Type `tag`" per field
+    members: list[str] = field(default_factory=list)  # Names of methods and nested symbols
+
+
+@dataclass
+class SymbolChange:
+    """A symbol present in both maps whose API or documentation changed."""
+
+    path: str
+    old: SymbolRecord
+    new: SymbolRecord
+    changes: list[str]  # Any of "type", "signature", "value", "members", "fields", "doc"
+
+    @property
+    def added_members(self) -> list[str]:
+        return [m for m in self.new.members if m not in self.old.members]
+
+    @property
+    def removed_members(self) -> list[str]:
+        return [m for m in self.old.members if m not in self.new.members]
+
+    @property
+    def added_fields(self) -> list[str]:
+        return [f for f in self.new.fields if f not in self.old.fields]
+
+    @property
+    def removed_fields(self) -> list[str]:
+        return [f for f in self.old.fields if f not in self.new.fields]
+
+
+@dataclass
+class MapDiff:
+    """Differences between two maps.
+
+    modified holds API changes (type, signature, value, members, fields); symbols
+    whose doc comment changed are listed in doc_changed as well, so doc churn
+    can be ignored by looking only at the other lists.
+    """
+
+    added: list[SymbolRecord] = field(default_factory=list)
+    removed: list[SymbolRecord] = field(default_factory=list)
+    modified: list[SymbolChange] = field(default_factory=list)
+    doc_changed: list[SymbolChange] = field(default_factory=list)
+
+    @property
+    def has_api_changes(self) -> bool:
+        """Whether anything other than doc comments changed."""
+        return bool(self.added or self.removed or self.modified)
+
+    @property
+    def is_empty(self) -> bool:
+        return not (self.has_api_changes or self.doc_changed)
+
+
+def diff_documents(old: dict[str, Any], new: dict[str, Any]) -> MapDiff:
+    """Diff two JSON export documents.
+
+    Args:
+        old: Document from build_document() (or a loaded JSON export) before the change.
+        new: Document after the change.
+
+    Returns:
+        MapDiff with entries sorted by symbol path.
+    """
+    old_records = symbol_records(old)
+    new_records = symbol_records(new)
+    diff = MapDiff()
+
+    for path in sorted(set(old_records) | set(new_records)):
+        before, after = old_records.get(path), new_records.get(path)
+        if before is None:
+            diff.added.append(after)
+        elif after is None:
+            diff.removed.append(before)
+        else:
+            changes = _compare(before, after)
+            api_changes = [c for c in changes if c != "doc"]
+            if api_changes:
+                diff.modified.append(SymbolChange(path, before, after, api_changes))
+            if "doc" in changes:
+                diff.doc_changed.append(SymbolChange(path, before, after, ["doc"]))
+    return diff
+
+
+def symbol_records(document: dict[str, Any]) -> dict[str, SymbolRecord]:
+    """Index every symbol of an export document by its fully-qualified path."""
+    packages = document.get("packages", [])
+    name_counts: dict[str, int] = {}
+    for package in packages:
+        if package.get("name"):
+            name_counts[package["name"]] = name_counts.get(package["name"], 0) + 1
+
+    records: dict[str, SymbolRecord] = {}
+    for package in packages:
+        name = package.get("name")
+        if name and name_counts[name] > 1:
+            name = f"{package['path']}/{name}"  # Same package name in several directories
+        for path, record in _package_records(package, name):
+            unique, n = path, 2
+            while unique in records:
+                unique, n = f"{path}#{n}", n + 1  # e.g. several Go init() functions
+            record.path = unique
+            records[unique] = record
+    return records
+
+
+def _package_records(package: dict[str, Any], name: Optional[str]) -> Iterator[tuple[str, SymbolRecord]]:
+    """Yield (path, record) for the symbols of one package."""
+    symbols = package.get("symbols", [])
+    methods: dict[str, list[str]] = {}
+    for symbol in symbols:
+        if symbol.get("receiver"):
+            methods.setdefault(receiver_base(symbol["receiver"])[0], []).append(symbol["name"])
+
+    for symbol in symbols:
+        qualifier = name or module_path(symbol["file"])
+        if symbol.get("receiver"):
+            prefix = f"{qualifier}.{receiver_base(symbol['receiver'])[0]}"
+            yield from _symbol_records(symbol, prefix, [])
+        else:
+            yield from _symbol_records(symbol, qualifier, methods.get(symbol["name"], []))
+
+
+def _symbol_records(symbol: dict[str, Any], prefix: str, extra_members: list[str]) -> Iterator[tuple[str, SymbolRecord]]:
+    """Yield records for a symbol and its nested children."""
+    path = f"{prefix}.{symbol['name']}"
+    children = symbol.get("children", [])
+    yield path, SymbolRecord(
+        path=path,
+        type=symbol["type"],
+        file=symbol["file"],
+        lines=tuple(symbol["lines"]),
+        signature=symbol.get("signature"),
+        docstring=symbol.get("docstring"),
+        value=symbol.get("value"),
+        is_alias=symbol.get("is_alias", False),
+        params=[_param_text(p) for p in symbol.get("params", [])],
+        results=[_param_text(r) for r in symbol.get("results", [])],
+        fields=[_field_text(f) for f in symbol.get("fields", [])],
+        members=sorted({c["name"] for c in children} | set(extra_members)),
+    )
+    for child in children:
+        yield from _symbol_records(child, path, [])
+
+
+def _param_text(param: dict[str, Any]) -> str:
+    """Describe a parameter or result for comparison, e.g. "args ...string"."""
+    type_text = f"...{param['type']}" if param.get("variadic") else param["type"]
+    return f"{param['name']} {type_text}" if param.get("name") else type_text
+
+
+def _field_text(fld: dict[str, Any]) -> str:
+    """Describe a field for comparison, e.g. 'ID int `json:"id"`'."""
+    text = f"{fld['name']} {fld['type']}" if fld.get("name") else fld["type"]
+    if fld.get("tag"):
+        text += f" `{fld['tag']}`"
+    return text
+
+
+def _compare(old: SymbolRecord, new: SymbolRecord) -> list[str]:
+    """List what changed between two records of the same symbol."""
+    changes = []
+    if old.type != new.type or old.is_alias != new.is_alias:
+        changes.append("type")
+    # Signatures are cut when long, so a change past the cut only shows in params and results
+    if old.signature != new.signature or old.params != new.params or old.results != new.results:
+        changes.append("signature")
+    if old.value != new.value:
+        changes.append("value")
+    if old.members != new.members:
+        changes.append("members")
+    if old.fields != new.fields:
+        changes.append("fields")
+    if (old.docstring or None) != (new.docstring or None):
+        changes.append("doc")
+    return changes
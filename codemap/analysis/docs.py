@@ -0,0 +1,146 @@
+"""Trim doc comments for compact exports.
+
+Doc comments are stored as written. For exports packing many symbols into
+a small context, each can be cut down, in this order:
+
+1. strip_name drops the conventional leading symbol name, so "GetUser
+   retrieves a user by ID." becomes "retrieves a user by ID.".
+2. first_sentence keeps the first sentence of the first paragraph.
+3. max_lines keeps that many lines.
+4. max_chars cuts the rest at a word boundary, ending in "...".
+"""
+
+from __future__ import annotations
+
+import re
+from dataclasses import dataclass
+from typing import TYPE_CHECKING, Optional
+
+from ..parsers.base import Symbol
+
+if TYPE_CHECKING:
+    from ..core.map_store import MapStore
+
+# Words a period doesn't end a sentence after, compared case-insensitively
+ABBREVIATIONS = {
+    "e.g", "i.e", "etc", "vs", "cf", "al", "approx", "esp", "incl", "resp",
+    "no", "nos", "fig", "eq", "sec", "ch", "vol", "ref",
+    "mr", "mrs", "ms", "dr", "prof", "st", "jr", "sr", "inc", "ltd", "co", "corp",
+}
+
+# A period followed by whitespace or the end of the text
+_PERIOD_RE = re.compile(r"\.(?=\s|$)")
+
+
+@dataclass
+class DocOptions:
+    """How to trim doc comments; the defaults leave them unchanged."""
+
+    first_sentence: bool = False
+    max_chars: Optional[int] = None
+    max_lines: Optional[int] = None
+    strip_name: bool = False
+
+    @property
+    def active(self) -> bool:
+        """True if any option changes a doc comment."""
+        return self.first_sentence or self.strip_name or self.max_chars is not None or self.max_lines is not None
+
+
+def trim_docs(store: MapStore, options: DocOptions) -> MapStore:
+    """Get a copy of an index with every symbol's doc comment trimmed.
+
+    Args:
+        store: Loaded MapStore. It is not modified.
+        options: Trimming to apply.
+
+    Returns:
+        In-memory MapStore holding the trimmed index.
+    """
+    trimmed = store.copy()
+    for _, entry in trimmed.get_all_files():
+        _trim_all(entry.symbols, options)
+    return trimmed
+
+
+def trim_doc(doc: Optional[str], name: str, options: DocOptions) -> Optional[str]:
+    """Trim one doc comment.
+
+    Args:
+        doc: Doc comment, or None.
+        name: Name of the documented symbol, for strip_name.
+        options: Trimming to apply.
+
+    Returns:
+        The trimmed doc, or None if nothing is left.
+    """
+    if not doc:
+        return doc
+    doc = doc.strip()
+    if options.strip_name:
+        doc = strip_symbol_name(doc, name)
+    if options.first_sentence:
+        doc = first_sentence(doc)
+    if options.max_lines is not None:
+        doc = "\n".join(doc.splitlines()[:options.max_lines]).rstrip()
+    if options.max_chars is not None:
+        doc = _truncate(doc, options.max_chars)
+    return doc or None
+
+
+def strip_symbol_name(doc: str, name: str) -> str:
+    """Drop a leading symbol name, as in "GetUser retrieves ...", from a doc comment.
+
+    The doc is returned unchanged if it doesn't start with the name followed
+    by whitespace, or if the name is all there is.
+    """
+    if not name or not doc.startswith(name):
+        return doc
+    rest = doc[len(name):]
+    if not rest[:1].isspace() or not rest.strip():
+        return doc
+    return rest.lstrip()
+
+
+def first_sentence(doc: str) -> str:
+    """Get the first sentence of a doc comment's first paragraph.
+
+    The sentence ends at the first period followed by whitespace or the end
+    of the text, except after an abbreviation ("e.g.", "etc.", "Dr.") or a
+    single-letter initial ("J. Smith"). Periods not followed by whitespace,
+    as in "v1.2" or "os.Open", never end it. Line breaks inside the sentence
+    become spaces. Without such a period, the whole paragraph is returned.
+    """
+    paragraph = re.split(r"\n\s*\n", doc.strip(), maxsplit=1)[0]
+    for match in _PERIOD_RE.finditer(paragraph):
+        if not _is_abbreviation(paragraph[:match.start()]):
+            paragraph = paragraph[:match.end()]
+            break
+    return " ".join(paragraph.split())
+
+
+def _is_abbreviation(before: str) -> bool:
+    """Check if the word ending just before a period is an abbreviation or initial."""
+    words = before.split()
+    if not words:
+        return False
+    word = words[-1].lstrip("([\"'")
+    return word.casefold() in ABBREVIATIONS or (len(word) == 1 and word.isalpha() and word.isupper())
+
+
+def _truncate(doc: str, max_chars: int) -> str:
+    if len(doc) <= max_chars:
+        return doc
+    if max_chars <= 3:
+        return doc[:max_chars]
+    cut = doc[:max_chars - 3]
+    space = cut.rfind(" ")
+    if space > 0:
+        cut = cut[:space]
+    return cut.rstrip(" ,;:") + "..."
+
+
+def _trim_all(symbols: list[Symbol], options: DocOptions) -> None:
+    for symbol in symbols:
+        symbol.docstring = trim_doc(symbol.docstring, symbol.name, options)
+        _trim_all(symbol.children, options)
@@ -0,0 +1,103 @@
+"""Reduce an index to its exported (public) API.
+
+Symbols with exported=False are dropped, along with unexported struct
+fields and everything declared in test files. Symbols from languages
+without an export convention (exported=None) are kept. Signatures and
+cross references are left as written, so an exported function taking an
+unexported type still names it.
+
+A method that implements an exported interface is kept, since it is
+reachable through the public API, even if its name or its receiver type
+is unexported. An unexported receiver type itself is still dropped; the
+interface's implemented_by keeps naming it.
+"""
+
+from __future__ import annotations
+
+from typing import TYPE_CHECKING, Optional
+
+from ..parsers.base import Field, Symbol
+from ..parsers.go_parser import is_exported
+from .go_packages import GoPackage, collect_go_packages, receiver_base
+from .implements import PackageIndex
+
+if TYPE_CHECKING:
+    from ..core.map_store import MapStore
+
+
+def filter_exported(store: MapStore) -> MapStore:
+    """Get a copy of an index with only exported symbols.
+
+    Args:
+        store: Loaded MapStore. It is not modified.
+
+    Returns:
+        In-memory MapStore holding the filtered index.
+    """
+    filtered = store.copy()
+    packages = collect_go_packages(filtered.get_all_files())
+    index = PackageIndex(packages)
+    by_file = {rel_path: package for package in packages for rel_path, _ in package.files}
+    api_methods = {(p.directory, name): _interface_api(index, p, sym) for p in packages for name, sym in p.types.items()}
+
+    for rel_path, entry in filtered.get_all_files():
+        package = by_file.get(rel_path)
+        entry.symbols = [
+            s for s in (_filter_symbol(sym, package, api_methods) for sym in entry.symbols) if s is not None
+        ]
+    return filtered
+
+
+def _interface_api(index: PackageIndex, package: GoPackage, symbol: Symbol) -> Optional[set[str]]:
+    """Get the methods of exported interfaces a type implements, or None if there are none."""
+    names: set[str] = set()
+    found = False
+    for ref in symbol.implements:
+        resolved = index.resolve(package, ref)
+        if resolved is None or not is_exported(resolved[1]):
+            continue
+        found = True
+        names.update(index.interface_methods(*resolved))
+    return names if found else None
+
+
+def _filter_symbol(
+    symbol: Symbol,
+    package: Optional[GoPackage],
+    api_methods: dict[tuple[str, str], Optional[set[str]]],
+) -> Optional[Symbol]:
+    """Filter one top-level symbol, returning None if it is dropped."""
+    if symbol.in_test:
+        return None
+    if package is not None and symbol.type == "method" and symbol.receiver:
+        base, _ = receiver_base(symbol.receiver)
+        key = (package.directory, base)
+        interface_api = api_methods.get(key)
+        if interface_api is not None and symbol.name in interface_api:
+            return symbol
+        if not is_exported(base) or symbol.exported is False:
+            return None
+        return symbol
+
+    if symbol.exported is False:
+        return None
+
+    symbol.children = [c for c in (_filter_member(c) for c in symbol.children) if c is not None]
+    symbol.fields = [f for f in symbol.fields if _field_exported(f)]
+    return symbol
+
+
+def _filter_member(symbol: Symbol) -> Optional[Symbol]:
+    """Filter a nested symbol such as an interface or class method."""
+    if symbol.exported is False:
+        return None
+    symbol.children = [c for c in (_filter_member(c) for c in symbol.children) if c is not None]
+    return symbol
+
+
+def _field_exported(field: Field) -> bool:
+    """Check whether a struct field is exported; embedded fields use their type name."""
+    name = field.name
+    if field.embedded:
+        name = receiver_base(field.type)[0].rsplit(".", 1)[-1]
+    return is_exported(name or "")
@@ -0,0 +1,94 @@
+"""Read Go module information from go.mod."""
+
+from __future__ import annotations
+
+import re
+from dataclasses import dataclass
+from pathlib import Path
+from typing import Optional
+
+# Module of the standard library in GOROOT/src, whose import paths have no module prefix
+STD_MODULE = "std"
+
+_MODULE_RE = re.compile(r'^\s*module\s+"?([^\s"]+)"?', re.MULTILINE)
+
+
+@dataclass
+class GoModule:
+    """A Go module: the directory holding a go.mod and the path it declares."""
+
+    path: Optional[str]  # Module path, e.g. "github.com/me/proj"; None without a module directive
+    directory: Path  # Absolute directory of the go.mod
+
+
+def read_module_path(root: Path) -> Optional[str]:
+    """Get the module path declared by root/go.mod, e.g. "github.com/me/proj".
+
+    Args:
+        root: Directory that may contain a go.mod file.
+
+    Returns:
+        The module path, or None if there is no go.mod or it has no module directive.
+    """
+    try:
+        text = (root / "go.mod").read_text(encoding="utf-8")
+    except OSError:
+        return None
+    match = _MODULE_RE.search(text)
+    return match.group(1) if match else None
+
+
+class ModuleResolver:
+    """Resolves directories of a tree to Go import paths.
+
+    A directory belongs to the module of the nearest go.mod in it or one of
+    its parents, as with the go command, so nested modules and a root
+    inside a module both resolve. Lookups are cached per directory.
+    """
+
+    def __init__(self, root: Path):
+        """Initialize the resolver.
+
+        Args:
+            root: Root of the indexed tree; directories are relative to it.
+        """
+        self.root = root.resolve()
+        self._modules: dict[Path, Optional[GoModule]] = {}
+
+    def module_for(self, directory: str) -> Optional[GoModule]:
+        """Get the module governing a directory relative to the root, or None outside any module."""
+        return self._find(self.root / directory)
+
+    def import_path(self, directory: str) -> tuple[str, bool]:
+        """Get the import path of a package directory.
+
+        Args:
+            directory: Directory relative to the root ("." for the root).
+
+        Returns:
+            (import path, resolved). When no go.mod with a module directive
+            governs the directory, the path is the directory itself and
+            resolved is False. Packages of the standard library's "std"
+            module (GOROOT/src) have unprefixed paths such as "encoding/json".
+        """
+        module = self.module_for(directory)
+        if module is None or module.path is None:
+            return directory, False
+        target = (self.root / directory).resolve()
+        rel = target.relative_to(module.directory).as_posix()
+        if module.path == STD_MODULE and rel != ".":
+            return rel, True
+        return (module.path if rel == "." else f"{module.path}/{rel}"), True
+
+    def _find(self, directory: Path) -> Optional[GoModule]:
+        directory = directory.resolve()
+        if directory in self._modules:
+            return self._modules[directory]
+        if (directory / "go.mod").is_file():
+            module: Optional[GoModule] = GoModule(path=read_module_path(directory), directory=directory)
+        elif directory.parent == directory:
+            module = None
+        else:
+            module = self._find(directory.parent)
+        self._modules[directory] = module
+        return module
@@ -0,0 +1,287 @@
+"""Group indexed Go files into packages for cross-file analysis."""
+
+from __future__ import annotations
+
+import re
+from dataclasses import dataclass, field
+from pathlib import Path, PurePosixPath
+from typing import TYPE_CHECKING, Iterable, Optional
+
+from ..parsers.base import EnumValue, Symbol
+from .go_module import ModuleResolver
+
+if TYPE_CHECKING:
+    from ..core.map_store import FileEntry
+
+# Keywords that can start a type expression and must not be read as parameter names
+_TYPE_KEYWORDS = {"chan", "func", "map", "struct", "interface"}
+
+_IDENT_RE = re.compile(r"(?<![\w.])([A-Za-z_]\w*)")
+
+
+@dataclass
+class GoPackage:
+    """All symbols of one Go package (a directory plus package name)."""
+
+    directory: str
+    name: str
+    files: list[tuple[str, FileEntry]] = field(default_factory=list)
+    types: dict[str, Symbol] = field(default_factory=dict)  # Structs and other named types
+    interfaces: dict[str, Symbol] = field(default_factory=dict)
+    methods: dict[str, list[Symbol]] = field(default_factory=dict)  # Receiver base name -> methods
+    constants: list[Symbol] = field(default_factory=list)  # In file order, then declaration order
+    import_path: Optional[str] = None  # e.g. "github.com/me/proj/internal/util"; None unless a root was given
+    import_path_resolved: bool = False  # False when no go.mod was found and import_path is the directory
+
+    @property
+    def is_external_test(self) -> bool:
+        """Whether this is an external test package ("package foo_test")."""
+        return self.name.endswith("_test")
+
+    @property
+    def doc(self) -> Optional[str]:
+        """Package doc comment, merged across files; see package_doc()."""
+        return package_doc(self.files)
+
+    @property
+    def type_names(self) -> set[str]:
+        """Names of every type declared in the package."""
+        return set(self.types) | set(self.interfaces)
+
+
+def collect_go_packages(
+    files: Iterable[tuple[str, FileEntry]], root: Optional[Path] = None
+) -> list[GoPackage]:
+    """Group indexed Go files by directory and package name.
+
+    Args:
+        files: (relative_path, FileEntry) pairs, e.g. from MapStore.get_all_files().
+        root: Root the paths are relative to. When given, each package's
+            import_path is resolved from the nearest go.mod.
+
+    Returns:
+        Packages sorted by directory and name.
+    """
+    modules = ModuleResolver(root) if root is not None else None
+    packages: dict[tuple[str, str], GoPackage] = {}
+    for rel_path, entry in sorted(files, key=lambda f: f[0]):
+        if entry.language != "go" or not entry.package:
+            continue
+        directory = str(PurePosixPath(rel_path).parent)
+        key = (directory, entry.package)
+        package = packages.get(key)
+        if package is None:
+            package = packages[key] = GoPackage(directory=directory, name=entry.package)
+            if modules is not None:
+                package.import_path, package.import_path_resolved = modules.import_path(directory)
+        package.files.append((rel_path, entry))
+        for symbol in entry.symbols:
+            _add_symbol(package, symbol)
+    return [packages[key] for key in sorted(packages)]
+
+
+def link_methods(packages: list[GoPackage]) -> None:
+    """Fill methods on every type with the names of the methods declared on it.
+
+    A package's methods are matched to its types by the base name of their
+    receiver, so "*Cache[K, V]" belongs to Cache wherever in the package
+    either is declared. Names are in file order, then declaration order.
+    Methods whose type isn't in the package (excluded by build constraints,
+    or not indexed) are left unattached.
+    """
+    for package in packages:
+        for name, symbol in package.types.items():
+            symbol.methods = [m.name for m in package.methods.get(name, [])]
+
+
+def link_enums(packages: list[GoPackage]) -> None:
+    """Fill enum_values on every named type with the grouped constants of that type.
+
+    A defined type T is an enum when a const block declares values of type
+    T, as stringer expects: "A T = iota" and the names repeating it, or
+    values typed T explicitly. Constants declared alone, or of another
+    type within the block, don't count. Values are in file order, then
+    declaration order, from any file of the package.
+    """
+    for package in packages:
+        values: dict[str, list[EnumValue]] = {}
+        for constant in package.constants:
+            if constant.group and constant.signature in package.types:
+                values.setdefault(constant.signature, []).append(
+                    EnumValue(name=constant.name, value=constant.value, int_value=constant.int_value)
+                )
+        for name, symbol in package.types.items():
+            enum = symbol.type == "type" and not symbol.is_alias
+            symbol.enum_values = values.get(name, []) if enum else []
+
+
+def package_doc(files: Iterable[tuple[str, FileEntry]]) -> Optional[str]:
+    """Merge the package doc comments of a package's files.
+
+    A doc.go file comes first, then the other files by path; comments that
+    repeat an earlier one are dropped and the rest are separated by a blank
+    line.
+
+    Args:
+        files: (relative_path, FileEntry) pairs of one package.
+
+    Returns:
+        Package documentation, or None if no file has a package doc comment.
+    """
+    ordered = sorted(files, key=lambda f: (PurePosixPath(f[0]).name != "doc.go", f[0]))
+    docs: list[str] = []
+    for _, entry in ordered:
+        if entry.package_doc and entry.package_doc not in docs:
+            docs.append(entry.package_doc)
+    return "\n\n".join(docs) or None
+
+
+def _add_symbol(package: GoPackage, symbol: Symbol) -> None:
+    """Register a top-level symbol with its package."""
+    if symbol.type == "interface":
+        package.interfaces[symbol.name] = symbol
+    elif symbol.type in ("struct", "type"):
+        package.types[symbol.name] = symbol
+    elif symbol.type == "method" and symbol.receiver:
+        base, _ = receiver_base(symbol.receiver)
+        package.methods.setdefault(base, []).append(symbol)
+    elif symbol.type == "const":
+        package.constants.append(symbol)
+
+
+def receiver_base(receiver: str) -> tuple[str, bool]:
+    """Split a receiver type into its base type name and pointer flag.
+
+    "*Set[T]" becomes ("Set", True); "User" becomes ("User", False).
+    Parentheses and spacing allowed by the grammar are ignored, so
+    "(*Set[K, V])" and "* Set [T]" give ("Set", True) too.
+    """
+    text = _unparenthesize(receiver)
+    pointer = text.startswith("*")
+    base = _unparenthesize(text[1:] if pointer else text)
+    return base.split("[", 1)[0].strip(), pointer
+
+
+def _unparenthesize(text: str) -> str:
+    text = text.strip()
+    while text.startswith("(") and text.endswith(")"):
+        text = text[1:-1].strip()
+    return text
+
+
+def split_top_level(text: str, sep: str = ",") -> list[str]:
+    """Split text on a separator, ignoring separators nested in brackets."""
+    parts, depth, current = [], 0, []
+    for char in text:
+        if char in "([{":
+            depth += 1
+        elif char in ")]}":
+            depth -= 1
+        if char == sep and depth == 0:
+            parts.append("".join(current).strip())
+            current = []
+        else:
+            current.append(char)
+    tail = "".join(current).strip()
+    if tail:
+        parts.append(tail)
+    return parts
+
+
+def parameter_types(param_list: str) -> list[str]:
+    """Get the types of a parenthesized Go parameter list, dropping names.
+
+    Handles grouped names ("a, b int"), unnamed parameters ("(int, error)"),
+    and variadics ("args ...string").
+    """
+    inner = param_list.strip()
+    if inner.startswith("(") and inner.endswith(")"):
+        inner = inner[1:-1]
+    parts = split_top_level(inner)
+
+    named = [_split_named(p) for p in parts]
+    if not any(n is not None for n in named):
+        return parts
+
+    types: list[str] = []
+    pending = 0
+    for split in named:
+        if split is None:
+            pending += 1  # Name sharing the type of the next named part
+            continue
+        types.extend([split[1]] * (pending + 1))
+        pending = 0
+    return types
+
+
+def _split_named(part: str) -> Optional[tuple[str, str]]:
+    """Split "name Type" into (name, Type), or None if the part is only a type or name."""
+    pieces = part.split(None, 1)
+    if len(pieces) != 2 or pieces[0] in _TYPE_KEYWORDS or not pieces[0].isidentifier():
+        return None
+    return pieces[0], pieces[1].strip()
+
+
+def signature_key(signature: Optional[str], package: Optional[GoPackage] = None) -> Optional[str]:
+    """Normalize a Go signature to its parameter and result types.
+
+    Parameter names are dropped and whitespace collapsed, so
+    "(id int) (*User, error)" and "(userID int) (*User, error)" share the key
+    "(int)(*User,error)". When a package is given, its own type names are
+    qualified ("User" becomes "sample.User") so keys compare across packages.
+
+    Returns None for signatures that cannot be compared, such as ones
+    truncated by the index.
+    """
+    if signature is None or signature.endswith("..."):
+        return None
+    text = signature.strip()
+    if not text.startswith("("):
+        return None
+
+    params, rest = _take_group(text)
+    results = rest.strip()
+    result_types = parameter_types(results) if results.startswith("(") else ([results] if results else [])
+
+    types = [_normalize_type(t, package) for t in parameter_types(params)]
+    results_norm = [_normalize_type(t, package) for t in result_types]
+    return f"({','.join(types)})({','.join(results_norm)})"
+
+
+def method_key(method: Symbol, package: Optional[GoPackage] = None) -> Optional[str]:
+    """Get the signature key of a function or method, as from signature_key.
+
+    The key is built from the parameter and result lists, which the index
+    stores whole, so it survives a signature cut at MAX_SIGNATURE_LENGTH.
+    Symbols without either list fall back to their signature.
+    """
+    if not method.params and not method.results:
+        return signature_key(method.signature, package)
+    params = [_normalize_type(f"...{p.type}" if p.variadic else p.type, package) for p in method.params]
+    results = [_normalize_type(p.type, package) for p in method.results]
+    return f"({','.join(params)})({','.join(results)})"
+
+
+def _take_group(text: str) -> tuple[str, str]:
+    """Split a leading balanced parenthesized group from the rest of the text."""
+    depth = 0
+    for i, char in enumerate(text):
+        if char == "(":
+            depth += 1
+        elif char == ")":
+            depth -= 1
+            if depth == 0:
+                return text[: i + 1], text[i + 1:]
+    return text, ""
+
+
+def _normalize_type(type_text: str, package: Optional[GoPackage]) -> str:
+    """Collapse whitespace and qualify package-local type names."""
+    normalized = " ".join(type_text.split())
+    if package is None:
+        return normalized
+    local = package.type_names
+    return _IDENT_RE.sub(
+        lambda m: f"{package.name}.{m.group(1)}" if m.group(1) in local else m.group(1),
+        normalized,
+    )
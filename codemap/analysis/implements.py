@@ -0,0 +1,441 @@
+"""Interface satisfaction for Go packages using method-set comparison.
+
+Method sets follow the Go spec: the value type T has the methods declared
+with value receivers, while *T also has the pointer-receiver methods.
+Methods promoted through embedded fields and embedded interfaces are
+included, with shallower methods shadowing deeper ones and same-depth
+collisions dropped as ambiguous.
+
+Embedded types are resolved only when they are declared in an indexed
+package (qualified references like "store.Base" match through the
+package's imports, or by package name); types from outside the index, such
+as io.Reader, contribute no methods and are listed as unresolved in a
+MethodSet. An interface embedding one is never reported as implemented,
+since part of its method set is unknown.
+Interfaces with an empty method set (interface{}, any) are skipped because
+every type satisfies them.
+
+PackageIndex.missing_methods explains a failed match: one MethodDiff per
+interface method the type lacks, with near misses (a method of the right
+name but wrong signature, or differing only in case) and methods only *T
+has told apart from methods that are missing outright.
+"""
+
+from __future__ import annotations
+
+from dataclasses import dataclass, field, replace
+from typing import TYPE_CHECKING, Optional
+
+from ..parsers.base import Symbol
+from ..parsers.go_parser import is_exported
+from .go_packages import GoPackage, _take_group, collect_go_packages, method_key, receiver_base
+from .type_refs import ImportScope
+
+if TYPE_CHECKING:
+    from ..core.map_store import MapStore
+
+@dataclass
+class MethodSetEntry:
+    """A method in a type's method set, declared on the type or promoted to it."""
+
+    name: str
+    signature: Optional[str]  # As declared, e.g. "(p []byte) (n int, err error)"
+    key: Optional[str]  # method_key() in the declaring package; None if not comparable
+    symbol: Symbol  # The method declaration, or the interface method
+    declared_by: str  # Type or interface declaring the method, e.g. "Buffer"
+    package: str  # Directory of the declaring package
+    pointer_receiver: bool = False  # Declared on *T; always False for interface methods
+    via: tuple[str, ...] = ()  # Embedded fields or interfaces it is promoted through, outermost first
+
+    @property
+    def promoted(self) -> bool:
+        """Whether the method comes from an embedded field or interface."""
+        return bool(self.via)
+
+
+@dataclass
+class MethodSet:
+    """The complete method set of a type (T or *T) or an interface."""
+
+    type_name: str  # e.g. "DefaultService" or "*DefaultService"
+    methods: list[MethodSetEntry] = field(default_factory=list)  # Sorted by name
+    unresolved: list[str] = field(default_factory=list)  # Embedded types outside the index, e.g. "*bytes.Buffer"
+
+    def names(self) -> list[str]:
+        return [m.name for m in self.methods]
+
+    def get(self, name: str) -> Optional[MethodSetEntry]:
+        return next((m for m in self.methods if m.name == name), None)
+
+
+@dataclass
+class MethodDiff:
+    """Why a type lacks one method of an interface."""
+
+    name: str  # Interface method
+    kind: str  # "missing", "signature", "pointer_receiver", "unexported" or "uncomparable"
+    detail: str  # One sentence explaining the problem
+    want: Optional[str] = None  # Signature the interface declares
+    got: Optional[str] = None  # Signature of the type's method of that name, if any
+    declared_by: Optional[str] = None  # Type declaring got, "*T" for a pointer receiver
+    candidate: Optional[str] = None  # For "missing": a method of the type whose name differs only in case
+
+
+# Method name -> entry; an entry's embedding depth is len(entry.via)
+_MethodSet = dict[str, MethodSetEntry]
+
+
+class PackageIndex:
+    """Resolves type references across a set of Go packages."""
+
+    def __init__(self, packages: list[GoPackage]):
+        self.packages = packages
+        self._by_name: dict[str, list[GoPackage]] = {}
+        self._by_path: dict[str, GoPackage] = {}
+        self._qualifiers: dict[int, dict[str, str]] = {}  # id(package) -> local name -> import path
+        for package in packages:
+            self._by_name.setdefault(package.name, []).append(package)
+            if package.import_path_resolved and not package.is_external_test:
+                self._by_path.setdefault(package.import_path, package)
+
+    def resolve(self, package: GoPackage, ref: str) -> Optional[tuple[GoPackage, str]]:
+        """Resolve a type reference like "Base", "*Base" or "store.Base[T]".
+
+        A qualifier is looked up in the package's imports when the imported
+        package is indexed with its import path, and matched by package
+        name otherwise.
+        """
+        name = ref.strip().lstrip("*").split("[", 1)[0]
+        if "." in name:
+            qualifier, name = name.split(".", 1)
+            target = self._by_path.get(self._imports(package).get(qualifier, ""))
+            if target is None:
+                candidates = self._by_name.get(qualifier, [])
+                target = candidates[0] if candidates else None
+        else:
+            target = package
+        if target is None or name not in target.type_names:
+            return None
+        return target, name
+
+    def method_set(self, package: GoPackage, type_name: str, pointer: bool) -> dict[str, Optional[str]]:
+        """Get the method set of a named concrete type as name -> signature key.
+
+        Args:
+            package: Package declaring the type.
+            type_name: Type name without pointer or type arguments.
+            pointer: True for the method set of *T, False for T.
+        """
+        return {name: m.key for name, m in self._concrete_methods(package, type_name, pointer, set(), []).items()}
+
+    def interface_methods(self, package: GoPackage, name: str) -> dict[str, Optional[str]]:
+        """Get the full method set of an interface, including embedded interfaces."""
+        return {n: m.key for n, m in self._interface_methods(package, name, set(), []).items()}
+
+    def full_method_set(self, package: GoPackage, type_name: str, pointer: bool = False) -> MethodSet:
+        """Get the method set of a type or interface with where each method comes from.
+
+        Args:
+            package: Package declaring the type.
+            type_name: Type name without pointer or type arguments.
+            pointer: True for the method set of *T, False for T. Ignored
+                for interfaces.
+        """
+        unresolved: list[str] = []
+        if type_name in package.interfaces:
+            methods = self._interface_methods(package, type_name, set(), unresolved)
+        else:
+            methods = self._concrete_methods(package, type_name, pointer, set(), unresolved)
+            type_name = f"*{type_name}" if pointer else type_name
+        return MethodSet(
+            type_name=type_name,
+            methods=[methods[name] for name in sorted(methods)],
+            unresolved=unresolved,
+        )
+
+    def missing_methods(
+        self, package: GoPackage, type_name: str, iface_package: GoPackage, iface_name: str, pointer: bool = False
+    ) -> list[MethodDiff]:
+        """Explain why a type doesn't implement an interface.
+
+        Kinds of MethodDiff:
+
+        - "missing": the type has no method of that name.
+        - "signature": it has one, with other parameter or result types.
+        - "pointer_receiver": only *T has it; checked with pointer=False.
+        - "unexported": the method is unexported and the type is declared
+          in another package, which can never implement it.
+        - "uncomparable": a signature was truncated in the index, or the
+          interface embeds an interface outside the index (name is then
+          the embedded reference, e.g. "io.Reader").
+
+        Args:
+            package: Package declaring the type.
+            type_name: Concrete type name without pointer or type arguments.
+            iface_package: Package declaring the interface.
+            iface_name: Interface name.
+            pointer: Check *T instead of T.
+
+        Returns:
+            Diffs sorted by method name, then one per unresolved embedded
+            interface; empty when the type implements the interface, as
+            link_implementations decides it.
+        """
+        unresolved: list[str] = []
+        unknown: list[str] = []
+        wanted = self._interface_methods(iface_package, iface_name, set(), unknown)
+        values = self._concrete_methods(package, type_name, pointer, set(), unresolved)
+        pointers = values if pointer else self._concrete_methods(package, type_name, True, set(), [])
+        shown = f"*{type_name}" if pointer else type_name
+
+        diffs = []
+        for name in sorted(wanted):
+            want = wanted[name]
+            got = pointers.get(name)
+            diff = MethodDiff(name=name, kind="", detail="", want=want.signature)
+            if got is not None:
+                diff.got = got.signature
+                diff.declared_by = f"*{got.declared_by}" if got.pointer_receiver else got.declared_by
+            if iface_package is not package and not is_exported(name):
+                diff.kind = "unexported"
+                diff.detail = f"{name} is unexported, so only types in package {iface_package.name} can implement it"
+            elif got is None:
+                diff.kind = "missing"
+                diff.candidate = next((n for n in sorted(pointers) if n.lower() == name.lower()), None)
+                diff.detail = f"{shown} has no method {name}"
+                if diff.candidate is not None:
+                    diff.detail += f"; it has {diff.candidate}, which differs in case"
+                elif unresolved:
+                    diff.detail += f"; it may be promoted from {', '.join(unresolved)}, which isn't indexed"
+            elif want.key is None or got.key is None:
+                diff.kind = "uncomparable"
+                diff.detail = f"The signature of {name} is truncated in the index and can't be compared"
+            elif want.key != got.key:
+                diff.kind = "signature"
+                diff.detail = f"{name}{got.signature or '()'} has {_mismatch(got.key, want.key)}; want {name}{want.signature}"
+            elif name not in values:
+                diff.kind = "pointer_receiver"
+                diff.detail = f"{name} has a pointer receiver, so it is in the method set of *{type_name}, not {type_name}"
+            else:
+                continue
+            diffs.append(diff)
+        for embed in unknown:
+            diffs.append(MethodDiff(
+                name=embed,
+                kind="uncomparable",
+                detail=f"{iface_name} embeds {embed}, which isn't indexed, so its methods can't be checked",
+            ))
+        return diffs
+
+    def _imports(self, package: GoPackage) -> dict[str, str]:
+        qualifiers = self._qualifiers.get(id(package))
+        if qualifiers is None:
+            names = {path: p.name for path, p in self._by_path.items()}
+            qualifiers = {}
+            for _, entry in package.files:
+                for name, path in ImportScope(entry.imports, package_names=names).qualifiers.items():
+                    qualifiers.setdefault(name, path)
+            self._qualifiers[id(package)] = qualifiers
+        return qualifiers
+
+    def _concrete_methods(
+        self, package: GoPackage, type_name: str, pointer: bool, seen: set[tuple[str, str]], unresolved: list[str]
+    ) -> _MethodSet:
+        """Collect declared and promoted methods of a concrete type."""
+        marker = (package.directory, type_name)
+        if marker in seen:
+            return {}
+        seen = seen | {marker}
+
+        methods: _MethodSet = {}
+        for method in package.methods.get(type_name, []):
+            _, pointer_receiver = receiver_base(method.receiver or "")
+            if pointer_receiver and not pointer:
+                continue
+            methods[method.name] = MethodSetEntry(
+                name=method.name,
+                signature=method.signature,
+                key=method_key(method, package),
+                symbol=method,
+                declared_by=type_name,
+                package=package.directory,
+                pointer_receiver=pointer_receiver,
+            )
+
+        symbol = package.types.get(type_name)
+        if symbol is not None and symbol.type == "struct":
+            for name, entry in self._promoted(package, symbol, pointer, seen, unresolved).items():
+                methods.setdefault(name, entry)
+        return methods
+
+    def _promoted(self, package: GoPackage, symbol: Symbol, pointer: bool, seen, unresolved) -> _MethodSet:
+        """Collect methods promoted through a struct's embedded fields."""
+        promoted: _MethodSet = {}
+        ambiguous: set[str] = set()
+        for embed in symbol.embeds:
+            resolved = self.resolve(package, embed)
+            if resolved is None:
+                unresolved.append(embed)
+                continue
+            target, name = resolved
+            if name in target.interfaces:
+                inner = self._interface_methods(target, name, seen, unresolved)
+            else:
+                # Embedding *T promotes T's pointer methods even into the value type
+                inner = self._concrete_methods(target, name, pointer or embed.startswith("*"), seen, unresolved)
+            field_name = receiver_base(embed)[0].rsplit(".", 1)[-1]
+            for method, entry in inner.items():
+                entry = replace(entry, via=(field_name, *entry.via))
+                current = promoted.get(method)
+                if current is None or len(entry.via) < len(current.via):
+                    promoted[method] = entry
+                    ambiguous.discard(method)
+                elif len(entry.via) == len(current.via):
+                    ambiguous.add(method)
+        return {m: entry for m, entry in promoted.items() if m not in ambiguous}
+
+    def _interface_methods(self, package: GoPackage, name: str, seen, unresolved) -> _MethodSet:
+        """Collect an interface's own and embedded methods."""
+        marker = (package.directory, name)
+        symbol = package.interfaces.get(name)
+        if symbol is None or marker in seen:
+            return {}
+        seen = seen | {marker}
+
+        methods: _MethodSet = {}
+        for embed in symbol.embeds:
+            resolved = self.resolve(package, embed)
+            if resolved is None:
+                unresolved.append(embed)
+                continue
+            embed_name = receiver_base(embed)[0].rsplit(".", 1)[-1]
+            for method, entry in self._interface_methods(resolved[0], resolved[1], seen, unresolved).items():
+                methods[method] = replace(entry, via=(embed_name, *entry.via))
+        for method in symbol.children or []:
+            if method.type == "method":
+                methods[method.name] = MethodSetEntry(
+                    name=method.name,
+                    signature=method.signature,
+                    key=method_key(method, package),
+                    symbol=method,
+                    declared_by=name,
+                    package=package.directory,
+                )
+        return methods
+
+
+def _mismatch(got: str, want: str) -> str:
+    """Say which half of two differing signature keys differs."""
+    got_params, got_results = _take_group(got)
+    want_params, want_results = _take_group(want)
+    if got_params != want_params and got_results != want_results:
+        return "the wrong parameter and result types"
+    return "the wrong parameter types" if got_params != want_params else "the wrong result types"
+
+
+def satisfies(type_methods: dict[str, Optional[str]], iface_methods: dict[str, Optional[str]]) -> bool:
+    """Check whether a method set contains every interface method with a matching signature."""
+    for name, key in iface_methods.items():
+        if key is None or type_methods.get(name) != key:
+            return False
+    return True
+
+
+def link_implementations(packages: list[GoPackage]) -> None:
+    """Fill implements/implemented_by on every type and interface in the packages.
+
+    Types in the interface's package are listed by bare name, others as
+    "pkg.Name". implemented_by entries are written "*T" when only the
+    pointer type satisfies the interface.
+    """
+    index = PackageIndex(packages)
+    for package in packages:
+        for symbol in list(package.types.values()) + list(package.interfaces.values()):
+            symbol.implements = []
+            symbol.implemented_by = []
+
+    interfaces = []
+    for package in packages:
+        for name in sorted(package.interfaces):
+            iface_set = index.full_method_set(package, name)
+            if iface_set.unresolved or not iface_set.methods:
+                continue  # Part of the method set is unknown, or every type satisfies it
+            interfaces.append((package, name, {m.name: m.key for m in iface_set.methods}))
+
+    for package in packages:
+        for type_name in sorted(package.types):
+            value_set = index.method_set(package, type_name, pointer=False)
+            pointer_set = index.method_set(package, type_name, pointer=True)
+            for iface_package, iface_name, iface_methods in interfaces:
+                if iface_package is not package and not all(is_exported(m) for m in iface_methods):
+                    continue  # Unexported methods can't be implemented from another package
+                if satisfies(value_set, iface_methods):
+                    form = type_name
+                elif satisfies(pointer_set, iface_methods):
+                    form = f"*{type_name}"
+                else:
+                    continue
+                _record(package, type_name, iface_package, iface_name, form)
+
+
+def _record(package: GoPackage, type_name: str, iface_package: GoPackage, iface_name: str, form: str) -> None:
+    """Record one satisfaction relationship on both symbols."""
+    same = package is iface_package
+    type_ref = form if same else form.replace(type_name, f"{package.name}.{type_name}", 1)
+    iface_ref = iface_name if same else f"{iface_package.name}.{iface_name}"
+    package.types[type_name].implements.append(iface_ref)
+    iface_package.interfaces[iface_name].implemented_by.append(type_ref)
+
+
+def method_set(store: MapStore, path: str, pointer: bool = False) -> Optional[MethodSet]:
+    """Get the method set of a Go type or interface in an index.
+
+    Args:
+        store: Loaded MapStore.
+        path: Path of the type as used by MapStore.lookup, e.g. "sample.DefaultService".
+        pointer: True for the method set of *T, which also has the methods
+            declared with pointer receivers.
+
+    Returns:
+        MethodSet, or None if the path isn't a Go type or interface.
+    """
+    packages = collect_go_packages(store.get_all_files(), store.root)
+    found = _find_type(store, packages, path)
+    if found is None:
+        return None
+    return PackageIndex(packages).full_method_set(found[0], found[1], pointer)
+
+
+def missing_methods(store: MapStore, type_path: str, iface_path: str) -> Optional[list[MethodDiff]]:
+    """Explain why a Go type in an index doesn't implement an interface.
+
+    Args:
+        store: Loaded MapStore.
+        type_path: Path of a concrete type as for method_set; a leading
+            "*" checks the pointer type, e.g. "*sample.DefaultService".
+        iface_path: Path of the interface, e.g. "sample.UserService".
+
+    Returns:
+        Diffs as for PackageIndex.missing_methods, or None if type_path
+        isn't a concrete Go type or iface_path isn't a Go interface.
+    """
+    pointer = type_path.startswith("*")
+    packages = collect_go_packages(store.get_all_files(), store.root)
+    found = _find_type(store, packages, type_path.removeprefix("*"))
+    iface = _find_type(store, packages, iface_path)
+    if found is None or iface is None or found[1] not in found[0].types or iface[1] not in iface[0].interfaces:
+        return None
+    return PackageIndex(packages).missing_methods(found[0], found[1], iface[0], iface[1], pointer)
+
+
+def _find_type(store: MapStore, packages: list[GoPackage], path: str) -> Optional[tuple[GoPackage, str]]:
+    """Find the package and name of the Go type or interface a path names."""
+    match = store.lookup(path)
+    if match is None or match.field is not None:
+        return None
+    for package in packages:
+        if any(rel_path == match.file for rel_path, _ in package.files):
+            name = match.symbol.name
+            if package.types.get(name) is match.symbol or package.interfaces.get(name) is match.symbol:
+                return package, name
+    return None
@@ -0,0 +1,183 @@
+"""Package-level import graph for Go code."""
+
+from __future__ import annotations
+
+from dataclasses import dataclass, field
+from typing import Iterable, Optional
+
+from .go_packages import GoPackage
+from .opaque import boundary_node, opaque_prefix
+
+
+@dataclass
+class ImportGraph:
+    """Import relationships between packages.
+
+    Packages of the module are identified by import path when it is known
+    ("github.com/me/proj/internal/util") and by directory otherwise.
+    Edges point from the importing package to the imported one. Packages
+    under an opaque prefix are merged into one boundary node per prefix,
+    named like "github.com/aws/...", which has no outgoing edges.
+    """
+
+    packages: list[str] = field(default_factory=list)  # Packages in the module, sorted
+    external: list[str] = field(default_factory=list)  # Imported packages outside the module, sorted
+    boundaries: list[str] = field(default_factory=list)  # Opaque boundary nodes, sorted
+    edges: list[tuple[str, str]] = field(default_factory=list)  # Sorted (importer, imported) pairs
+    cycle_edges: set[tuple[str, str]] = field(default_factory=set)  # Edges that are part of an import cycle
+
+    def is_internal(self, node: str) -> bool:
+        return node in self.packages
+
+    def is_boundary(self, node: str) -> bool:
+        return node in self.boundaries
+
+
+def build_import_graph(
+    packages: list[GoPackage], module: Optional[str] = None, opaque: Iterable[str] = ()
+) -> ImportGraph:
+    """Build the import graph of a set of Go packages.
+
+    Args:
+        packages: Packages from collect_go_packages. A directory's external
+            test package (name ending in "_test") is merged into its directory.
+            Packages with a resolved import_path are identified by it, so
+            nested modules get their own paths.
+        module: Module path from go.mod, for packages whose import path
+            isn't resolved. Without either, an import is matched to an
+            indexed directory by its trailing path elements.
+        opaque: Directory or import path prefixes of packages to merge
+            into boundary nodes; see analysis.opaque.
+
+    Returns:
+        ImportGraph with deterministic ordering.
+    """
+    opaque = list(opaque)
+    node_for_dir: dict[str, str] = {}
+    boundary_for: dict[str, str] = {}  # Node -> boundary node it is merged into
+    for package in packages:
+        node = node_for_dir.setdefault(package.directory, _node_id(package, module))
+        prefix = opaque_prefix(opaque, package.directory, node)
+        if prefix is not None:
+            boundary_for[node] = boundary_node(prefix)
+    resolved = {node_for_dir[p.directory] for p in packages if p.import_path_resolved}
+    unresolved = {d: n for d, n in node_for_dir.items() if n not in resolved}
+
+    def merge(node: str) -> str:
+        if node not in boundary_for:
+            prefix = opaque_prefix(opaque, _module_directory(node, module), node)
+            boundary_for[node] = boundary_node(prefix) if prefix is not None else node
+        return boundary_for[node]
+
+    edges: set[tuple[str, str]] = set()
+    external: set[str] = set()
+    internal: set[str] = set(node_for_dir.values())
+    for package in packages:
+        source = node_for_dir[package.directory]
+        if merge(source) != source:
+            continue  # Boundaries are leaves
+        for _, entry in package.files:
+            for imp in entry.imports:
+                target = imp.path if imp.path in resolved else _resolve(imp.path, module, unresolved)
+                if target is None:
+                    external.add(imp.path)
+                    target = imp.path
+                else:
+                    internal.add(target)
+                if target != source:
+                    edges.add((source, merge(target)))
+
+    boundaries = {merge(node) for node in internal | external if merge(node) != node}
+    graph = ImportGraph(
+        packages=sorted(n for n in internal if merge(n) == n),
+        external=sorted(n for n in external if merge(n) == n),
+        boundaries=sorted(boundaries),
+        edges=sorted(edges),
+    )
+    graph.cycle_edges = _cycle_edges(graph.edges, set(graph.packages))
+    return graph
+
+
+def _node_id(package: GoPackage, module: Optional[str]) -> str:
+    """Identify a package by import path, or by directory without one."""
+    if package.import_path_resolved and package.import_path:
+        return package.import_path
+    if module is None:
+        return package.directory
+    return module if package.directory == "." else f"{module}/{package.directory}"
+
+
+def _module_directory(node: str, module: Optional[str]) -> Optional[str]:
+    """Directory of a module package relative to the module root, or None outside the module."""
+    if module is None:
+        return node
+    if node == module:
+        return "."
+    if node.startswith(module + "/"):
+        return node[len(module) + 1:]
+    return None
+
+
+def _resolve(path: str, module: Optional[str], node_for_dir: dict[str, str]) -> Optional[str]:
+    """Map an import path to a package of the module, or None if it is external."""
+    if module is not None:
+        if path == module or path.startswith(module + "/"):
+            return path
+        return None
+    # No module path: pick the indexed directory matching the most trailing elements
+    best = None
+    for directory, node in node_for_dir.items():
+        if directory != "." and (path == directory or path.endswith("/" + directory)):
+            if best is None or len(directory) > len(best[0]):
+                best = (directory, node)
+    return best[1] if best else None
+
+
+def _cycle_edges(edges: list[tuple[str, str]], nodes: set[str]) -> set[tuple[str, str]]:
+    """Find edges whose endpoints are in the same strongly connected component."""
+    adjacency: dict[str, list[str]] = {n: [] for n in nodes}
+    for source, target in edges:
+        if target in adjacency:
+            adjacency[source].append(target)
+
+    # Iterative Tarjan's algorithm, so deep graphs don't hit the recursion limit
+    index: dict[str, int] = {}
+    low: dict[str, int] = {}
+    component: dict[str, int] = {}
+    stack: list[str] = []
+    on_stack: set[str] = set()
+    counter = 0
+    for start in sorted(adjacency):
+        if start in index:
+            continue
+        work = [(start, 0)]
+        while work:
+            node, i = work.pop()
+            if i == 0:
+                index[node] = low[node] = counter
+                counter += 1
+                stack.append(node)
+                on_stack.add(node)
+            if i < len(adjacency[node]):
+                work.append((node, i + 1))
+                succ = adjacency[node][i]
+                if succ not in index:
+                    work.append((succ, 0))
+                elif succ in on_stack:
+                    low[node] = min(low[node], index[succ])
+                continue
+            if low[node] == index[node]:
+                while True:
+                    member = stack.pop()
+                    on_stack.discard(member)
+                    component[member] = index[node]
+                    if member == node:
+                        break
+            if work:
+                parent = work[-1][0]
+                low[parent] = min(low[parent], low[node])
+
+    return {
+        (source, target) for source, target in edges
+        if target in component and component[source] == component[target]
+    }
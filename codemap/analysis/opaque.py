@@ -0,0 +1,56 @@
+"""Go packages indexed as opaque boundaries rather than mapped in full.
+
+Config.opaque lists package prefixes, each a directory relative to the root
+("internal/vendorized") or an import path prefix ("github.com/aws"). A
+package is under a prefix when its directory or import path is the prefix
+or lies below it.
+
+The files of such packages are indexed without symbols, imports or notes,
+keeping only the package clause and its doc comment, so each package is a
+leaf that others can depend on but nothing descends into. References to
+its types keep their resolved_type, the import path of the boundary, and
+embedding one contributes no methods, as for types outside the index. In
+the import graph every package under a prefix, indexed or only imported,
+becomes one boundary node named after the prefix, e.g. "github.com/aws/...".
+"""
+
+from __future__ import annotations
+
+from typing import Iterable, Optional
+
+from ..parsers.base import ParseResult
+
+
+def opaque_prefix(
+    prefixes: Iterable[str], directory: Optional[str] = None, import_path: Optional[str] = None
+) -> Optional[str]:
+    """Find the first prefix a package is under.
+
+    Args:
+        prefixes: Configured prefixes; a trailing "/" is ignored.
+        directory: Package directory relative to the root, if indexed.
+        import_path: Package import path, if known.
+
+    Returns:
+        The matching prefix without a trailing "/", or None.
+    """
+    for prefix in prefixes:
+        prefix = prefix.strip("/")
+        if not prefix:
+            continue
+        for path in (directory, import_path):
+            if path is not None and (path == prefix or path.startswith(prefix + "/")):
+                return prefix
+    return None
+
+
+def boundary_node(prefix: str) -> str:
+    """Name of the import graph node standing in for every package under a prefix."""
+    return f"{prefix}/..."
+
+
+def strip_opaque(result: ParseResult) -> None:
+    """Reduce a parsed Go file of an opaque package to its package clause."""
+    result.symbols = []
+    result.imports = []
+    result.notes = []
@@ -0,0 +1,111 @@
+"""Look up symbols of an index by path or name.
+
+Paths are the ones codemap diff reports: the package name (the directory
+plus name when several directories declare it, or the module path such as
+"codemap.cli" for languages without packages), then the type, then the
+member, e.g. "sample.DefaultService.GetUser" or "sample.User.ID". Go methods
+are placed under their receiver's base type. When two symbols share a path,
+later ones get a "#2", "#3" suffix, e.g. for several init() functions.
+"""
+
+from __future__ import annotations
+
+from dataclasses import dataclass
+from pathlib import PurePosixPath
+from typing import TYPE_CHECKING, Callable, Optional
+
+from ..parsers.base import Field, Symbol
+from ..utils.file_utils import module_path
+from .go_packages import receiver_base
+
+if TYPE_CHECKING:
+    from ..core.map_store import FileEntry, MapStore
+
+
+@dataclass
+class SymbolMatch:
+    """A symbol or struct field found in the index."""
+
+    path: str  # Fully-qualified path, e.g. "sample.DefaultService.GetUser"
+    file: str  # File declaring it, relative to the root
+    symbol: Symbol  # The symbol, or for a field the struct declaring it
+    field: Optional[Field] = None  # Set when the match is a struct field
+
+    @property
+    def name(self) -> str:
+        """Short name: the last element of the path."""
+        return self.path.rsplit(".", 1)[-1].split("#", 1)[0]
+
+
+class SymbolIndex:
+    """Path and name lookups over an index, built once in a single pass."""
+
+    def __init__(self, store: MapStore):
+        self._by_path: dict[str, SymbolMatch] = {}
+        self._by_name: dict[str, list[SymbolMatch]] = {}
+        self._symbols: list[SymbolMatch] = []
+        self._paths: dict[int, str] = {}  # id(symbol) -> path
+        files = list(store.get_all_files())
+        names = _package_names(files)
+        for rel_path, entry in files:
+            qualifier = names.get((_directory(rel_path), entry.package)) or module_path(rel_path)
+            for symbol in entry.symbols:
+                prefix = qualifier
+                if symbol.type == "method" and symbol.receiver:
+                    prefix = f"{qualifier}.{receiver_base(symbol.receiver)[0]}"
+                self._add_symbol(symbol, prefix, rel_path)
+
+    def lookup(self, path: str) -> Optional[SymbolMatch]:
+        """Get the symbol or field at a path like "sample.DefaultService.GetUser"."""
+        return self._by_path.get(path)
+
+    def find(self, predicate: Callable[[Symbol], bool]) -> list[SymbolMatch]:
+        """Get every symbol (not field) the predicate accepts, in file order."""
+        return [m for m in self._symbols if predicate(m.symbol)]
+
+    def find_by_name(self, name: str) -> list[SymbolMatch]:
+        """Get every symbol with this short name across packages, in file order."""
+        return list(self._by_name.get(name, []))
+
+    def path_of(self, symbol: Symbol) -> Optional[str]:
+        """Get the path of a symbol object from the indexed store, or None if it isn't in it."""
+        return self._paths.get(id(symbol))
+
+    def __len__(self) -> int:
+        return len(self._by_path)
+
+    def _add_symbol(self, symbol: Symbol, prefix: str, rel_path: str) -> None:
+        match = self._add(SymbolMatch(path=f"{prefix}.{symbol.name}", file=rel_path, symbol=symbol))
+        self._symbols.append(match)
+        self._paths[id(symbol)] = match.path
+        self._by_name.setdefault(symbol.name, []).append(match)
+        for fld in symbol.fields:
+            name = fld.name or receiver_base(fld.type)[0].rsplit(".", 1)[-1]
+            self._add(SymbolMatch(path=f"{match.path}.{name}", file=rel_path, symbol=symbol, field=fld))
+        for child in symbol.children:
+            self._add_symbol(child, match.path, rel_path)
+
+    def _add(self, match: SymbolMatch) -> SymbolMatch:
+        path, n = match.path, 2
+        while match.path in self._by_path:
+            match.path, n = f"{path}#{n}", n + 1
+        self._by_path[match.path] = match
+        return match
+
+
+def _package_names(files: list[tuple[str, FileEntry]]) -> dict[tuple[str, Optional[str]], str]:
+    """Qualifier per (directory, package); "dir/name" when a name is declared in several directories."""
+    directories: dict[str, set[str]] = {}
+    for rel_path, entry in files:
+        if entry.package:
+            directories.setdefault(entry.package, set()).add(_directory(rel_path))
+    return {
+        (directory, name): name if len(dirs) == 1 else f"{directory}/{name}"
+        for name, dirs in directories.items()
+        for directory in dirs
+    }
+
+
+def _directory(rel_path: str) -> str:
+    return str(PurePosixPath(rel_path).parent)
+
@@ -0,0 +1,119 @@
+"""Symbol counts of an index, overall and per package.
+
+Symbols are counted by type ("struct", "interface", "function", "method",
+"const", ...), nested ones included, so the methods of a Go interface count
+as methods. Packages are grouped as in the JSON export: the files of one
+directory that declare the same package name.
+"""
+
+from __future__ import annotations
+
+from dataclasses import dataclass, field
+from pathlib import PurePosixPath
+from typing import TYPE_CHECKING, Iterator, Optional
+
+from ..parsers.base import Symbol
+
+if TYPE_CHECKING:
+    from ..core.map_store import MapStore
+
+
+@dataclass
+class KindCount:
+    """Number of symbols of one type.
+
+    total includes symbols of languages without an export convention, which
+    count as neither exported nor unexported.
+    """
+
+    total: int = 0
+    exported: int = 0
+    unexported: int = 0
+
+    def add(self, symbol: Symbol) -> None:
+        self.total += 1
+        if symbol.exported is True:
+            self.exported += 1
+        elif symbol.exported is False:
+            self.unexported += 1
+
+
+@dataclass
+class PackageStats:
+    """Symbol counts of one package."""
+
+    name: Optional[str]  # Declared package name; None for languages without one
+    path: str  # Directory relative to the root ("." for the root)
+    files: int = 0
+    kinds: dict[str, KindCount] = field(default_factory=dict)  # Symbol type -> counts
+
+    @property
+    def total(self) -> int:
+        return sum(k.total for k in self.kinds.values())
+
+    @property
+    def exported(self) -> int:
+        return sum(k.exported for k in self.kinds.values())
+
+    @property
+    def unexported(self) -> int:
+        return sum(k.unexported for k in self.kinds.values())
+
+    def add(self, symbol: Symbol) -> None:
+        self.kinds.setdefault(symbol.type, KindCount()).add(symbol)
+
+
+@dataclass
+class MapStats:
+    """Symbol counts of a whole index."""
+
+    files: int = 0
+    kinds: dict[str, KindCount] = field(default_factory=dict)  # Symbol type -> counts
+    packages: list[PackageStats] = field(default_factory=list)  # Sorted by path, then name
+
+    @property
+    def total(self) -> int:
+        return sum(k.total for k in self.kinds.values())
+
+    @property
+    def exported(self) -> int:
+        return sum(k.exported for k in self.kinds.values())
+
+    @property
+    def unexported(self) -> int:
+        return sum(k.unexported for k in self.kinds.values())
+
+    def largest(self, n: int) -> list[PackageStats]:
+        """The n packages with the most symbols, ties broken by path."""
+        return sorted(self.packages, key=lambda p: (-p.total, p.path, p.name or ""))[:n]
+
+
+def map_stats(store: MapStore) -> MapStats:
+    """Count the symbols of an index by type and by package.
+
+    Args:
+        store: Loaded MapStore.
+
+    Returns:
+        Counts for the whole index and each package.
+    """
+    stats = MapStats()
+    packages: dict[tuple[str, Optional[str]], PackageStats] = {}
+    for rel_path, entry in store.get_all_files():
+        directory = str(PurePosixPath(rel_path).parent)
+        package = packages.get((directory, entry.package))
+        if package is None:
+            package = packages[(directory, entry.package)] = PackageStats(name=entry.package, path=directory)
+        stats.files += 1
+        package.files += 1
+        for symbol in _walk(entry.symbols):
+            stats.kinds.setdefault(symbol.type, KindCount()).add(symbol)
+            package.add(symbol)
+    stats.packages = [packages[key] for key in sorted(packages, key=lambda k: (k[0], k[1] or ""))]
+    return stats
+
+
+def _walk(symbols: list[Symbol]) -> Iterator[Symbol]:
+    for symbol in symbols:
+        yield symbol
+        yield from _walk(symbol.children)
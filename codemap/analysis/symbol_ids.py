@@ -0,0 +1,73 @@
+"""Assign stable, fully-qualified IDs to indexed symbols.
+
+An ID is the path codemap diff reports with the package name replaced by
+the import path, so it is unique across modules:
+"example.com/app/sample.DefaultService.GetUser". Go methods are placed
+under their receiver's base type, nested symbols under their parent, and
+an external test package adds "_test" to the import path, as the go
+command does. Without a go.mod the package directory stands in for the
+import path. Languages without packages use the module path of the file,
+e.g. "codemap.cli.Indexer.run".
+
+IDs depend only on where a symbol is declared, not on its line, so they stay
+put as code moves around a file. When a package declares a name more than
+once (several Go init() functions), later ones get a "#2", "#3" suffix in
+file order.
+"""
+
+from __future__ import annotations
+
+from typing import Optional
+
+from ..parsers.base import Symbol
+from ..utils.file_utils import module_path
+from .go_packages import GoPackage, receiver_base
+
+
+def assign_ids(symbols: list[Symbol], qualifier: str, seen: Optional[dict[str, int]] = None) -> None:
+    """Set id on top-level symbols and their children.
+
+    Args:
+        symbols: Top-level symbols, in declaration order.
+        qualifier: Import or module path the IDs start with.
+        seen: IDs already given out in the package, with their counts, so
+            repeated names get a suffix; shared between calls for the
+            files of one package.
+    """
+    seen = {} if seen is None else seen
+    for symbol in symbols:
+        prefix = qualifier
+        if symbol.type == "method" and symbol.receiver:
+            prefix = f"{qualifier}.{receiver_base(symbol.receiver)[0]}"
+        _assign(symbol, prefix, seen)
+
+
+def assign_module_ids(symbols: list[Symbol], rel_path: str) -> None:
+    """Set ids for the symbols of a file in a language without packages."""
+    assign_ids(symbols, module_path(rel_path))
+
+
+def link_ids(packages: list[GoPackage]) -> None:
+    """Set ids on the symbols of every Go package, by import path."""
+    for package in packages:
+        seen: dict[str, int] = {}
+        qualifier = go_qualifier(package)
+        for _, entry in package.files:
+            assign_ids(entry.symbols, qualifier, seen)
+
+
+def go_qualifier(package: GoPackage) -> str:
+    """Import path the IDs of a Go package start with, e.g. "example.com/app/sample"."""
+    path = package.import_path or package.directory
+    if path == ".":
+        path = package.name.removesuffix("_test")
+    return f"{path}_test" if package.is_external_test else path
+
+
+def _assign(symbol: Symbol, prefix: str, seen: dict[str, int]) -> None:
+    path = f"{prefix}.{symbol.name}"
+    count = seen.get(path, 0) + 1
+    seen[path] = count
+    symbol.id = path if count == 1 else f"{path}#{count}"
+    for child in symbol.children:
+        _assign(child, symbol.id, seen)
@@ -0,0 +1,157 @@
+"""Resolve package qualifiers in Go type expressions to import paths.
+
+A parameter, result or struct field typed "*other.Thing" keeps that short
+form in Param.type and Field.type, and gets the form with the import path
+of "other" in resolved_type, e.g. "*github.com/x/other.Thing". Qualifiers
+are looked up in the declaring file's imports:
+
+- An aliased import ("o github.com/x/other") is known by its alias.
+- An import without an alias is known by the package name declared in it
+  when that package is indexed, and otherwise by the name the go tool
+  assumes from the path: "github.com/x/go-yaml/v3" is "yaml".
+- Names brought in by a dot import ("Thing" for `import . "github.com/x/other"`)
+  are resolved when the imported package is indexed and declares the type,
+  or when the file has a single dot import of a package outside the index.
+  Names declared in the file's own package and type parameters win.
+
+resolved_type is None when nothing in the type was resolved, e.g. for
+"int", "*User" or a qualifier that matches no import. Embedded types are
+resolved the same way into Symbol.resolved_embeds, which lines up with
+Symbol.embeds and is left empty when none of them resolved.
+"""
+
+from __future__ import annotations
+
+import re
+from typing import Iterable, Optional
+
+from ..parsers.base import Import, Symbol
+from ..parsers.go_parser import is_exported
+from .go_packages import GoPackage
+
+# A package-qualified name ("other.Thing" in "map[string]*other.Thing"), or an
+# unqualified name in type position: not a field or parameter name ("ID" in
+# "struct{ ID int }") and not an interface method name ("Close()")
+_NAME_RE = re.compile(
+    r"(?<![\w.])([A-Za-z_]\w*)(?:\.([A-Za-z_]\w*)|(?![\w.])(?=\[|\s*(?:[,)\]};]|$)))"
+)
+
+_MAJOR_VERSION_RE = re.compile(r"^v\d+$")
+
+
+def assumed_package_name(path: str) -> str:
+    """Get the package name the go tool assumes for an import path.
+
+    Like golang.org/x/tools' ImportPathToAssumedName: the last element,
+    skipping a major version suffix ("v2"), without a "go-" prefix and cut
+    at the first character that can't be in an identifier.
+    """
+    elements = path.split("/")
+    base = elements[-1]
+    if _MAJOR_VERSION_RE.match(base) and len(elements) > 1:
+        base = elements[-2]
+    if base.startswith("go-"):
+        base = base[3:]
+    match = re.match(r"\w*", base)
+    return match.group(0) if match else base
+
+
+class ImportScope:
+    """The names one Go file's imports bring into its type expressions."""
+
+    def __init__(
+        self,
+        imports: Iterable[Import],
+        local_types: Iterable[str] = (),
+        package_names: Optional[dict[str, str]] = None,
+        package_types: Optional[dict[str, set[str]]] = None,
+    ):
+        """Initialize the scope.
+
+        Args:
+            imports: The file's imports.
+            local_types: Types declared in the file's package, which shadow
+                dot-imported names.
+            package_names: Import path -> declared package name of indexed
+                packages.
+            package_types: Import path -> type names of indexed packages.
+        """
+        package_names = package_names or {}
+        self.package_types = package_types or {}
+        self.local_types = set(local_types)
+        self.qualifiers: dict[str, str] = {}  # Local package name -> import path
+        self.dot_paths: list[str] = []
+        for imp in imports:
+            if imp.name == ".":
+                self.dot_paths.append(imp.path)
+            elif imp.name != "_":
+                name = imp.name or package_names.get(imp.path) or assumed_package_name(imp.path)
+                self.qualifiers.setdefault(name, imp.path)
+
+    def resolve(self, type_text: str, type_params: Iterable[str] = ()) -> Optional[str]:
+        """Get a type expression with import paths for package qualifiers.
+
+        Args:
+            type_text: Type as written, e.g. "[]*other.Thing".
+            type_params: Type parameter names in scope, never dot-imported.
+
+        Returns:
+            The resolved type, or None if nothing in it was resolved.
+        """
+        params = set(type_params)
+        resolved = _NAME_RE.sub(lambda m: self._replace(m, params), type_text)
+        return resolved if resolved != type_text else None
+
+    def _replace(self, match: re.Match, type_params: set[str]) -> str:
+        name, member = match.groups()
+        if member is not None:
+            path = self.qualifiers.get(name)
+            return f"{path}.{member}" if path else match.group(0)
+        if not self.dot_paths or not is_exported(name) or name in type_params or name in self.local_types:
+            return name
+        for path in self.dot_paths:
+            if name in self.package_types.get(path, ()):
+                return f"{path}.{name}"
+        outside = [p for p in self.dot_paths if p not in self.package_types]
+        if len(self.dot_paths) == 1 and outside:
+            return f"{outside[0]}.{name}"
+        return name
+
+
+def resolve_type_refs(packages: list[GoPackage]) -> None:
+    """Fill resolved_type and resolved_embeds on every symbol in the packages.
+
+    Indexed packages are matched to imports by import path, so the packages
+    should come from collect_go_packages with a root.
+    """
+    package_names: dict[str, str] = {}
+    package_types: dict[str, set[str]] = {}
+    for package in packages:
+        if package.import_path_resolved and not package.is_external_test:
+            package_names[package.import_path] = package.name
+            package_types.setdefault(package.import_path, set()).update(package.type_names)
+
+    for package in packages:
+        for _, entry in package.files:
+            scope = ImportScope(entry.imports, package.type_names, package_names, package_types)
+            for symbol in entry.symbols:
+                _resolve_symbol(symbol, scope, set())
+
+
+def _resolve_symbol(symbol: Symbol, scope: ImportScope, type_params: set[str]) -> None:
+    type_params = type_params | {p.name for p in symbol.type_params} | _receiver_params(symbol.receiver)
+    for param in symbol.params + symbol.results:
+        param.resolved_type = scope.resolve(param.type, type_params)
+    for fld in symbol.fields:
+        fld.resolved_type = scope.resolve(fld.type, type_params)
+    resolved_embeds = [scope.resolve(embed, type_params) for embed in symbol.embeds]
+    symbol.resolved_embeds = resolved_embeds if any(resolved_embeds) else []
+    for child in symbol.children:
+        _resolve_symbol(child, scope, type_params)
+
+
+def _receiver_params(receiver: Optional[str]) -> set[str]:
+    """Type parameter names of a generic receiver, e.g. {"K", "V"} for "*Map[K, V]"."""
+    if not receiver or "[" not in receiver:
+        return set()
+    return set(re.findall(r"[A-Za-z_]\w*", receiver.split("[", 1)[1]))
@@ -3,7 +3,7 @@
 from __future__ import annotations
 
 import sys
-from pathlib import Path
+from pathlib import Path, PurePosixPath
 
 import click
 
@@ -28,37 +28,145 @@ def cli():
     multiple=True,
     help="Languages to index (python, typescript, javascript)",
 )
+@click.option(
+    "--include", "-i",
+    multiple=True,
+    help="Only index files matching these patterns (replaces include: in .codemaprc)",
+)
 @click.option(
     "--exclude", "-e",
     multiple=True,
-    help="Additional patterns to exclude",
+    help="Additional patterns to exclude; excludes win over includes",
+)
+@click.option(
+    "--max-depth",
+    type=click.IntRange(min=0),
+    metavar="N",
+    help="Only descend N directory levels below PATH (0: PATH itself only)",
+)
+@click.option("--vendor", is_flag=True, help="Also index vendor/ and node_modules/")
+@click.option("--no-gitignore", is_flag=True, help="Don't skip files matched by .gitignore")
+@click.option("--tests", is_flag=True, help="Also index Go _test.go files")
+@click.option("--goos", help="Target GOOS for Go build constraints (default: $GOOS or the host)")
+@click.option("--goarch", help="Target GOARCH for Go build constraints (default: $GOARCH or the host)")
+@click.option("--tags", help="Comma-separated Go build tags, as for go build -tags")
+@click.option("--exclude-generated", is_flag=True, help="Skip Go files marked 'Code generated ... DO NOT EDIT.'")
+@click.option("--show-skipped", is_flag=True, help="List skipped Go files and why they were skipped")
+@click.option(
+    "--ignore-file",
+    type=click.Path(dir_okay=False),
+    help="Extra ignore file with .gitignore syntax",
+)
+@click.option(
+    "--workers", "-j",
+    type=click.IntRange(min=1),
+    help="Parser processes to use (default: one per CPU)",
+)
+@click.option(
+    "--cache",
+    "cache_dir",
+    type=click.Path(file_okay=False),
+    help="Reuse parse results for unchanged files from this directory",
+)
+@click.option("--notes", is_flag=True, help="Record TODO/FIXME/XXX/HACK comments of Go files")
+@click.option(
+    "--opaque",
+    multiple=True,
+    metavar="PREFIX",
+    help="Index Go packages under this directory or import path prefix without their symbols",
 )
-def init(path: str, lang: tuple[str, ...], exclude: tuple[str, ...]):
+def init(
+    path: str,
+    lang: tuple[str, ...],
+    include: tuple[str, ...],
+    exclude: tuple[str, ...],
+    max_depth: int | None,
+    vendor: bool,
+    no_gitignore: bool,
+    tests: bool,
+    goos: str | None,
+    goarch: str | None,
+    tags: str | None,
+    exclude_generated: bool,
+    show_skipped: bool,
+    ignore_file: str | None,
+    workers: int | None,
+    cache_dir: str | None,
+    notes: bool,
+    opaque: tuple[str, ...],
+):
     """Initialize codemap for a directory.
 
     Scans the directory and creates a .codemap/ folder with structural
     information about all code files, mirroring the project structure.
+    Files matched by .gitignore, vendor directories, and Go test files are
+    skipped, as are Go files excluded by build constraints for the target
+    GOOS/GOARCH and tags, and generated Go files with --exclude-generated.
     """
-    from .core.indexer import Indexer
+    from .core.indexer import GENERATED_REASON, Indexer
+    from .utils.config import load_config
 
     root = Path(path).resolve()
     click.echo(f"Scanning {root}...")
 
     try:
+        config = load_config(root)
+        if vendor:
+            config.include_vendor = True
+        if no_gitignore:
+            config.respect_gitignore = False
+        if tests:
+            config.include_tests = True
+        if goos:
+            config.goos = goos
+        if goarch:
+            config.goarch = goarch
+        if tags is not None:
+            config.build_tags = [t for t in tags.split(",") if t]
+        if exclude_generated:
+            config.exclude_generated = True
+        if ignore_file:
+            config.ignore_file = str(Path(ignore_file).resolve())
+        if workers:
+            config.workers = workers
+        if cache_dir:
+            config.cache_dir = str(Path(cache_dir).resolve())
+        if notes:
+            config.notes = True
+        if opaque:
+            config.opaque = list(opaque)
+
         indexer = Indexer(
             root=root,
             languages=list(lang) if lang else None,
             exclude_patterns=list(exclude) if exclude else None,
+            config=config,
+            include_patterns=list(include) if include else None,
+            max_depth=max_depth,
         )
         result = indexer.index_all()
 
         click.echo(f"Found {result['total_files']} files")
         click.echo(f"Indexed {result['total_symbols']} symbols")
 
+        skipped = result.get("skipped", [])
+        generated = sum(1 for _, reason in skipped if reason == GENERATED_REASON)
+        if len(skipped) > generated:
+            context = indexer.build_context
+            click.echo(
+                f"Skipped {len(skipped) - generated} Go files excluded by build constraints "
+                f"({context.goos}/{context.goarch})"
+            )
+        if generated:
+            click.echo(f"Skipped {generated} generated Go files")
+        if show_skipped:
+            for filepath, reason in skipped:
+                click.echo(f"  - {filepath}: {reason}")
+
         if result.get("errors"):
             click.echo(click.style(f"\nWarnings ({len(result['errors'])}):", fg="yellow"))
-            for filepath, error in result["errors"][:5]:
-                click.echo(f"  - {filepath}: {error}")
+            for error in result["errors"][:5]:
+                click.echo(f"  - {error.path}: {error.error}")
             if len(result["errors"]) > 5:
                 click.echo(f"  ... and {len(result['errors']) - 5} more")
 
@@ -88,11 +196,13 @@ def update(filepath: str | None, update_all: bool):
             click.echo(f"Updated {result['updated']} files")
             if result.get("errors"):
                 click.echo(click.style(f"Errors ({len(result['errors'])}):", fg="red"))
-                for filepath, error in result["errors"]:
-                    click.echo(f"  - {filepath}: {error}")
+                for error in result["errors"]:
+                    click.echo(f"  - {error.path}: {error.error}")
         elif filepath:
             result = indexer.update_file(filepath)
-            if result.get("removed"):
+            if result.get("skipped"):
+                click.echo(f"Skipped {filepath}: {result['skipped']}")
+            elif result.get("removed"):
                 click.echo(f"Removed {filepath} from index")
             else:
                 click.echo(f"Updated {filepath} ({result['symbols_changed']} symbols changed)")
@@ -147,12 +257,19 @@ def find(query: str, symbol_type: str | None):
 
 @cli.command()
 @click.argument("filepath")
-def show(filepath: str):
+@click.option(
+    "--sort", "sort_mode",
+    type=click.Choice(["source", "alpha", "grouped", "complexity"]),
+    help="Symbol order within the file (default: source)",
+)
+def show(filepath: str, sort_mode: str | None):
     """Show structure of a file.
 
     Displays all symbols in the file with their line numbers and types.
     """
+    from .analysis import order_symbols
     from .core.map_store import MapStore
+    from .utils.config import load_config
 
     try:
         store = MapStore.load()
@@ -163,6 +280,10 @@ def show(filepath: str):
             click.echo("Run 'codemap update' to index it.")
             return
 
+        sort_mode = sort_mode or load_config(store.root).sort
+        symbols = order_symbols(store.get_file(filepath).symbols, sort_mode)
+        structure["symbols"] = [s.to_dict() for s in symbols]
+
         click.echo(f"File: {click.style(filepath, fg='blue')} (hash: {structure['hash']})")
         click.echo(f"Lines: {structure['lines']}")
         click.echo(f"Language: {structure['language']}")
@@ -195,14 +316,20 @@ def _print_symbols(symbols: list[dict], indent: int = 0):
             "async_method": "cyan",
         }.get(sym["type"], "white")
 
+        complexity = sym.get("complexity")
         click.echo(
             f"{prefix}- {click.style(sym['name'], fg='white', bold=True)} "
             f"[{click.style(sym['type'], fg=type_color)}] "
             f"L{lines[0]}-{lines[1]}"
+            + (click.style(f"  complexity {complexity}", dim=True) if complexity is not None else "")
+            + (click.style("  stub", fg="yellow") if sym.get("is_stub") else "")
         )
 
-        if sym.get("signature"):
-            click.echo(f"{prefix}  {click.style(sym['signature'], dim=True)}")
+        if sym.get("signature") or sym.get("value"):
+            text = sym.get("signature") or ""
+            if sym.get("value"):
+                text = f"{text} = {sym['value']}".lstrip()
+            click.echo(f"{prefix}  {click.style(text, dim=True)}")
 
         if sym.get("docstring"):
             doc = sym["docstring"]
@@ -210,6 +337,18 @@ def _print_symbols(symbols: list[dict], indent: int = 0):
                 doc = doc[:57] + "..."
             click.echo(f"{prefix}  {click.style(f'# {doc}', fg='bright_black')}")
 
+        for fld in sym.get("fields", []):
+            text = f"{fld['name']} {fld['type']}" if fld.get("name") else f"{fld['type']} (embedded)"
+            if fld.get("tag"):
+                text += f" `{fld['tag']}`"
+            click.echo(f"{prefix}  {click.style('.' + text, dim=True)}")
+
+        if sym.get("enum_values"):
+            values = ", ".join(
+                f"{v['name']}={v.get('int_value', v.get('value', '?'))}" for v in sym["enum_values"]
+            )
+            click.echo(f"{prefix}  {click.style('enum ' + values, dim=True)}")
+
         if sym.get("children"):
             _print_symbols(sym["children"], indent + 1)
 
@@ -330,7 +469,8 @@ def lines(range_spec: str):
     is_flag=True,
     help="Only show errors, not updates",
 )
-def watch(path: str, debounce: float, quiet: bool):
+@click.option("--lang", "-l", multiple=True, help="Only react to files of these languages")
+def watch(path: str, debounce: float, quiet: bool, lang: tuple[str, ...]):
     """Watch directory for changes and update index automatically.
 
     Monitors the directory for file changes and updates the codemap
@@ -341,6 +481,7 @@ def watch(path: str, debounce: float, quiet: bool):
         codemap watch              # Watch current directory
         codemap watch ./src        # Watch specific directory
         codemap watch -d 1.0       # Use 1 second debounce
+        codemap watch -l go        # Only Go files trigger updates
     """
     import signal
     from datetime import datetime
@@ -393,6 +534,7 @@ def watch(path: str, debounce: float, quiet: bool):
             on_update=on_update,
             on_error=on_error,
             debounce_seconds=debounce,
+            languages=list(lang) or None,
         )
     except FileNotFoundError as e:
         click.echo(click.style(f"Error: {e}", fg="red"), err=True)
@@ -430,17 +572,43 @@ def watch(path: str, debounce: float, quiet: bool):
 
 
 @cli.command()
-def stats():
+@click.option(
+    "--top",
+    type=click.IntRange(min=0),
+    default=10,
+    show_default=True,
+    help="Number of largest packages to list",
+)
+@click.option("--package", "package_path", metavar="DIR", help="Only show the counts of the package(s) in DIR")
+def stats(top: int, package_path: str | None):
     """Show statistics about the current codemap.
 
-    Displays file counts, symbol counts, and other metadata.
+    Displays file counts, symbol counts by type (exported and unexported),
+    the largest packages, and other metadata.
+
+    \b
+    Examples:
+        codemap stats
+        codemap stats --top 20
+        codemap stats --package internal/store   # How big is this package?
     """
     from .core.map_store import MapStore
+    from .formatters import format_stats
 
     try:
         store = MapStore.load()
         manifest = store.manifest
 
+        if package_path is not None:
+            wanted = str(PurePosixPath(package_path.replace("\\", "/")))
+            symbol_stats = store.symbol_stats()
+            packages = [p for p in symbol_stats.packages if p.path == wanted]
+            if not packages:
+                click.echo(f"No package indexed in {package_path}")
+                return
+            click.echo("\n\n".join(format_stats(symbol_stats, package=p) for p in packages))
+            return
+
         click.echo(f"\n{click.style('CodeMap Statistics', fg='blue', bold=True)}")
         click.echo("=" * 40)
         click.echo(f"Root: {manifest.root}")
@@ -462,22 +630,11 @@ def stats():
             for lang, count in sorted(lang_counts.items()):
                 click.echo(f"  {lang}: {count}")
 
-        # Count by symbol type
-        type_counts: dict[str, int] = {}
-
-        def count_types(symbols):
-            for sym in symbols:
-                type_counts[sym.type] = type_counts.get(sym.type, 0) + 1
-                if sym.children:
-                    count_types(sym.children)
-
-        for rel_path, entry in store.get_all_files():
-            count_types(entry.symbols)
-
-        if type_counts:
-            click.echo("\nSymbols by type:")
-            for sym_type, count in sorted(type_counts.items(), key=lambda x: -x[1]):
-                click.echo(f"  {sym_type}: {count}")
+        # Count by symbol type, exported and unexported, and by package
+        symbol_stats = store.symbol_stats()
+        if symbol_stats.kinds:
+            click.echo()
+            click.echo(format_stats(symbol_stats, top=top))
 
         # Show directory structure
         if manifest.directories:
@@ -496,5 +653,513 @@ def stats():
         sys.exit(1)
 
 
+@cli.command()
+@click.option(
+    "--kind", "-k",
+    multiple=True,
+    type=click.Choice(["TODO", "FIXME", "XXX", "HACK"], case_sensitive=False),
+    help="Only list these markers",
+)
+@click.option("--author", "-a", help="Only list notes of this author, as in TODO(name)")
+def notes(kind: tuple[str, ...], author: str | None):
+    """List TODO, FIXME, XXX and HACK comments recorded in the index.
+
+    Notes are recorded by 'codemap init --notes' (or notes: true in
+    .codemaprc) and listed as file:line:column.
+
+    \b
+    Examples:
+        codemap notes                  # main.go:5:4: TODO(alice): drop the v1 API
+        codemap notes -k FIXME -k HACK
+        codemap notes --author alice
+    """
+    from .core.map_store import MapStore
+
+    try:
+        store = MapStore.load()
+        kinds = {k.upper() for k in kind}
+        found = [
+            (rel_path, note)
+            for rel_path, note in store.notes()
+            if (not kinds or note.kind in kinds) and (author is None or note.author == author)
+        ]
+
+        if not found:
+            click.echo("No notes found")
+            return
+
+        for rel_path, note in found:
+            marker = f"{note.kind}({note.author})" if note.author else note.kind
+            click.echo(f"{note.pos(rel_path)}: {click.style(marker, fg='yellow')}: {note.text}".rstrip())
+
+    except FileNotFoundError:
+        click.echo(click.style("No codemap found. Run 'codemap init' first.", fg="red"), err=True)
+        sys.exit(1)
+    except Exception as e:
+        click.echo(click.style(f"Error: {e}", fg="red"), err=True)
+        sys.exit(1)
+
+
+@cli.command()
+@click.argument("path")
+@click.option("--pointer", "-p", is_flag=True, help="List the method set of *T instead of T")
+def methods(path: str, pointer: bool):
+    """List the method set of a Go type, promoted methods included.
+
+    PATH is a type as codemap diff names it. Methods promoted through
+    embedded fields show the fields they come through and the type
+    declaring them.
+
+    \b
+    Examples:
+        codemap methods sample.DefaultService
+        codemap methods sample.DefaultService --pointer
+    """
+    from .core.map_store import MapStore
+
+    try:
+        store = MapStore.load()
+        method_set = store.method_set(path, pointer=pointer)
+        if method_set is None:
+            click.echo(click.style(f"Not a Go type: {path}", fg="red"), err=True)
+            sys.exit(1)
+
+        if not method_set.methods:
+            click.echo(f"{method_set.type_name} has no methods")
+        for method in method_set.methods:
+            line = f"{method.name}{method.signature or ''}"
+            if method.promoted:
+                receiver = f"*{method.declared_by}" if method.pointer_receiver else method.declared_by
+                line += click.style(f"  (via {'.'.join(method.via)}, declared on {receiver})", dim=True)
+            click.echo(line)
+        for embed in method_set.unresolved:
+            click.echo(click.style(f"Methods of {embed} not included: not in the index", fg="yellow"))
+
+    except FileNotFoundError:
+        click.echo(click.style("No codemap found. Run 'codemap init' first.", fg="red"), err=True)
+        sys.exit(1)
+    except Exception as e:
+        click.echo(click.style(f"Error: {e}", fg="red"), err=True)
+        sys.exit(1)
+
+
+@cli.command("missing-methods")
+@click.argument("type_path", metavar="TYPE")
+@click.argument("iface_path", metavar="INTERFACE")
+def missing_methods(type_path: str, iface_path: str):
+    """Explain why a Go type doesn't implement an interface.
+
+    TYPE and INTERFACE are paths as codemap diff names them; prefix TYPE
+    with * to check the pointer type. Lists each interface method the type
+    lacks, with methods of the right name but the wrong signature, and
+    methods only the pointer type has. Exits with status 1 if the type
+    doesn't implement the interface.
+
+    \b
+    Examples:
+        codemap missing-methods sample.DefaultService sample.UserService
+        codemap missing-methods '*sample.DefaultService' sample.UserService
+    """
+    from .core.map_store import MapStore
+
+    try:
+        store = MapStore.load()
+        diffs = store.missing_methods(type_path, iface_path)
+        if diffs is None:
+            click.echo(click.style(f"Not a Go type and interface: {type_path}, {iface_path}", fg="red"), err=True)
+            sys.exit(1)
+
+        if not diffs:
+            click.echo(f"{type_path} implements {iface_path}")
+            return
+        if all(d.kind == "pointer_receiver" for d in diffs):
+            click.echo(f"{type_path} doesn't implement {iface_path}; only *{type_path} does")
+        else:
+            click.echo(f"{type_path} doesn't implement {iface_path}:")
+        for diff in diffs:
+            click.echo(f"  {click.style(diff.name, bold=True)}: {diff.detail}")
+        sys.exit(1)
+
+    except FileNotFoundError:
+        click.echo(click.style("No codemap found. Run 'codemap init' first.", fg="red"), err=True)
+        sys.exit(1)
+    except Exception as e:
+        click.echo(click.style(f"Error: {e}", fg="red"), err=True)
+        sys.exit(1)
+
+
+@cli.command()
+@click.option(
+    "--format", "-f", "output_format",
+    type=click.Choice(["json", "jsonl", "markdown", "dot", "mermaid", "xml", "compact"]),
+    default="json",
+    help="Output format (default: json)",
+)
+@click.option(
+    "--output", "-o",
+    type=click.Path(dir_okay=False),
+    help="Write to a file instead of stdout",
+)
+@click.option("--exported-only", is_flag=True, help="Only include exported (public) symbols")
+@click.option("--exclude-deprecated", is_flag=True, help="Leave out symbols and fields marked Deprecated:")
+@click.option(
+    "--collapse-over",
+    type=click.IntRange(min=0),
+    metavar="N",
+    help="Summarize files with more than N symbols as counts per type",
+)
+@click.option(
+    "--min-complexity",
+    type=click.IntRange(min=1),
+    metavar="N",
+    help="Only include functions and methods with a cyclomatic complexity of at least N",
+)
+@click.option(
+    "--sort", "sort_mode",
+    type=click.Choice(["source", "alpha", "grouped", "complexity"]),
+    help="Symbol order within each file (default: source)",
+)
+@click.option("--doc-first-sentence", is_flag=True, help="Keep only the first sentence of doc comments")
+@click.option(
+    "--doc-max-chars",
+    type=click.IntRange(min=1),
+    metavar="N",
+    help="Cut doc comments to N characters",
+)
+@click.option(
+    "--doc-max-lines",
+    type=click.IntRange(min=1),
+    metavar="N",
+    help="Cut doc comments to N lines",
+)
+@click.option("--doc-strip-name", is_flag=True, help="Drop the leading symbol name from doc comments")
+@click.option(
+    "--max-tokens",
+    type=click.IntRange(min=1),
+    help="Drop detail until the output fits this many tokens",
+)
+@click.option("--tokenizer", help="Tokenizer for token estimates (default, cl100k)")
+@click.option("--estimate", is_flag=True, help="Print the estimated token count instead of the export")
+@click.option("--method-sets", is_flag=True, help="json, xml: list each Go interface's methods, embedded ones included")
+@click.option("--internal-only", is_flag=True, help="dot: only draw imports between packages of the module")
+@click.option("--collapse-external", is_flag=True, help="dot: draw packages outside the module as one node")
+def export(
+    output_format: str,
+    output: str | None,
+    exported_only: bool,
+    exclude_deprecated: bool,
+    collapse_over: int | None,
+    min_complexity: int | None,
+    sort_mode: str | None,
+    doc_first_sentence: bool,
+    doc_max_chars: int | None,
+    doc_max_lines: int | None,
+    doc_strip_name: bool,
+    max_tokens: int | None,
+    tokenizer: str | None,
+    estimate: bool,
+    method_sets: bool,
+    internal_only: bool,
+    collapse_external: bool,
+):
+    """Export the codemap as JSON, JSON Lines, XML, Markdown, one line per symbol, a Graphviz import graph, or a Mermaid class diagram.
+
+    \b
+    Examples:
+        codemap export                   # JSON to stdout
+        codemap export -o codemap.json   # JSON to a file
+        codemap export -f jsonl          # One line per file and symbol
+        codemap export -f xml -o codemap.xml
+        codemap export -f markdown -o CODEMAP.md
+        codemap export -f compact        # One line per symbol
+        codemap export --exported-only   # Public API only
+        codemap export --exclude-deprecated
+        codemap export -f markdown --collapse-over 200
+        codemap export -f markdown --sort grouped
+        codemap export --min-complexity 10 --sort complexity
+        codemap export -f markdown --doc-first-sentence --doc-strip-name
+        codemap export -f markdown --max-tokens 8000
+        codemap export --estimate --tokenizer cl100k
+        codemap export -f dot --internal-only | dot -Tsvg > deps.svg
+        codemap export -f mermaid --exported-only -o types.mmd
+    """
+    import functools
+
+    from .analysis import (
+        DocOptions,
+        collapse_large_files,
+        filter_complexity,
+        filter_deprecated,
+        filter_exported,
+        sort_symbols,
+        trim_docs,
+    )
+    from .formatters import FORMATTERS, estimate_tokens, fit_to_budget, render
+    from .utils.config import load_config
+
+    try:
+        store = MapStore.load()
+        config = load_config(store.root)
+        tokenizer = tokenizer or config.tokenizer
+        max_tokens = max_tokens or config.max_tokens
+        options = {}
+        if output_format == "dot":
+            options = {"internal_only": internal_only, "collapse_external": collapse_external}
+        elif output_format in ("json", "xml") and method_sets:
+            options = {"method_sets": True}
+        format_text = functools.partial(FORMATTERS[output_format], **options)
+
+        if exported_only or config.exported_only:
+            store = filter_exported(store)
+        if exclude_deprecated or config.exclude_deprecated:
+            store = filter_deprecated(store)
+        min_complexity = min_complexity or config.min_complexity
+        if min_complexity is not None:
+            store = filter_complexity(store, min_complexity)
+        if collapse_over is None:
+            collapse_over = config.collapse_over
+        if collapse_over is not None:
+            store = collapse_large_files(store, collapse_over)
+        doc_options = DocOptions(
+            first_sentence=doc_first_sentence or config.doc_first_sentence,
+            max_chars=doc_max_chars or config.doc_max_chars,
+            max_lines=doc_max_lines or config.doc_max_lines,
+            strip_name=doc_strip_name or config.doc_strip_name,
+        )
+        if doc_options.active:
+            store = trim_docs(store, doc_options)
+        store = sort_symbols(store, sort_mode or config.sort)
+
+        if not max_tokens and not estimate:
+            # Nothing needs the whole text at once, so it is written as it is rendered
+            if output:
+                with open(output, "w", encoding="utf-8") as out:
+                    render(out, store, output_format, **options)
+                click.echo(f"Exported {output_format} to {output}")
+            else:
+                render(sys.stdout, store, output_format, **options)
+            return
+
+        if max_tokens:
+            rendered, applied = fit_to_budget(store, format_text, max_tokens, tokenizer, config.token_reductions)
+            tokens = estimate_tokens(rendered, tokenizer)
+            if applied:
+                click.echo(f"Reduced to ~{tokens} tokens by dropping: {', '.join(applied)}", err=True)
+            if tokens > max_tokens:
+                click.echo(
+                    click.style(f"Warning: output is ~{tokens} tokens, over the {max_tokens} budget", fg="yellow"),
+                    err=True,
+                )
+        else:
+            rendered = format_text(store)
+
+        if estimate:
+            click.echo(f"~{estimate_tokens(rendered, tokenizer)} tokens ({output_format}, {tokenizer} tokenizer)")
+            return
+
+        if output:
+            Path(output).write_text(rendered + "\n", encoding="utf-8")
+            click.echo(f"Exported {output_format} to {output}")
+        else:
+            click.echo(rendered)
+
+    except FileNotFoundError:
+        click.echo(click.style("No codemap found. Run 'codemap init' first.", fg="red"), err=True)
+        sys.exit(1)
+    except Exception as e:
+        click.echo(click.style(f"Error: {e}", fg="red"), err=True)
+        sys.exit(1)
+
+
+@cli.command()
+@click.argument("path", default=".", type=click.Path(exists=True, file_okay=False))
+@click.option(
+    "--output", "-o",
+    type=click.Path(dir_okay=False),
+    help="Write to a file instead of stdout",
+)
+@click.option("--per-file", is_flag=True, help="One line per file, with its symbols nested")
+@click.option("--lang", "-l", multiple=True, help="Languages to include")
+@click.option("--include", "-i", multiple=True, help="Only stream files matching these patterns")
+@click.option("--exclude", "-e", multiple=True, help="Additional patterns to exclude; excludes win over includes")
+@click.option(
+    "--max-depth",
+    type=click.IntRange(min=0),
+    metavar="N",
+    help="Only descend N directory levels below PATH (0: PATH itself only)",
+)
+@click.option(
+    "--workers", "-j",
+    type=click.IntRange(min=1),
+    help="Parser processes to use (default: one per CPU)",
+)
+def stream(
+    path: str,
+    output: str | None,
+    per_file: bool,
+    lang: tuple[str, ...],
+    include: tuple[str, ...],
+    exclude: tuple[str, ...],
+    max_depth: int | None,
+    workers: int | None,
+):
+    """Parse a tree and write JSON Lines as it goes, without building an index.
+
+    Each line is a self-contained file or symbol record, in file order.
+    Memory stays bounded, so this suits trees too large to index at once.
+    Files are selected as by init, using the .codemaprc in PATH.
+
+    \b
+    Examples:
+        codemap stream > codemap.jsonl
+        codemap stream ./src --per-file -o files.jsonl
+    """
+    from .formatters import stream_jsonl
+    from .utils.config import load_config
+
+    root = Path(path).resolve()
+    config = load_config(root)
+    if lang:
+        config.languages = list(lang)
+    if include:
+        config.include_patterns = list(include)
+    if exclude:
+        config.exclude_patterns.extend(exclude)
+    if max_depth is not None:
+        config.max_depth = max_depth
+    if workers:
+        config.workers = workers
+
+    try:
+        if output:
+            with open(output, "w", encoding="utf-8") as out:
+                result = stream_jsonl(out, root, config, per_file=per_file)
+        else:
+            result = stream_jsonl(sys.stdout, root, config, per_file=per_file)
+    except Exception as e:
+        click.echo(click.style(f"Error: {e}", fg="red"), err=True)
+        sys.exit(1)
+
+    click.echo(f"Streamed {result['total_files']} files, {result['total_symbols']} symbols", err=True)
+    for error in result["errors"]:
+        click.echo(click.style(f"  - {error.path}: {error.error}", fg="yellow"), err=True)
+
+
+@cli.command("package")
+@click.argument("import_path")
+@click.option(
+    "--format", "-f", "output_format",
+    type=click.Choice(["json", "jsonl", "markdown", "dot", "mermaid", "xml", "compact"]),
+    default="json",
+    help="Output format (default: json)",
+)
+@click.option(
+    "--output", "-o",
+    type=click.Path(dir_okay=False),
+    help="Write to a file instead of stdout",
+)
+@click.option("--tests", is_flag=True, help="Also map the package's _test.go files")
+@click.option("--goos", help="Target GOOS (default: $GOOS or the host)")
+@click.option("--goarch", help="Target GOARCH (default: $GOARCH or the host)")
+@click.option("--tags", help="Comma-separated Go build tags, as for go build -tags")
+def package_cmd(
+    import_path: str,
+    output_format: str,
+    output: str | None,
+    tests: bool,
+    goos: str | None,
+    goarch: str | None,
+    tags: str | None,
+):
+    """Map one Go package by import path, without indexing a directory.
+
+    The go command locates the package, so standard library packages and
+    dependencies in the module cache work as well as packages of the module
+    in the current directory. Files are chosen for the build context as go
+    build would. Nothing is written to the package directory.
+
+    \b
+    Examples:
+        codemap package encoding/json -f markdown
+        codemap package github.com/spf13/cobra -o cobra.json
+        codemap package ./internal/api --goos windows
+    """
+    from .core.go_list import load_package
+    from .formatters import render
+    from .utils.config import Config
+
+    config = Config(include_tests=tests, goos=goos, goarch=goarch)
+    if tags is not None:
+        config.build_tags = [t for t in tags.split(",") if t]
+
+    try:
+        store, errors = load_package(import_path, config)
+        if output:
+            with open(output, "w", encoding="utf-8") as out:
+                render(out, store, output_format)
+            click.echo(f"Mapped {import_path} to {output}")
+        else:
+            render(sys.stdout, store, output_format)
+    except Exception as e:
+        click.echo(click.style(f"Error: {e}", fg="red"), err=True)
+        sys.exit(1)
+
+    for error in errors:
+        click.echo(click.style(f"  - {error.path}: {error.error}", fg="yellow"), err=True)
+
+
+@cli.command()
+@click.argument("old", type=click.Path(exists=True))
+@click.argument("new", type=click.Path(exists=True), required=False)
+@click.option("--no-docs", is_flag=True, help="Ignore doc comment changes")
+@click.option("--exit-code", is_flag=True, help="Exit with 1 if any symbol was added, removed, or changed")
+def diff(old: str, new: str | None, no_docs: bool, exit_code: bool):
+    """Compare two code maps symbol by symbol.
+
+    OLD and NEW are JSON exports or indexed project directories; NEW
+    defaults to the current index.
+
+    \b
+    Examples:
+        codemap export -o before.json     # Before the change
+        codemap diff before.json          # Against the current index
+        codemap diff before.json after.json --no-docs --exit-code
+    """
+    from .analysis import diff_documents
+    from .formatters import format_diff
+
+    try:
+        diff_result = diff_documents(_load_document(Path(old)), _load_document(Path(new) if new else None))
+        rendered = format_diff(diff_result, include_docs=not no_docs)
+        if rendered:
+            click.echo(rendered)
+        else:
+            click.echo("No changes")
+
+        if exit_code and (diff_result.has_api_changes or (diff_result.doc_changed and not no_docs)):
+            sys.exit(1)
+
+    except FileNotFoundError:
+        click.echo(click.style("No codemap found. Run 'codemap init' first.", fg="red"), err=True)
+        sys.exit(1)
+    except (ValueError, KeyError) as e:
+        click.echo(click.style(f"Error: not a codemap export: {e}", fg="red"), err=True)
+        sys.exit(1)
+
+
+def _load_document(path: Path | None) -> dict:
+    """Load an export document from a JSON export or an indexed project directory."""
+    import json
+
+    from .formatters import build_document
+
+    if path is None or path.is_dir():
+        return build_document(MapStore.load(path))
+    document = json.loads(path.read_text(encoding="utf-8"))
+    if not isinstance(document, dict) or "packages" not in document:
+        raise ValueError(str(path))
+    return document
+
+
 if __name__ == "__main__":
     cli()
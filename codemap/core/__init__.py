@@ -2,9 +2,20 @@
 
 from .hasher import hash_file, hash_content
 from .map_store import MapStore
-from .indexer import Indexer
+from .indexer import FileError, Indexer
+from .go_list import GoListError, ListedPackage, go_list, load_package
 
-__all__ = ["hash_file", "hash_content", "MapStore", "Indexer"]
+__all__ = [
+    "hash_file",
+    "hash_content",
+    "MapStore",
+    "Indexer",
+    "FileError",
+    "GoListError",
+    "ListedPackage",
+    "go_list",
+    "load_package",
+]
 
 # Optional watcher (requires watchdog)
 try:
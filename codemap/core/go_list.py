@@ -0,0 +1,148 @@
+"""Map a single Go package named by its import path.
+
+The go command locates the package, in the standard library, the module
+cache, GOPATH, or the current module, and picks its files for the build
+context like go/packages would. GOOS, GOARCH and build tags come from the
+config when set there, and otherwise from the environment as for any go
+command. The listed files are parsed into an in-memory index rooted at the
+package directory; nothing is written there, so read-only directories such
+as GOROOT and the module cache work.
+"""
+
+from __future__ import annotations
+
+import json
+import os
+import subprocess
+from dataclasses import dataclass, field, replace
+from pathlib import Path
+from typing import Optional
+
+from ..utils.cancel import CancelToken, DeadlineExceeded, check
+from ..utils.config import Config
+from .indexer import FileError, Indexer
+from .map_store import MapStore
+
+
+class GoListError(RuntimeError):
+    """The go command couldn't be run or couldn't load a package."""
+
+
+@dataclass
+class ListedPackage:
+    """What "go list -json" reports about a package."""
+
+    import_path: str
+    name: str
+    directory: Path
+    go_files: list[str] = field(default_factory=list)  # Non-test files for the build context, cgo ones included
+    test_files: list[str] = field(default_factory=list)  # _test.go files of the package and of package x_test
+    standard: bool = False  # In the standard library
+    module: Optional[str] = None  # Module path; None for the standard library and GOPATH packages
+
+
+def go_list(
+    import_path: str,
+    config: Optional[Config] = None,
+    cwd: Optional[Path] = None,
+    cancel: Optional[CancelToken] = None,
+) -> ListedPackage:
+    """Locate a package and its files with "go list".
+
+    Args:
+        import_path: Import path, e.g. "encoding/json", or a relative
+            package path such as "./internal/api".
+        config: GOOS, GOARCH and build tags to use; unset ones come from
+            the environment.
+        cwd: Directory to run in, which decides the module whose
+            dependencies can be named. Defaults to the current directory.
+        cancel: Optional token checked before go is run; its deadline, if
+            any, is the timeout for the go command.
+
+    Raises:
+        GoListError: If go isn't installed or the package can't be loaded.
+        Cancelled: If cancel fired (DeadlineExceeded when go ran past the
+            deadline).
+    """
+    check(cancel)
+    config = config or Config()
+    command = ["go", "list", "-json"]
+    if config.build_tags:
+        command.append("-tags=" + ",".join(config.build_tags))
+    command.extend(["--", import_path])
+
+    env = dict(os.environ)
+    if config.goos:
+        env["GOOS"] = config.goos
+    if config.goarch:
+        env["GOARCH"] = config.goarch
+
+    timeout = cancel.remaining() if cancel is not None else None
+    try:
+        proc = subprocess.run(command, cwd=cwd, env=env, capture_output=True, text=True, check=False, timeout=timeout)
+    except subprocess.TimeoutExpired as e:
+        raise DeadlineExceeded(f"go list {import_path} ran past the deadline") from e
+    except OSError as e:
+        raise GoListError(f"Could not run go: {e}") from e
+    if proc.returncode != 0:
+        raise GoListError(proc.stderr.strip() or proc.stdout.strip() or f"go list {import_path} failed")
+
+    output = proc.stdout.strip()
+    if not output:
+        raise GoListError(f"go list {import_path} printed nothing")
+    try:
+        data, end = json.JSONDecoder().raw_decode(output)
+    except json.JSONDecodeError as e:
+        raise GoListError(f"Unreadable go list output for {import_path}: {e}") from e
+    if output[end:].strip():
+        raise GoListError(f"{import_path} matches more than one package")
+    if not isinstance(data, dict) or "ImportPath" not in data or "Dir" not in data:
+        raise GoListError(f"go list {import_path} didn't report a package directory")
+    return ListedPackage(
+        import_path=data["ImportPath"],
+        name=data.get("Name", ""),
+        directory=Path(data["Dir"]),
+        go_files=sorted(data.get("GoFiles", []) + data.get("CgoFiles", [])),
+        test_files=sorted(data.get("TestGoFiles", []) + data.get("XTestGoFiles", [])),
+        standard=data.get("Standard", False),
+        module=(data.get("Module") or {}).get("Path"),
+    )
+
+
+def load_package(
+    import_path: str,
+    config: Optional[Config] = None,
+    cwd: Optional[Path] = None,
+    retain_ast: bool = False,
+    cancel: Optional[CancelToken] = None,
+) -> tuple[MapStore, list[FileError]]:
+    """Build an in-memory index of one Go package.
+
+    Args:
+        import_path: Import path, as for go_list.
+        config: Build context as for go_list. include_tests adds the
+            package's test files, and notes, workers and cache_dir apply as
+            for init; include and exclude patterns don't.
+        cwd: Directory to run go in, as for go_list.
+        retain_ast: Keep each file's syntax tree in the returned store, as
+            for Indexer.
+        cancel: Optional token that stops go list and the parsing, as for
+            go_list and Indexer.index_all.
+
+    Returns:
+        (MapStore rooted at the package directory, files that failed to
+        parse), the map holding everything that did, as for Indexer.index_all.
+
+    Raises:
+        GoListError: If go isn't installed or the package can't be loaded.
+        Cancelled: If cancel fired.
+    """
+    config = replace(config or Config(), languages=["go"])
+    package = go_list(import_path, config, cwd, cancel)
+    names = package.go_files + (package.test_files if config.include_tests else [])
+
+    indexer = Indexer(root=package.directory, config=config, retain_ast=retain_ast)
+    indexer.map_store = MapStore.in_memory(package.directory)
+    indexer.map_store.set_metadata(root=str(indexer.root), config=config.to_dict())
+    result = indexer.index_files([package.directory / name for name in names], cancel)
+    return indexer.map_store, result["errors"]
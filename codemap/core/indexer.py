@@ -3,18 +3,285 @@
 from __future__ import annotations
 
 import logging
-from pathlib import Path
-from typing import Optional
-
-from ..parsers.base import Parser, Symbol
+import multiprocessing
+import os
+from collections import deque
+from concurrent.futures import Future, ProcessPoolExecutor
+from concurrent.futures import TimeoutError as FutureTimeout
+from concurrent.futures.process import BrokenProcessPool
+from dataclasses import dataclass
+from pathlib import Path, PurePosixPath
+from typing import Iterator, Optional
+
+from ..analysis import (
+    ModuleResolver,
+    assign_module_ids,
+    collect_go_packages,
+    link_enums,
+    link_ids,
+    link_implementations,
+    link_methods,
+    resolve_type_refs,
+)
+from ..analysis.opaque import opaque_prefix, strip_opaque
+from ..parsers.base import Parser, ParseResult, Symbol
 from ..parsers.python_parser import PythonParser
+from ..parsers.syntax import SyntaxTree
+from ..utils.build_constraints import BuildContext
+from ..utils.cancel import CancelToken, Cancelled, check
 from ..utils.config import Config, load_config
-from ..utils.file_utils import count_lines, discover_files, get_language
-from .hasher import hash_file
+from ..utils.file_utils import count_lines, discover_files, get_language, is_generated_file
+from .hasher import hash_content, hash_file
 from .map_store import MapStore
+from .parse_cache import ParseCache
+from .visitor import SymbolVisitor, visit_symbols
 
 logger = logging.getLogger(__name__)
 
+# Skip reason for generated Go files when Config.exclude_generated is set
+GENERATED_REASON = "generated file"
+
+# Below this many files, starting worker processes costs more than it saves
+PARALLEL_MIN_FILES = 64
+
+# Seconds between checks of a cancel token while waiting for a worker
+CANCEL_POLL_INTERVAL = 0.05
+
+# Most files parsed per worker task. With a few tasks in flight per worker,
+# this bounds how many parsed files wait in memory to be yielded in order.
+PARSE_CHUNK_SIZE = 32
+
+
+def create_parsers() -> dict[str, Parser]:
+    """Create one parser per available language.
+
+    Returns:
+        Mapping of language name to parser instance.
+    """
+    parsers: dict[str, Parser] = {}
+
+    # Python parser (always available)
+    parsers["python"] = PythonParser()
+
+    # TypeScript/JavaScript parsers (optional, requires tree-sitter)
+    try:
+        from ..parsers.typescript_parser import TypeScriptParser
+        parsers["typescript"] = TypeScriptParser()
+    except ImportError:
+        logger.debug("TypeScript parser not available (tree-sitter not installed)")
+
+    try:
+        from ..parsers.javascript_parser import JavaScriptParser
+        parsers["javascript"] = JavaScriptParser()
+    except ImportError:
+        logger.debug("JavaScript parser not available (tree-sitter not installed)")
+
+    # Markdown and YAML parsers (always available)
+    from ..parsers.markdown_parser import MarkdownParser
+    from ..parsers.yaml_parser import YamlParser
+
+    parsers["markdown"] = MarkdownParser()
+    parsers["yaml"] = YamlParser()
+
+    # Kotlin parser (optional, requires tree-sitter)
+    try:
+        from ..parsers.kotlin_parser import KotlinParser
+        parsers["kotlin"] = KotlinParser()
+    except ImportError:
+        logger.debug("Kotlin parser not available (tree-sitter-kotlin not installed)")
+
+    # Swift parser (optional, requires tree-sitter)
+    try:
+        from ..parsers.swift_parser import SwiftParser
+        parsers["swift"] = SwiftParser()
+    except ImportError:
+        logger.debug("Swift parser not available (tree-sitter-swift not installed)")
+
+    # C parser (optional, requires tree-sitter)
+    try:
+        from ..parsers.c_parser import CParser
+        parsers["c"] = CParser()
+    except ImportError:
+        logger.debug("C parser not available (tree-sitter-c not installed)")
+
+    # C++ parser (optional, requires tree-sitter)
+    try:
+        from ..parsers.cpp_parser import CppParser
+        parsers["cpp"] = CppParser()
+    except ImportError:
+        logger.debug("C++ parser not available (tree-sitter-cpp not installed)")
+
+    # HTML parser (optional, requires tree-sitter)
+    try:
+        from ..parsers.html_parser import HtmlParser
+        parsers["html"] = HtmlParser()
+    except ImportError:
+        logger.debug("HTML parser not available (tree-sitter-html not installed)")
+
+    # CSS parser (optional, requires tree-sitter)
+    try:
+        from ..parsers.css_parser import CssParser
+        parsers["css"] = CssParser()
+    except ImportError:
+        logger.debug("CSS parser not available (tree-sitter-css not installed)")
+
+    # PHP parser (optional, requires tree-sitter)
+    try:
+        from ..parsers.php_parser import PHPParser
+        parsers["php"] = PHPParser()
+    except ImportError:
+        logger.debug("PHP parser not available (tree-sitter-php not installed)")
+
+    # Go parser (optional, requires tree-sitter)
+    try:
+        from ..parsers.go_parser import GoParser
+        parsers["go"] = GoParser()
+    except ImportError:
+        logger.debug("Go parser not available (tree-sitter-go not installed)")
+
+    return parsers
+
+
+@dataclass
+class ParsedFile:
+    """Everything extracted from one file, ready to store in the index."""
+
+    rel_path: str
+    hash: str
+    language: str
+    lines: int
+    result: ParseResult
+
+
+def decode_source(raw: bytes) -> str:
+    """Decode file contents for a parser, as parse_path does.
+
+    Non-UTF-8 files are decoded with replacement characters, and newlines
+    are normalized as by read_text().
+    """
+    return raw.decode("utf-8", errors="replace").replace("\r\n", "\n").replace("\r", "\n")
+
+
+def parse_path(
+    filepath: Path, root: Path, parsers: dict[str, Parser], cache: Optional[ParseCache] = None
+) -> Optional[ParsedFile]:
+    """Read and parse one file without touching the index.
+
+    Args:
+        filepath: Path to the file.
+        root: Project root, used for the relative path.
+        parsers: Parsers by language, from create_parsers().
+        cache: Optional parse cache to reuse and store results in.
+
+    Returns:
+        ParsedFile, or None if no parser handles the file's language.
+    """
+    language = get_language(filepath)
+    if not language:
+        return None
+
+    parser = parsers.get(language)
+    if not parser:
+        logger.debug(f"No parser for language {language}")
+        return None
+
+    raw = filepath.read_bytes()
+    key = cache.key(raw, str(filepath), parser) if cache is not None else None
+    result = cache.get(key) if cache is not None else None
+    if result is None:
+        try:
+            result = parser.parse_file(decode_source(raw), str(filepath))
+        except SyntaxError as e:
+            result = ParseResult(symbols=[], error=str(e))
+        if cache is not None:
+            cache.put(key, result)
+
+    # Get relative path
+    try:
+        rel_path = str(filepath.relative_to(root))
+    except ValueError:
+        rel_path = str(filepath)
+
+    return ParsedFile(
+        rel_path=rel_path,
+        hash=hash_content(raw),
+        language=language,
+        lines=count_lines(filepath),
+        result=result,
+    )
+
+
+@dataclass(frozen=True)
+class FileError:
+    """A file that failed to parse, reported alongside the map built from the others.
+
+    When partial is set the file is in the map with whatever its parser
+    recovered around the error (FileEntry.error holds the same message);
+    otherwise it couldn't be read or parsed at all and is left out.
+    """
+
+    path: str  # Relative to the root, with "/" separators
+    error: str
+    partial: bool = False
+
+
+# Parsers and parse cache of a worker process, created once by _init_worker
+_worker_parsers: dict[str, Parser] = {}
+_worker_cache: Optional[ParseCache] = None
+
+
+def _init_worker(cache_dir: Optional[Path] = None) -> None:
+    """Set up the parsers and parse cache of a pool worker process."""
+    global _worker_parsers, _worker_cache
+    _worker_parsers = create_parsers()
+    _worker_cache = ParseCache(cache_dir) if cache_dir is not None else None
+
+
+def _parse_in_worker(files: list[Path], root: Path) -> list[tuple[Optional[ParsedFile], Optional[str]]]:
+    """Parse files in a pool worker, returning errors instead of raising them."""
+    results = []
+    for filepath in files:
+        try:
+            results.append((parse_path(filepath, root, _worker_parsers, _worker_cache), None))
+        except Exception as e:
+            results.append((None, str(e)))
+    return results
+
+
+class _TrackingContext:
+    """Multiprocessing context that remembers the worker processes a pool starts."""
+
+    def __init__(self):
+        self._context = multiprocessing.get_context()
+        self.processes: list = []
+
+    def Process(self, *args, **kwargs):
+        process = self._context.Process(*args, **kwargs)
+        self.processes.append(process)
+        return process
+
+    def __getattr__(self, name: str):
+        return getattr(self._context, name)
+
+
+def _terminate(pool: ProcessPoolExecutor, context: _TrackingContext) -> None:
+    """Stop a pool without waiting for the files its workers are parsing."""
+    pool.shutdown(wait=False, cancel_futures=True)
+    for process in context.processes:
+        process.terminate()
+
+
+def _result(future: Future, cancel: Optional[CancelToken]):
+    """Wait for a worker's result, giving up as soon as cancel fires."""
+    if cancel is None:
+        return future.result()
+    while True:
+        cancel.check()
+        try:
+            return future.result(timeout=CANCEL_POLL_INTERVAL)
+        except FutureTimeout:
+            continue
+
 
 class Indexer:
     """Orchestrates the indexing of a codebase."""
@@ -25,6 +292,10 @@ class Indexer:
         languages: list[str] | None = None,
         exclude_patterns: list[str] | None = None,
         config: Config | None = None,
+        visitors: list[SymbolVisitor] | None = None,
+        include_patterns: list[str] | None = None,
+        max_depth: int | None = None,
+        retain_ast: bool = False,
     ):
         """Initialize the indexer.
 
@@ -33,6 +304,15 @@ class Indexer:
             languages: Optional list of languages to index.
             exclude_patterns: Optional additional exclude patterns.
             config: Optional Config object.
+            visitors: Called for every symbol of each file before it is
+                stored; see core.visitor.
+            include_patterns: Optional globs restricting the indexed files,
+                replacing the configured ones. Exclude patterns still win.
+            max_depth: Optional deepest directory level to index below the
+                root (0 for the root only), replacing the configured one.
+            retain_ast: Keep each stored file's syntax tree in the map
+                store, for MapStore.syntax_tree(). Trees take many times
+                the memory of the index, so this is off by default.
         """
         self.root = root.resolve()
         self.config = config or load_config(self.root)
@@ -42,85 +322,30 @@ class Indexer:
             self.config.languages = list(languages)
         if exclude_patterns:
             self.config.exclude_patterns.extend(exclude_patterns)
+        if include_patterns:
+            self.config.include_patterns = list(include_patterns)
+        if max_depth is not None:
+            self.config.max_depth = max_depth
+
+        self.build_context = BuildContext.from_config(self.config)
+        self.cache = ParseCache(self._cache_dir()) if self.config.cache_dir else None
+        self.visitors = list(visitors or [])
+        self.retain_ast = retain_ast
+        self._modules = ModuleResolver(self.root)
 
         # Use new MapStore that manages .codemap/ directory
         self.map_store = MapStore(self.root)
         self._parsers: dict[str, Parser] = {}
         self._init_parsers()
 
+    def _cache_dir(self) -> Path:
+        """Directory of the parse cache; a relative cache_dir is relative to the root."""
+        directory = Path(self.config.cache_dir).expanduser()
+        return directory if directory.is_absolute() else self.root / directory
+
     def _init_parsers(self) -> None:
         """Initialize language parsers."""
-        # Python parser (always available)
-        self._parsers["python"] = PythonParser()
-
-        # TypeScript/JavaScript parsers (optional, requires tree-sitter)
-        try:
-            from ..parsers.typescript_parser import TypeScriptParser
-            self._parsers["typescript"] = TypeScriptParser()
-        except ImportError:
-            logger.debug("TypeScript parser not available (tree-sitter not installed)")
-
-        try:
-            from ..parsers.javascript_parser import JavaScriptParser
-            self._parsers["javascript"] = JavaScriptParser()
-        except ImportError:
-            logger.debug("JavaScript parser not available (tree-sitter not installed)")
-
-        # Markdown and YAML parsers (always available)
-        from ..parsers.markdown_parser import MarkdownParser
-        from ..parsers.yaml_parser import YamlParser
-
-        self._parsers["markdown"] = MarkdownParser()
-        self._parsers["yaml"] = YamlParser()
-
-        # Kotlin parser (optional, requires tree-sitter)
-        try:
-            from ..parsers.kotlin_parser import KotlinParser
-            self._parsers["kotlin"] = KotlinParser()
-        except ImportError:
-            logger.debug("Kotlin parser not available (tree-sitter-kotlin not installed)")
-
-        # Swift parser (optional, requires tree-sitter)
-        try:
-            from ..parsers.swift_parser import SwiftParser
-            self._parsers["swift"] = SwiftParser()
-        except ImportError:
-            logger.debug("Swift parser not available (tree-sitter-swift not installed)")
-
-        # C parser (optional, requires tree-sitter)
-        try:
-            from ..parsers.c_parser import CParser
-            self._parsers["c"] = CParser()
-        except ImportError:
-            logger.debug("C parser not available (tree-sitter-c not installed)")
-
-        # C++ parser (optional, requires tree-sitter)
-        try:
-            from ..parsers.cpp_parser import CppParser
-            self._parsers["cpp"] = CppParser()
-        except ImportError:
-            logger.debug("C++ parser not available (tree-sitter-cpp not installed)")
-
-        # HTML parser (optional, requires tree-sitter)
-        try:
-            from ..parsers.html_parser import HtmlParser
-            self._parsers["html"] = HtmlParser()
-        except ImportError:
-            logger.debug("HTML parser not available (tree-sitter-html not installed)")
-
-        # CSS parser (optional, requires tree-sitter)
-        try:
-            from ..parsers.css_parser import CssParser
-            self._parsers["css"] = CssParser()
-        except ImportError:
-            logger.debug("CSS parser not available (tree-sitter-css not installed)")
-
-        # PHP parser (optional, requires tree-sitter)
-        try:
-            from ..parsers.php_parser import PHPParser
-            self._parsers["php"] = PHPParser()
-        except ImportError:
-            logger.debug("PHP parser not available (tree-sitter-php not installed)")
+        self._parsers = create_parsers()
 
     @classmethod
     def load_existing(cls, root: Path | None = None) -> "Indexer":
@@ -145,37 +370,76 @@ class Indexer:
         indexer.map_store = MapStore.load(root)
         return indexer
 
-    def index_all(self) -> dict:
+    def index_all(self, cancel: Optional[CancelToken] = None) -> dict:
         """Index all files in the root directory.
 
+        A file that fails to parse never aborts indexing: it is reported in
+        "errors" and the map holds everything else, so a caller wanting a
+        clean run checks that "errors" is empty.
+
+        Args:
+            cancel: Optional token that stops the walk and the parsing
+                promptly when it fires; see utils.cancel.
+
         Returns:
-            Dictionary with indexing statistics.
-        """
-        # Clear any existing codemap
-        self.map_store.clear()
+            Dictionary with indexing statistics; "errors" is a list of
+            FileError in file order.
 
-        # Set metadata
+        Raises:
+            Cancelled: If cancel fired (DeadlineExceeded for a timeout).
+                Nothing is saved, and the previous index is left as it was.
+        """
+        # Build the new map apart from the existing one, which is only
+        # replaced once indexing has finished
+        previous = self.map_store
+        self.map_store = MapStore.in_memory(self.root)
         self.map_store.set_metadata(
             root=str(self.root),
             config=self.config.to_dict(),
         )
 
+        try:
+            files, skipped = self.discover(cancel)
+            result = self.index_files(files, cancel)
+        except BaseException:
+            self.map_store = previous
+            raise
+        self.map_store.replace_on_disk()
+
+        return {**result, "skipped": skipped}
+
+    def index_files(self, files: list[Path], cancel: Optional[CancelToken] = None) -> dict:
+        """Parse files into the map store and link Go packages, without saving.
+
+        Args:
+            files: Files to index, e.g. from discover().
+            cancel: Optional token checked between files, as for parse_files.
+
+        Returns:
+            Dictionary with "total_files", "total_symbols" and "errors", a
+            list of FileError in file order.
+
+        Raises:
+            Cancelled: If cancel fired; the store holds the files parsed so far.
+        """
         total_files = 0
         total_symbols = 0
-        errors = []
-
-        for filepath in discover_files(self.root, self.config):
-            try:
-                symbols = self._index_file(filepath)
-                total_files += 1
-                total_symbols += self._count_symbols(symbols)
-            except Exception as e:
-                logger.warning(f"Failed to index {filepath}: {e}")
-                errors.append((str(filepath), str(e)))
+        errors: list[FileError] = []
+
+        for filepath, parsed, error in self.parse_files(files, cancel):
+            file_error = self.file_error(filepath, parsed, error)
+            if file_error is not None:
+                errors.append(file_error)
+            if error is not None:
+                logger.warning(f"Failed to index {filepath}: {error}")
+                continue
+            total_files += 1
+            if parsed is not None:
+                self._store(parsed)
+                total_symbols += self._count_symbols(parsed.result.symbols)
 
-        # Update stats and save
+        self._link_go_packages()
         self.map_store.update_stats()
-        self.map_store.save()
 
         return {
             "total_files": total_files,
@@ -183,6 +447,123 @@ class Indexer:
             "errors": errors,
         }
 
+    def discover(self, cancel: Optional[CancelToken] = None) -> tuple[list[Path], list[tuple[str, str]]]:
+        """Find the files to index.
+
+        Args:
+            cancel: Optional token checked before each directory is read.
+
+        Returns:
+            (files to parse, (relative path, reason) per skipped Go file).
+
+        Raises:
+            Cancelled: If cancel fired during the walk.
+        """
+        files = []
+        skipped = []
+        for filepath in discover_files(self.root, self.config, cancel=cancel):
+            reason = self._skip_reason(filepath)
+            if reason is not None:
+                skipped.append((self._rel_path(filepath), reason))
+            else:
+                files.append(filepath)
+        return files, skipped
+
+    def _skip_reason(self, filepath: Path) -> Optional[str]:
+        """Explain why a Go file is left out of the index, or return None to index it.
+
+        Files excluded by build constraints are skipped, as are generated
+        files when Config.exclude_generated is set.
+        """
+        if get_language(filepath) != "go":
+            return None
+        reason = self.build_context.skip_reason(filepath)
+        if reason is None and self.config.exclude_generated and is_generated_file(filepath):
+            reason = GENERATED_REASON
+        return reason
+
+    def file_error(
+        self, filepath: Path, parsed: Optional[ParsedFile], error: Optional[str]
+    ) -> Optional[FileError]:
+        """Turn one result of parse_files into a FileError, or None if the file parsed cleanly."""
+        if error is not None:
+            return FileError(Path(self._rel_path(filepath)).as_posix(), error)
+        if parsed is not None and parsed.result.error:
+            return FileError(Path(parsed.rel_path).as_posix(), parsed.result.error, partial=True)
+        return None
+
+    def _rel_path(self, filepath: Path) -> str:
+        try:
+            return str(filepath.relative_to(self.root))
+        except ValueError:
+            return str(filepath)
+
+    def parse_files(
+        self, files: list[Path], cancel: Optional[CancelToken] = None
+    ) -> Iterator[tuple[Path, Optional[ParsedFile], Optional[str]]]:
+        """Parse files, in parallel when there are enough of them.
+
+        Results are yielded in the order of files regardless of which worker
+        finishes first, so the index is reproducible. Only a few chunks of
+        PARSE_CHUNK_SIZE files are in flight at a time, so memory stays
+        bounded however many files there are. Nothing is stored in the index.
+
+        Args:
+            files: Files to parse.
+            cancel: Optional token checked between files and, every
+                CANCEL_POLL_INTERVAL seconds, while waiting for a worker.
+                When it fires, queued work is dropped and the workers
+                are terminated instead of being waited for.
+
+        Yields:
+            (filepath, parsed file or None, error message or None) per file.
+
+        Raises:
+            Cancelled: If cancel fired.
+        """
+        done = 0
+        workers = self.config.workers or os.cpu_count() or 1
+        if workers > 1 and len(files) >= PARALLEL_MIN_FILES:
+            cancelled = False
+            pool = None
+            try:
+                cache_dir = self.cache.directory if self.cache is not None else None
+                context = _TrackingContext()
+                pool = ProcessPoolExecutor(
+                    max_workers=workers, mp_context=context, initializer=_init_worker, initargs=(cache_dir,)
+                )
+                chunksize = max(1, min(PARSE_CHUNK_SIZE, len(files) // (workers * 4)))
+                starts = iter(range(0, len(files), chunksize))
+                pending: deque = deque()
+                while True:
+                    while len(pending) < workers * 2 and (start := next(starts, None)) is not None:
+                        pending.append(pool.submit(_parse_in_worker, files[start:start + chunksize], self.root))
+                    if not pending:
+                        break
+                    for parsed, error in _result(pending.popleft(), cancel):
+                        check(cancel)
+                        yield files[done], parsed, error
+                        done += 1
+                return
+            except Cancelled:
+                cancelled = True
+                raise
+            except (OSError, NotImplementedError, BrokenProcessPool) as e:
+                # No usable process pool (e.g. a sandbox without semaphores)
+                logger.debug(f"Parallel parsing unavailable, parsing the rest serially: {e}")
+            finally:
+                if pool is not None and cancelled:
+                    _terminate(pool, context)
+                elif pool is not None:
+                    pool.shutdown()
+
+        for filepath in files[done:]:
+            check(cancel)
+            try:
+                yield filepath, parse_path(filepath, self.root, self._parsers, self.cache), None
+            except Exception as e:
+                yield filepath, None, str(e)
+
     def _index_file(self, filepath: Path) -> list[Symbol]:
         """Index a single file.
 
@@ -192,45 +573,68 @@ class Indexer:
         Returns:
             List of extracted symbols.
         """
-        language = get_language(filepath)
-        if not language:
-            return []
-
-        parser = self._parsers.get(language)
-        if not parser:
-            logger.debug(f"No parser for language {language}")
+        parsed = parse_path(filepath, self.root, self._parsers, self.cache)
+        if parsed is None:
             return []
+        self._store(parsed)
+        return parsed.result.symbols
 
-        # Read file content
-        try:
-            content = filepath.read_text(encoding="utf-8")
-        except UnicodeDecodeError:
-            # Try with errors='replace' for non-UTF-8 files
-            content = filepath.read_text(encoding="utf-8", errors="replace")
-
-        # Parse symbols
-        try:
-            symbols = parser.parse(content, str(filepath))
-        except SyntaxError as e:
-            logger.warning(f"Syntax error in {filepath}: {e}")
-            symbols = []
-
-        # Get relative path
-        try:
-            rel_path = str(filepath.relative_to(self.root))
-        except ValueError:
-            rel_path = str(filepath)
-
-        # Update map store
+    def is_opaque(self, rel_path: str | Path) -> bool:
+        """Whether a Go file belongs to a package under a Config.opaque prefix; see analysis.opaque."""
+        if not self.config.opaque:
+            return False
+        directory = str(PurePosixPath(Path(rel_path).as_posix()).parent)
+        import_path, _ = self._modules.import_path(directory)
+        return opaque_prefix(self.config.opaque, directory, import_path) is not None
+
+    def _store(self, parsed: ParsedFile) -> None:
+        """Write a parsed file to the map store."""
+        if parsed.result.error:
+            logger.warning(f"Syntax error in {parsed.rel_path}: {parsed.result.error}")
+        opaque = parsed.language == "go" and self.is_opaque(parsed.rel_path)
+        if opaque:
+            strip_opaque(parsed.result)
+        if parsed.language != "go":
+            assign_module_ids(parsed.result.symbols, Path(parsed.rel_path).as_posix())  # Go ones need the package
+        visit_symbols(self.visitors, parsed.result.symbols, Path(parsed.rel_path).as_posix())
         self.map_store.update_file(
-            rel_path=rel_path,
-            hash=hash_file(filepath),
-            language=language,
-            lines=count_lines(filepath),
-            symbols=symbols,
+            rel_path=parsed.rel_path,
+            hash=parsed.hash,
+            language=parsed.language,
+            lines=parsed.lines,
+            symbols=parsed.result.symbols,
+            package=parsed.result.package,
+            error=parsed.result.error,
+            imports=parsed.result.imports,
+            notes=parsed.result.notes if self.config.notes else None,
+            package_doc=parsed.result.package_doc,
+            opaque=opaque,
         )
-
-        return symbols
+        if self.retain_ast and not opaque:
+            self._retain_syntax_tree(parsed)
+
+    def _retain_syntax_tree(self, parsed: ParsedFile) -> None:
+        """Parse a stored file again for its syntax tree, which can't come from a worker or the cache."""
+        parser = self._parsers.get(parsed.language)
+        if parser is None:
+            return
+        filepath = self.root / parsed.rel_path
+        content = decode_source(filepath.read_bytes())
+        tree = parser.syntax_tree(content, str(filepath))
+        if tree is not None:
+            # Encoded again so byte offsets index into the text that was parsed
+            source = content.encode("utf-8")
+            self.map_store.set_syntax_tree(SyntaxTree(Path(parsed.rel_path).as_posix(), parsed.language, source, tree))
+
+    def _link_go_packages(self) -> None:
+        """Recompute cross-file Go analysis, such as methods of types, enums and interface satisfaction."""
+        packages = collect_go_packages(self.map_store.get_all_files(), self.root)
+        if packages:
+            link_ids(packages)
+            link_methods(packages)
+            link_enums(packages)
+            link_implementations(packages)
+            resolve_type_refs(packages)
 
     def _count_symbols(self, symbols: list[Symbol] | None) -> int:
         """Count total symbols including children.
@@ -260,20 +664,19 @@ class Indexer:
         """
         filepath = Path(filepath).resolve()
 
-        if not filepath.exists():
-            # File was deleted, remove from index
-            try:
-                rel_path = str(filepath.relative_to(self.root))
-            except ValueError:
-                rel_path = str(filepath)
-
-            removed = self.map_store.remove_file(rel_path)
+        reason = self._skip_reason(filepath) if filepath.exists() else None
+        if not filepath.exists() or reason is not None:
+            # File was deleted or is now excluded by build constraints, remove from index
+            removed = self.map_store.remove_file(self._rel_path(filepath))
+            if removed and get_language(filepath) == "go":
+                self._link_go_packages()
             self.map_store.update_stats()
             self.map_store.save()
 
             return {
                 "removed": removed,
                 "symbols_changed": 0,
+                "skipped": reason,
             }
 
         # Re-index the file
@@ -287,6 +690,8 @@ class Indexer:
 
             symbols = self._index_file(filepath)
             new_symbol_count = self._count_symbols(symbols)
+            if get_language(filepath) == "go":
+                self._link_go_packages()
 
             self.map_store.update_stats()
             self.map_store.save()
@@ -299,22 +704,45 @@ class Indexer:
             logger.error(f"Failed to update {filepath}: {e}")
             raise
 
+    def remove_files(self, rel_paths: list[str]) -> int:
+        """Remove files from the index, e.g. after their directory was deleted.
+
+        Args:
+            rel_paths: Paths relative to the root; ones not indexed are ignored.
+
+        Returns:
+            Number of files removed.
+        """
+        removed = [p for p in rel_paths if self.map_store.remove_file(p)]
+        if not removed:
+            return 0
+        if any(get_language(Path(p)) == "go" for p in removed):
+            self._link_go_packages()
+        self.map_store.update_stats()
+        self.map_store.save()
+        return len(removed)
+
     def update_all_stale(self) -> dict:
         """Update all stale files.
 
         Returns:
-            Dictionary with update statistics.
+            Dictionary with update statistics; "errors" is a list of
+            FileError, as for index_all.
         """
         stale_files = self.validate_all()
         updated = 0
-        errors = []
+        errors: list[FileError] = []
 
         for filepath in stale_files:
             try:
                 self.update_file(self.root / filepath)
                 updated += 1
             except Exception as e:
-                errors.append((filepath, str(e)))
+                errors.append(FileError(Path(filepath).as_posix(), str(e)))
+                continue
+            entry = self.map_store.get_file(filepath)
+            if entry is not None and entry.error:
+                errors.append(FileError(Path(filepath).as_posix(), entry.error, partial=True))
 
         return {
             "updated": updated,
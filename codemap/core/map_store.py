@@ -2,14 +2,22 @@
 
 from __future__ import annotations
 
+import copy
 import json
 import shutil
+import tempfile
 from dataclasses import dataclass, field
 from datetime import datetime, timezone
 from pathlib import Path
-from typing import Any, Iterator, Optional
+from typing import TYPE_CHECKING, Any, Callable, Iterator, Optional
 
-from ..parsers.base import Symbol
+from ..parsers.base import Import, Note, Symbol
+
+if TYPE_CHECKING:
+    from ..analysis.implements import MethodDiff, MethodSet
+    from ..analysis.query import SymbolIndex, SymbolMatch
+    from ..analysis.stats import MapStats
+    from ..parsers.syntax import SyntaxTree
 
 
 @dataclass
@@ -21,16 +29,38 @@ class FileEntry:
     language: str
     lines: int
     symbols: list[Symbol]
+    package: Optional[str] = None  # Declared package (e.g. Go package clause)
+    package_doc: Optional[str] = None  # Doc comment of the package clause in this file
+    imports: list[Import] = field(default_factory=list)  # Imported packages
+    error: Optional[str] = None  # Parse error; symbols are whatever was recovered
+    collapsed: Optional[dict[str, int]] = None  # Symbol counts by type when the listing was collapsed
+    notes: list[Note] = field(default_factory=list)  # TODO/FIXME/XXX/HACK comments, if recorded
+    opaque: bool = False  # Go package under a Config.opaque prefix, indexed without symbols
 
     def to_dict(self) -> dict:
         """Convert to dictionary for JSON serialization."""
-        return {
+        result = {
             "hash": self.hash,
             "indexed_at": self.indexed_at,
             "language": self.language,
             "lines": self.lines,
             "symbols": [s.to_dict() for s in self.symbols],
         }
+        if self.package:
+            result["package"] = self.package
+        if self.package_doc:
+            result["package_doc"] = self.package_doc
+        if self.imports:
+            result["imports"] = [i.to_dict() for i in self.imports]
+        if self.error:
+            result["error"] = self.error
+        if self.collapsed is not None:
+            result["collapsed"] = dict(self.collapsed)
+        if self.notes:
+            result["notes"] = [n.to_dict() for n in self.notes]
+        if self.opaque:
+            result["opaque"] = True
+        return result
 
     @classmethod
     def from_dict(cls, data: dict) -> "FileEntry":
@@ -41,6 +71,13 @@ class FileEntry:
             language=data["language"],
             lines=data["lines"],
             symbols=[Symbol.from_dict(s) for s in data.get("symbols", [])],
+            package=data.get("package"),
+            package_doc=data.get("package_doc"),
+            imports=[Import.from_dict(i) for i in data.get("imports", [])],
+            error=data.get("error"),
+            collapsed=data.get("collapsed"),
+            notes=[Note.from_dict(n) for n in data.get("notes", [])],
+            opaque=data.get("opaque", False),
         )
 
 
@@ -128,6 +165,9 @@ class MapStore:
         self.codemap_dir = self.root / self.CODEMAP_DIR
         self._manifest: Optional[RootManifest] = None
         self._dir_maps: dict[str, DirectoryMap] = {}  # Cache for directory maps
+        self._symbol_index: Optional[SymbolIndex] = None  # Built by symbol_index(), reset on changes
+        self._in_memory = False  # Never read directory maps from disk
+        self._syntax_trees: dict[str, SyntaxTree] = {}  # Kept by Indexer(retain_ast=True), never saved
 
     @property
     def manifest(self) -> RootManifest:
@@ -163,6 +203,21 @@ class MapStore:
         store._manifest = store._load_manifest()
         return store
 
+    @classmethod
+    def in_memory(cls, root: Path) -> "MapStore":
+        """Create an empty store that ignores any index on disk under root.
+
+        Like a copy(), it is meant to be filled and read; replace_on_disk()
+        saves it in place of the index on disk.
+
+        Args:
+            root: Directory the stored paths are relative to.
+        """
+        store = cls(root)
+        store._manifest = RootManifest()
+        store._in_memory = True
+        return store
+
     def _load_manifest(self) -> RootManifest:
         """Load the root manifest from file.
 
@@ -207,7 +262,7 @@ class MapStore:
             return self._dir_maps[directory]
 
         map_path = self._get_dir_map_path(directory)
-        if not map_path.exists():
+        if self._in_memory or not map_path.exists():
             dir_map = DirectoryMap(directory=directory)
         else:
             try:
@@ -259,6 +314,32 @@ class MapStore:
         # Save manifest
         self.save_manifest()
 
+    def replace_on_disk(self) -> None:
+        """Save the store in place of the index on disk, dropping every file it doesn't hold.
+
+        The new index is written to a temporary directory under the root
+        and renamed into place, so the previous .codemap/ stays whole until
+        the new one is complete. Afterwards the store reads from disk like
+        one that was loaded.
+        """
+        staging = Path(tempfile.mkdtemp(prefix=f"{self.CODEMAP_DIR}-", dir=self.root))
+        target = self.codemap_dir
+        try:
+            self.codemap_dir = staging
+            self.save()
+        except BaseException:
+            shutil.rmtree(staging, ignore_errors=True)
+            raise
+        finally:
+            self.codemap_dir = target
+
+        previous = staging.with_name(staging.name + "-old")
+        if target.exists():
+            target.rename(previous)
+        staging.rename(target)
+        shutil.rmtree(previous, ignore_errors=True)
+        self._in_memory = False
+
     def update_file(
         self,
         rel_path: str,
@@ -266,6 +347,12 @@ class MapStore:
         language: str,
         lines: int,
         symbols: list[Symbol],
+        package: Optional[str] = None,
+        error: Optional[str] = None,
+        imports: Optional[list[Import]] = None,
+        notes: Optional[list[Note]] = None,
+        package_doc: Optional[str] = None,
+        opaque: bool = False,
     ) -> None:
         """Update or add a file entry.
 
@@ -275,6 +362,12 @@ class MapStore:
             language: Programming language.
             lines: Number of lines in file.
             symbols: List of extracted symbols.
+            package: Optional package declared by the file.
+            error: Optional parse error for the file.
+            imports: Optional packages imported by the file.
+            notes: Optional work-marker comments of the file.
+            package_doc: Optional doc comment of the file's package clause.
+            opaque: Whether the file belongs to an opaque Go package.
         """
         # Determine which directory this file belongs to
         path = Path(rel_path)
@@ -291,11 +384,19 @@ class MapStore:
             language=language,
             lines=lines,
             symbols=symbols,
+            package=package,
+            package_doc=package_doc,
+            imports=list(imports or []),
+            error=error,
+            notes=list(notes or []),
+            opaque=opaque,
         )
+        self._syntax_trees.pop(path.as_posix(), None)
 
         # Ensure directory is in the manifest
         if directory not in self.manifest.directories:
             self.manifest.directories.append(directory)
+        self._symbol_index = None
 
     def remove_file(self, rel_path: str) -> bool:
         """Remove a file entry.
@@ -313,6 +414,8 @@ class MapStore:
         dir_map = self._load_dir_map(directory)
         if filename in dir_map.files:
             del dir_map.files[filename]
+            self._symbol_index = None
+            self._syntax_trees.pop(path.as_posix(), None)
 
             # If directory is now empty, remove it from manifest and cache
             if not dir_map.files:
@@ -349,6 +452,29 @@ class MapStore:
         dir_map = self._load_dir_map(directory)
         return dir_map.files.get(filename)
 
+    def syntax_tree(self, rel_path: str) -> Optional[SyntaxTree]:
+        """Get the syntax tree of a file, for analyses the symbols don't cover.
+
+        Trees are only kept by an Indexer created with retain_ast=True, and
+        only until the file is updated or removed; see parsers.syntax.
+
+        Args:
+            rel_path: Relative path to the file.
+
+        Returns:
+            SyntaxTree, or None if no tree was kept for the file.
+        """
+        return self._syntax_trees.get(Path(rel_path).as_posix())
+
+    def syntax_trees(self) -> Iterator[SyntaxTree]:
+        """Iterate over the kept syntax trees, sorted by path."""
+        for rel_path in sorted(self._syntax_trees):
+            yield self._syntax_trees[rel_path]
+
+    def set_syntax_tree(self, tree: SyntaxTree) -> None:
+        """Keep a file's syntax tree in memory until the file is updated or removed."""
+        self._syntax_trees[tree.path] = tree
+
     def get_file_hash(self, rel_path: str) -> Optional[str]:
         """Get the hash of a file.
 
@@ -443,6 +569,95 @@ class MapStore:
         for child in symbol.children:
             yield from self._search_symbol(child, filepath, query, symbol_type)
 
+    def symbol_index(self) -> SymbolIndex:
+        """Get the path and name index of all symbols, building it on first use."""
+        if self._symbol_index is None:
+            from ..analysis.query import SymbolIndex
+
+            self._symbol_index = SymbolIndex(self)
+        return self._symbol_index
+
+    def lookup(self, path: str) -> Optional[SymbolMatch]:
+        """Get the symbol or struct field at a dotted path.
+
+        Args:
+            path: Path like "sample.DefaultService.GetUser" or "sample.User.ID",
+                as shown by codemap diff.
+
+        Returns:
+            SymbolMatch, or None if nothing has that path.
+        """
+        return self.symbol_index().lookup(path)
+
+    def find(self, predicate: Callable[[Symbol], bool]) -> list[SymbolMatch]:
+        """Get every symbol the predicate accepts, in file order."""
+        return self.symbol_index().find(predicate)
+
+    def find_by_name(self, name: str) -> list[SymbolMatch]:
+        """Get every symbol with an exact short name, across all packages."""
+        return self.symbol_index().find_by_name(name)
+
+    def call_graph(self) -> dict[str, list[str]]:
+        """Get the intra-package call graph of Go functions and methods.
+
+        Returns:
+            Caller path -> paths of the functions, methods and interface
+            methods it calls. See analysis.callgraph for how calls are resolved.
+        """
+        from ..analysis.callgraph import call_graph
+
+        return call_graph(self)
+
+    def method_set(self, path: str, pointer: bool = False) -> Optional[MethodSet]:
+        """Get the method set of a Go type, with promoted methods and their origin.
+
+        Args:
+            path: Path of the type, e.g. "sample.DefaultService".
+            pointer: True for the method set of *T instead of T.
+
+        Returns:
+            MethodSet, or None if the path isn't a Go type or interface. See
+            analysis.implements.
+        """
+        from ..analysis.implements import method_set
+
+        return method_set(self, path, pointer)
+
+    def missing_methods(self, type_path: str, iface_path: str) -> Optional[list[MethodDiff]]:
+        """Explain why a Go type doesn't implement an interface.
+
+        Args:
+            type_path: Path of the type, e.g. "sample.DefaultService", or
+                "*sample.DefaultService" for the pointer type.
+            iface_path: Path of the interface, e.g. "sample.UserService".
+
+        Returns:
+            One MethodDiff per method the type lacks, empty if it
+            implements the interface, or None if either path isn't a Go
+            type of the right kind. See analysis.implements.
+        """
+        from ..analysis.implements import missing_methods
+
+        return missing_methods(self, type_path, iface_path)
+
+    def notes(self) -> list[tuple[str, Note]]:
+        """Get the TODO/FIXME/XXX/HACK comments recorded for every file.
+
+        Returns:
+            (relative path, note) pairs sorted by path, then position.
+        """
+        return [(rel_path, note) for rel_path, entry in sorted(self.get_all_files()) for note in entry.notes]
+
+    def symbol_stats(self) -> MapStats:
+        """Count symbols by type, exported and unexported, overall and per package.
+
+        Returns:
+            MapStats; see analysis.stats.
+        """
+        from ..analysis.stats import map_stats
+
+        return map_stats(self)
+
     def update_stats(self) -> None:
         """Update the stats section of the manifest."""
         total_files = 0
@@ -502,12 +717,31 @@ class MapStore:
                 else:
                     yield filename, entry
 
+    def copy(self) -> "MapStore":
+        """Create an in-memory deep copy of the loaded index.
+
+        The copy can be modified (e.g. filtered for export) without affecting
+        this store; it should not be saved over the real index. Syntax trees
+        are shared with this store, not copied.
+
+        Returns:
+            MapStore with all directory maps loaded.
+        """
+        clone = MapStore(self.root)
+        clone._manifest = copy.deepcopy(self.manifest)
+        for directory in self.manifest.directories:
+            clone._dir_maps[directory] = copy.deepcopy(self._load_dir_map(directory))
+        clone._syntax_trees = dict(self._syntax_trees)
+        return clone
+
     def clear(self) -> None:
         """Remove the entire .codemap directory."""
         if self.codemap_dir.exists():
             shutil.rmtree(self.codemap_dir)
         self._manifest = RootManifest()
         self._dir_maps.clear()
+        self._symbol_index = None
+        self._syntax_trees.clear()
 
 
 # Legacy compatibility aliases
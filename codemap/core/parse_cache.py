@@ -0,0 +1,116 @@
+"""On-disk cache of parse results, keyed by file contents.
+
+Each entry holds the ParseResult of one file under a key derived from the
+file's contents, its path, the parser and the codemap version, so an
+unchanged file is not parsed again, even by a fresh `codemap init`.
+
+Options that filter exports (exported_only, exclude_deprecated, token
+budgets) are applied after the index is built and options that choose
+files (languages, build tags, tests, generated files) before parsing, so
+neither changes what a parser returns for a given file and neither is part
+of the key. Notes are always extracted and only dropped when stored. Bump
+CACHE_VERSION when parser output changes without a codemap version bump.
+"""
+
+from __future__ import annotations
+
+import hashlib
+import json
+import logging
+import os
+import tempfile
+from pathlib import Path
+from typing import Optional
+
+from .. import __version__
+from ..parsers.base import Import, Note, Parser, ParseResult, Symbol
+
+logger = logging.getLogger(__name__)
+
+# Format of cache entries and of the key; bump to invalidate existing caches
+CACHE_VERSION = 2
+
+
+class ParseCache:
+    """A directory of cached parse results.
+
+    Entries are written atomically, so parallel workers and concurrent runs
+    can share a directory. Unreadable entries count as misses.
+    """
+
+    def __init__(self, directory: Path):
+        """Initialize the cache.
+
+        Args:
+            directory: Cache directory; created on the first write.
+        """
+        self.directory = directory
+
+    def key(self, content: bytes, path: str, parser: Parser) -> str:
+        """Compute the cache key of a file.
+
+        Args:
+            content: Raw file contents.
+            path: File path given to the parser, which shows in errors and
+                decides e.g. whether a Go file is a test file.
+            parser: Parser that would parse the file.
+        """
+        parts = {
+            "cache": CACHE_VERSION,
+            "codemap": __version__,
+            "parser": f"{type(parser).__module__}.{type(parser).__qualname__}",
+            "path": path,
+            "content": hashlib.sha256(content).hexdigest(),
+        }
+        return hashlib.sha256(json.dumps(parts, sort_keys=True).encode("utf-8")).hexdigest()
+
+    def get(self, key: str) -> Optional[ParseResult]:
+        """Get a cached result, or None on a miss."""
+        try:
+            data = json.loads(self._path(key).read_text(encoding="utf-8"))
+            return ParseResult(
+                symbols=[Symbol.from_dict(s) for s in data["symbols"]],
+                package=data.get("package"),
+                package_doc=data.get("package_doc"),
+                imports=[Import.from_dict(i) for i in data.get("imports", [])],
+                error=data.get("error"),
+                notes=[Note.from_dict(n) for n in data.get("notes", [])],
+            )
+        except FileNotFoundError:
+            return None
+        except (OSError, ValueError, KeyError, TypeError) as e:
+            logger.debug(f"Ignoring unreadable cache entry {key}: {e}")
+            return None
+
+    def put(self, key: str, result: ParseResult) -> None:
+        """Store a result, logging rather than raising if the cache can't be written."""
+        data = {
+            "symbols": [_symbol_to_dict(s) for s in result.symbols],
+            "package": result.package,
+            "package_doc": result.package_doc,
+            "imports": [i.to_dict() for i in result.imports],
+            "error": result.error,
+            "notes": [n.to_dict() for n in result.notes],
+        }
+        path = self._path(key)
+        try:
+            path.parent.mkdir(parents=True, exist_ok=True)
+            fd, tmp = tempfile.mkstemp(dir=path.parent, suffix=".tmp")
+            with os.fdopen(fd, "w", encoding="utf-8") as f:
+                json.dump(data, f)
+            os.replace(tmp, path)
+        except OSError as e:
+            logger.debug(f"Could not write cache entry {key}: {e}")
+
+    def _path(self, key: str) -> Path:
+        return self.directory / key[:2] / f"{key}.json"
+
+
+def _symbol_to_dict(symbol: Symbol) -> dict:
+    """Serialize a symbol without the truncation Symbol.to_dict applies for the index."""
+    data = symbol.to_dict()
+    data["signature"] = symbol.signature
+    data["docstring"] = symbol.docstring
+    data["children"] = [_symbol_to_dict(c) for c in symbol.children]
+    return data
+
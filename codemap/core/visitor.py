@@ -0,0 +1,47 @@
+"""Hooks that enrich symbols as files are indexed.
+
+A visitor is any object with a visit_symbol(symbol, file) method, e.g. one
+that looks up owners in CODEOWNERS or attaches linter findings. It may set
+keys of Symbol.metadata, which is saved in the index and exported; values
+must be JSON-serializable.
+
+Visitors run in the indexing process, never in parser workers, so they need
+not be thread-safe or picklable. Each file is visited once it is parsed, in
+discovery order (each directory's entries by name, subdirectories as they
+come), even when files are parsed in parallel, and its symbols depth first
+in the parser's order:
+a type before its children, then the next top-level symbol. With several
+visitors, all of them see a symbol, in the order given, before the next
+symbol is visited. Symbols from the parse cache are visited like freshly
+parsed ones; metadata is never cached.
+"""
+
+from __future__ import annotations
+
+from typing import Iterable, Protocol
+
+from ..parsers.base import Symbol
+
+
+class SymbolVisitor(Protocol):
+    """Called for each symbol of each indexed file."""
+
+    def visit_symbol(self, symbol: Symbol, file: str) -> None:
+        """Inspect or enrich a symbol.
+
+        Args:
+            symbol: The symbol; set symbol.metadata entries to attach data.
+            file: Path of the declaring file, relative to the root, with
+                forward slashes.
+        """
+
+
+def visit_symbols(visitors: Iterable[SymbolVisitor], symbols: list[Symbol], file: str) -> None:
+    """Run visitors over a file's symbols and their children, depth first."""
+    visitors = list(visitors)
+    if not visitors:
+        return
+    for symbol in symbols:
+        for visitor in visitors:
+            visitor.visit_symbol(symbol, file)
+        visit_symbols(visitors, symbol.children, file)
@@ -3,13 +3,26 @@
 from __future__ import annotations
 
 import logging
+import queue
 import threading
 import time
 from pathlib import Path
-from typing import Callable, Optional
+from typing import TYPE_CHECKING, Callable, Iterator, Optional
 
 from ..utils.config import Config, load_config
-from ..utils.file_utils import get_language, should_exclude
+from ..utils.file_utils import (
+    discover_files,
+    get_language,
+    is_editor_temp_file,
+    is_go_test_file,
+    is_ignored_path,
+    is_included,
+    should_exclude,
+    within_depth,
+)
+
+if TYPE_CHECKING:
+    from .map_store import MapStore
 
 logger = logging.getLogger(__name__)
 
@@ -34,19 +47,24 @@ class CodemapEventHandler(FileSystemEventHandler):
         config: Config,
         on_change: Callable[[Path, str], None],
         debounce_seconds: float = 0.5,
+        on_batch: Optional[Callable[[], None]] = None,
     ):
         """Initialize the event handler.
 
         Args:
             root: Root directory being watched.
             config: CodeMap configuration.
-            on_change: Callback for file changes. Args: (filepath, event_type).
+            on_change: Callback for file changes. Args: (filepath, event_type),
+                where event_type is "created", "modified", "deleted",
+                "dir_created" or "dir_deleted".
             debounce_seconds: Time to wait before processing changes.
+            on_batch: Callback after each debounced batch of changes.
         """
         super().__init__()
         self.root = root
         self.config = config
         self.on_change = on_change
+        self.on_batch = on_batch
         self.debounce_seconds = debounce_seconds
 
         # Debouncing state
@@ -64,33 +82,51 @@ class CodemapEventHandler(FileSystemEventHandler):
             True if file should be processed.
         """
         try:
-            filepath = Path(path)
-
             # Skip directories
-            if filepath.is_dir():
+            if Path(path).is_dir():
                 return False
+            return self._is_tracked(Path(path))
+        except Exception:
+            return False
 
-            # Skip .codemap directory
-            try:
-                rel_path = str(filepath.relative_to(self.root))
-            except ValueError:
-                return False
+    def _is_tracked(self, filepath: Path) -> bool:
+        """Check if a path, which may no longer exist, is a file the index covers."""
+        try:
+            rel_path = str(filepath.relative_to(self.root))
+        except ValueError:
+            return False
 
-            if rel_path.startswith(".codemap"):
-                return False
+        # Skip .codemap directory
+        if rel_path.startswith(".codemap"):
+            return False
 
-            # Check if it's a supported language
-            if get_language(filepath) is None:
-                return False
+        # Check if it's a configured language, not an editor's swap or backup file
+        if get_language(filepath) not in self.config.languages or is_editor_temp_file(rel_path):
+            return False
 
-            # Check exclude patterns
-            if should_exclude(rel_path, self.config.exclude_patterns):
-                return False
+        # Check include and exclude patterns; excludes win
+        if not is_included(rel_path, self.config.include_patterns):
+            return False
+        if should_exclude(rel_path, self.config.exclude_patterns):
+            return False
 
-            return True
-        except Exception:
+        if not self.config.include_tests and is_go_test_file(rel_path):
             return False
 
+        if not within_depth(rel_path, self.config.max_depth):
+            return False
+
+        # Skip files a .gitignore or the configured ignore file leaves out
+        return not is_ignored_path(self.root, filepath.relative_to(self.root).as_posix(), self.config)
+
+    def _is_watched_dir(self, dirpath: Path) -> bool:
+        """Check if a directory may hold indexed files (it is not .codemap or .git)."""
+        try:
+            parts = dirpath.relative_to(self.root).parts
+        except ValueError:
+            return False
+        return bool(parts) and parts[0] != ".codemap" and ".git" not in parts
+
     def _schedule_change(self, filepath: Path, event_type: str) -> None:
         """Schedule a change to be processed after debounce period.
 
@@ -127,9 +163,26 @@ class CodemapEventHandler(FileSystemEventHandler):
             except Exception as e:
                 logger.error(f"Error processing {filepath}: {e}")
 
+        if self.on_batch is not None and changes:
+            try:
+                self.on_batch()
+            except Exception as e:
+                logger.error(f"Error finishing batch: {e}")
+
+    def cancel(self) -> None:
+        """Drop pending changes and stop the debounce timer."""
+        with self._lock:
+            if self._debounce_timer is not None:
+                self._debounce_timer.cancel()
+                self._debounce_timer = None
+            self._pending_changes.clear()
+
     def on_created(self, event: "FileSystemEvent") -> None:
-        """Handle file creation."""
-        if not event.is_directory and self._should_process(event.src_path):
+        """Handle file or directory creation."""
+        if event.is_directory:
+            if self._is_watched_dir(Path(event.src_path)):
+                self._schedule_change(Path(event.src_path), "dir_created")
+        elif self._should_process(event.src_path):
             self._schedule_change(Path(event.src_path), "created")
 
     def on_modified(self, event: "FileSystemEvent") -> None:
@@ -138,42 +191,35 @@ class CodemapEventHandler(FileSystemEventHandler):
             self._schedule_change(Path(event.src_path), "modified")
 
     def on_deleted(self, event: "FileSystemEvent") -> None:
-        """Handle file deletion."""
-        # For deletions, we can't check if it's a supported file anymore
-        # so we'll process it and let the indexer handle it
-        if not event.is_directory:
-            filepath = Path(event.src_path)
-            if get_language(filepath) is not None:
-                try:
-                    rel_path = str(filepath.relative_to(self.root))
-                    # Skip .codemap directory
-                    if rel_path.startswith(".codemap"):
-                        return
-                    if not should_exclude(rel_path, self.config.exclude_patterns):
-                        self._schedule_change(filepath, "deleted")
-                except ValueError:
-                    pass
+        """Handle file or directory deletion."""
+        # The path is gone, so it is checked by name only; the indexer ignores
+        # files it never indexed
+        filepath = Path(event.src_path)
+        if event.is_directory:
+            if self._is_watched_dir(filepath):
+                self._schedule_change(filepath, "dir_deleted")
+        elif self._is_tracked(filepath):
+            self._schedule_change(filepath, "deleted")
 
     def on_moved(self, event: "FileSystemEvent") -> None:
-        """Handle file move/rename."""
+        """Handle file or directory move/rename."""
         # Treat as delete + create
-        if not event.is_directory:
-            # Delete old location
-            src_path = Path(event.src_path)
-            if get_language(src_path) is not None:
-                try:
-                    rel_path = str(src_path.relative_to(self.root))
-                    # Skip .codemap directory
-                    if rel_path.startswith(".codemap"):
-                        return
-                    if not should_exclude(rel_path, self.config.exclude_patterns):
-                        self._schedule_change(src_path, "deleted")
-                except ValueError:
-                    pass
-
-            # Create at new location
-            if self._should_process(event.dest_path):
-                self._schedule_change(Path(event.dest_path), "created")
+        src_path = Path(event.src_path)
+        dest_path = Path(event.dest_path)
+        if event.is_directory:
+            if self._is_watched_dir(src_path):
+                self._schedule_change(src_path, "dir_deleted")
+            if self._is_watched_dir(dest_path):
+                self._schedule_change(dest_path, "dir_created")
+            return
+
+        # Delete old location
+        if self._is_tracked(src_path):
+            self._schedule_change(src_path, "deleted")
+
+        # Create at new location
+        if self._should_process(event.dest_path):
+            self._schedule_change(dest_path, "created")
 
 
 class CodeMapWatcher:
@@ -185,6 +231,8 @@ class CodeMapWatcher:
         on_update: Optional[Callable[[str, int], None]] = None,
         on_error: Optional[Callable[[str, Exception], None]] = None,
         debounce_seconds: float = 0.5,
+        languages: Optional[list[str]] = None,
+        on_map: Optional[Callable[["MapStore"], None]] = None,
     ):
         """Initialize the watcher.
 
@@ -193,6 +241,10 @@ class CodeMapWatcher:
             on_update: Callback when a file is updated. Args: (filepath, symbols_changed).
             on_error: Callback when an error occurs. Args: (filepath, exception).
             debounce_seconds: Time to wait before processing changes.
+            languages: Languages whose files trigger updates; defaults to the
+                configured languages.
+            on_map: Callback after each batch of changes that updated the
+                index, with a copy of the updated map.
 
         Raises:
             ImportError: If watchdog is not installed.
@@ -207,10 +259,13 @@ class CodeMapWatcher:
         self.root = root.resolve()
         self.on_update = on_update
         self.on_error = on_error
+        self.on_map = on_map
         self.debounce_seconds = debounce_seconds
 
         # Load config and indexer
         self.config = load_config(self.root)
+        if languages:
+            self.config.languages = list(languages)
 
         # Import here to avoid circular imports
         from .indexer import Indexer
@@ -219,8 +274,13 @@ class CodeMapWatcher:
 
         # Set up observer
         self._observer: Optional["Observer"] = None
+        self._handler: Optional[CodemapEventHandler] = None
         self._running = False
 
+        # Changes of the batch being processed
+        self._created_dirs: list[Path] = []
+        self._changed = False
+
     def _handle_change(self, filepath: Path, event_type: str) -> None:
         """Handle a file change event.
 
@@ -234,17 +294,27 @@ class CodeMapWatcher:
             rel_path = str(filepath)
 
         try:
-            if event_type == "deleted":
+            if event_type == "dir_created":
+                # Files moved in with a directory get no events of their own
+                self._created_dirs.append(filepath)
+            elif event_type == "dir_deleted":
+                prefix = rel_path.replace("\\", "/") + "/"
+                indexed = [p for p, _ in self.indexer.map_store.get_all_files() if p.startswith(prefix)]
+                if self.indexer.remove_files(indexed):
+                    self._changed = True
+                    if self.on_update:
+                        for removed_path in indexed:
+                            self.on_update(removed_path, 0)
+            elif event_type == "deleted":
                 # Remove from index
-                removed = self.indexer.map_store.remove_file(rel_path)
-                if removed:
-                    self.indexer.map_store.update_stats()
-                    self.indexer.map_store.save()
+                if self.indexer.remove_files([rel_path]):
+                    self._changed = True
                     if self.on_update:
                         self.on_update(rel_path, 0)
             else:
                 # Update file (created or modified)
                 result = self.indexer.update_file(filepath)
+                self._changed = True
                 if self.on_update:
                     self.on_update(rel_path, result.get("symbols_changed", 0))
 
@@ -253,31 +323,47 @@ class CodeMapWatcher:
             if self.on_error:
                 self.on_error(rel_path, e)
 
+    def _finish_batch(self) -> None:
+        """Index the files of new directories and report the updated map."""
+        created, self._created_dirs = self._created_dirs, []
+        if created:
+            for filepath in discover_files(self.root, self.config):
+                if any(filepath.is_relative_to(d) for d in created) and not is_editor_temp_file(filepath.name):
+                    self._handle_change(filepath, "created")
+
+        changed, self._changed = self._changed, False
+        if changed and self.on_map:
+            self.on_map(self.indexer.map_store.copy())
+
     def start(self) -> None:
         """Start watching for changes."""
         if self._running:
             return
 
-        handler = CodemapEventHandler(
+        self._handler = CodemapEventHandler(
             root=self.root,
             config=self.config,
             on_change=self._handle_change,
             debounce_seconds=self.debounce_seconds,
+            on_batch=self._finish_batch,
         )
 
         self._observer = Observer()
-        self._observer.schedule(handler, str(self.root), recursive=True)
+        self._observer.schedule(self._handler, str(self.root), recursive=True)
         self._observer.start()
         self._running = True
 
     def stop(self) -> None:
-        """Stop watching for changes."""
+        """Stop watching for changes, dropping changes still being debounced."""
         if not self._running or self._observer is None:
             return
 
         self._observer.stop()
         self._observer.join(timeout=5)
         self._observer = None
+        if self._handler is not None:
+            self._handler.cancel()
+            self._handler = None
         self._running = False
 
     @property
@@ -320,3 +406,62 @@ def watch_directory(
     )
     watcher.start()
     return watcher
+
+
+def watch_maps(
+    root: Path,
+    debounce_seconds: float = 0.5,
+    languages: Optional[list[str]] = None,
+) -> tuple[Iterator["MapStore"], Callable[[], None]]:
+    """Watch a directory and get the updated map after every change.
+
+    Changes within the debounce period, like a burst of saves, yield a
+    single map. Each map is an in-memory copy that later updates don't touch.
+
+    Example:
+        maps, cancel = watch_maps(Path("."), languages=["go"])
+        for store in maps:  # Blocks until the next change; ends on cancel()
+            render(store)
+
+    Args:
+        root: Root directory to watch; it must already be indexed.
+        debounce_seconds: Time to wait for more changes before updating.
+        languages: Languages whose files trigger updates; defaults to the
+            configured languages.
+
+    Returns:
+        (maps, cancel): an iterator of updated maps, and a function that
+        stops watching and ends the iterator. Calling cancel more than once
+        is harmless.
+
+    Raises:
+        ImportError: If watchdog is not installed.
+        FileNotFoundError: If no codemap exists.
+    """
+    updates: "queue.Queue[Optional[MapStore]]" = queue.Queue()
+    closed = threading.Event()
+    lock = threading.Lock()
+
+    def on_map(store: "MapStore") -> None:
+        if not closed.is_set():
+            updates.put(store)
+
+    watcher = CodeMapWatcher(root=root, debounce_seconds=debounce_seconds, languages=languages, on_map=on_map)
+
+    def cancel() -> None:
+        with lock:
+            if closed.is_set():
+                return
+            closed.set()
+        watcher.stop()
+        updates.put(None)
+
+    def maps() -> Iterator["MapStore"]:
+        while True:
+            store = updates.get()
+            if store is None:
+                return
+            yield store
+
+    watcher.start()
+    return maps(), cancel
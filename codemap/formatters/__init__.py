@@ -0,0 +1,91 @@
+"""Output formatters that render a codemap index for other tools."""
+
+from ..core.map_store import MapStore
+from .budget import DEFAULT_REDUCTIONS, estimate_tokens, fit_to_budget
+from .compact_formatter import compact_line, format_compact, write_compact
+from .diff_formatter import format_diff
+from .dot_formatter import format_dot
+from .json_formatter import SCHEMA_VERSION, build_document, format_json, write_json
+from .jsonl_formatter import format_jsonl, jsonl_records, stream_jsonl, write_jsonl
+from .markdown_formatter import format_markdown, write_markdown
+from .mermaid_formatter import format_mermaid
+from .stats_formatter import format_stats
+from .writer import ShortWriteError, TextSink, TextStream, writer_for
+from .xml_formatter import NAMESPACE as XML_NAMESPACE
+from .xml_formatter import format_xml, load_xml, write_xml
+
+__all__ = [
+    "SCHEMA_VERSION",
+    "build_document",
+    "format_json",
+    "format_jsonl",
+    "jsonl_records",
+    "stream_jsonl",
+    "format_markdown",
+    "format_compact",
+    "compact_line",
+    "FORMATTERS",
+    "DEFAULT_REDUCTIONS",
+    "estimate_tokens",
+    "fit_to_budget",
+    "format_diff",
+    "format_dot",
+    "format_mermaid",
+    "format_stats",
+    "format_xml",
+    "load_xml",
+    "XML_NAMESPACE",
+    "WRITERS",
+    "render",
+    "write_json",
+    "write_jsonl",
+    "write_markdown",
+    "write_compact",
+    "write_xml",
+    "ShortWriteError",
+    "TextSink",
+    "TextStream",
+]
+
+# Format name -> formatter taking a MapStore and returning the rendered text
+FORMATTERS = {
+    "json": format_json,
+    "jsonl": format_jsonl,
+    "markdown": format_markdown,
+    "dot": format_dot,
+    "mermaid": format_mermaid,
+    "xml": format_xml,
+    "compact": format_compact,
+}
+
+# Format name -> writer taking a text stream and a MapStore, writing the
+# formatter's output followed by a newline; empty line-per-record exports
+# (jsonl, compact) write nothing
+WRITERS = {
+    "json": write_json,
+    "jsonl": write_jsonl,
+    "markdown": write_markdown,
+    "dot": writer_for(format_dot),
+    "mermaid": writer_for(format_mermaid),
+    "xml": write_xml,
+    "compact": write_compact,
+}
+
+
+def render(out: TextStream, store: MapStore, output_format: str = "json", **options) -> None:
+    """Write the index to a stream in one of the export formats.
+
+    Args:
+        out: Text stream, e.g. an open file or sys.stdout; see formatters.writer.
+        store: Loaded MapStore.
+        output_format: Key of WRITERS.
+        **options: Options of that format's formatter, e.g. method_sets
+            for json and xml, or internal_only for dot.
+
+    Raises:
+        ValueError: If the format is unknown.
+        OSError: If writing or flushing fails, after whatever was written before.
+    """
+    if output_format not in WRITERS:
+        raise ValueError(f"Unknown format {output_format!r}; expected one of {', '.join(WRITERS)}")
+    WRITERS[output_format](out, store, **options)
@@ -0,0 +1,171 @@
+"""Token estimates and token budgets for rendered exports.
+
+The estimates are heuristics, not exact tokenizer counts, but are close
+enough to decide whether an export fits an LLM context window.
+
+When an export is over budget, detail is dropped in stages until it fits.
+The default order removes what an LLM needs least first:
+
+1. ``docs``: doc comments
+2. ``unexported``: unexported symbols (see analysis.exported)
+3. ``signatures``: parameter and result lists
+4. ``members``: methods, nested symbols, and struct fields
+
+Each stage is cumulative and the order can be changed by passing a
+different list of stage names.
+"""
+
+from __future__ import annotations
+
+import math
+import re
+from typing import Callable, Iterator
+
+from ..analysis.exported import filter_exported
+from ..core.map_store import MapStore
+from ..parsers.base import Symbol
+
+# Approximation of the cl100k pre-tokenizer: contractions, letter runs,
+# up to three digits, punctuation runs, and whitespace
+_CL100K_RE = re.compile(
+    r"'(?:[sdmt]|ll|ve|re)|[^\r\n\w]?[^\W\d_]+|\d{1,3}| ?[^\s\w]+[\r\n]*|\s*[\r\n]+|\s+",
+    re.IGNORECASE,
+)
+
+# Tokenizer names accepted by estimate_tokens, including common aliases
+TOKENIZERS = {
+    "default": "default",
+    "cl100k": "cl100k",
+    "cl100k_base": "cl100k",
+    "gpt-4": "cl100k",
+    "gpt-3.5-turbo": "cl100k",
+}
+
+DEFAULT_REDUCTIONS = ["docs", "unexported", "signatures", "members"]
+
+
+def estimate_tokens(text: str, model: str = "default") -> int:
+    """Estimate how many tokens a text uses.
+
+    Args:
+        text: Rendered text.
+        model: Tokenizer or model name (see TOKENIZERS). "default" assumes
+            about four characters per token, which suits most BPE tokenizers.
+
+    Returns:
+        Estimated token count.
+
+    Raises:
+        ValueError: If the tokenizer is unknown.
+    """
+    tokenizer = TOKENIZERS.get(model.lower())
+    if tokenizer is None:
+        raise ValueError(f"Unknown tokenizer: {model} (expected one of {', '.join(TOKENIZERS)})")
+    if not text:
+        return 0
+    if tokenizer == "default":
+        return math.ceil(len(text) / 4)
+    return sum(_cl100k_piece_tokens(piece) for piece in _CL100K_RE.findall(text))
+
+
+def _cl100k_piece_tokens(piece: str) -> int:
+    """Estimate tokens for one pre-tokenized piece.
+
+    Short words are usually a single token; longer identifiers split into
+    roughly seven-character chunks, and punctuation runs into pairs.
+    """
+    stripped = piece.strip()
+    if not stripped:
+        return 1
+    if stripped[-1].isalpha():
+        return 1 + (len(stripped) - 1) // 7
+    if stripped.isdigit():
+        return 1
+    return math.ceil(len(stripped) / 2)
+
+
+def fit_to_budget(
+    store: MapStore,
+    render: Callable[[MapStore], str],
+    max_tokens: int,
+    model: str = "default",
+    reductions: list[str] | None = None,
+) -> tuple[str, list[str]]:
+    """Render an index, dropping detail until it fits a token budget.
+
+    Args:
+        store: Loaded MapStore. It is not modified.
+        render: Formatter, e.g. format_markdown.
+        max_tokens: Token budget for the rendered output.
+        model: Tokenizer used for the estimate.
+        reductions: Reduction stages in the order to apply them;
+            defaults to DEFAULT_REDUCTIONS.
+
+    Returns:
+        (rendered text, names of the reductions applied). The text may still
+        exceed the budget if every reduction has been applied.
+
+    Raises:
+        ValueError: If a reduction name is unknown.
+    """
+    stages = list(DEFAULT_REDUCTIONS if reductions is None else reductions)
+    unknown = [name for name in stages if name not in REDUCTIONS]
+    if unknown:
+        raise ValueError(f"Unknown reduction: {', '.join(unknown)} (expected one of {', '.join(REDUCTIONS)})")
+
+    rendered = render(store)
+    applied: list[str] = []
+    for name in stages:
+        if estimate_tokens(rendered, model) <= max_tokens:
+            break
+        if not applied:
+            store = store.copy()
+        store = REDUCTIONS[name](store)
+        applied.append(name)
+        rendered = render(store)
+    return rendered, applied
+
+
+def _walk(symbols: list[Symbol]) -> Iterator[Symbol]:
+    """Yield symbols and their nested children."""
+    for symbol in symbols:
+        yield symbol
+        yield from _walk(symbol.children)
+
+
+def _drop_docs(store: MapStore) -> MapStore:
+    for _, entry in store.get_all_files():
+        for symbol in _walk(entry.symbols):
+            symbol.docstring = None
+    return store
+
+
+def _drop_unexported(store: MapStore) -> MapStore:
+    return filter_exported(store)
+
+
+def _drop_signatures(store: MapStore) -> MapStore:
+    for _, entry in store.get_all_files():
+        for symbol in _walk(entry.symbols):
+            symbol.signature = None
+            symbol.params = []
+            symbol.results = []
+    return store
+
+
+def _drop_members(store: MapStore) -> MapStore:
+    for _, entry in store.get_all_files():
+        entry.symbols = [s for s in entry.symbols if not s.receiver]
+        for symbol in entry.symbols:
+            symbol.children = []
+            symbol.fields = []
+    return store
+
+
+# Reduction name -> function taking a store copy and returning the reduced store
+REDUCTIONS: dict[str, Callable[[MapStore], MapStore]] = {
+    "docs": _drop_docs,
+    "unexported": _drop_unexported,
+    "signatures": _drop_signatures,
+    "members": _drop_members,
+}
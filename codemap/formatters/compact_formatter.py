@@ -0,0 +1,149 @@
+"""Compact export: one line per symbol, for the densest faithful listing.
+
+Each file gets a header line ("// svc/user.go (package svc)") followed by
+one line per top-level symbol, with no doc comments and no blank lines.
+Go symbols are written as declarations:
+
+    type User struct { ID int `json:"id"`; Name string }
+    type Store interface { io.Closer; Get(id int) (*User, error) }
+    func (*DefaultService) GetUser(id int) (*User, error)
+    const MaxUsers int = 100
+
+Receiver names aren't indexed, so methods show only the receiver type,
+which is still valid Go. Symbols of other languages are written as their
+kind, name and signature, with members inside braces:
+"class App { run(self) -> None; stop(self) }". Values spanning several
+lines are joined onto one. Files collapsed by collapse_large_files get
+their counts on the header line instead of symbol lines.
+"""
+
+from __future__ import annotations
+
+import re
+from typing import Iterator, Optional
+
+from ..core.map_store import FileEntry, MapStore
+from ..parsers.base import Field, Symbol
+from .writer import TextStream, render_to_string, text_sink
+
+# Line breaks and the indentation around them, joined into one space
+_BREAK_RE = re.compile(r"\s*\n\s*")
+
+_METHOD_KINDS = {"method", "async_method"}
+
+
+def format_compact(store: MapStore) -> str:
+    """Render the index as one line per symbol.
+
+    Args:
+        store: Loaded MapStore.
+
+    Returns:
+        Lines for every file, sorted by path; symbols in index order.
+    """
+    return render_to_string(write_compact, store)
+
+
+def write_compact(out: TextStream, store: MapStore) -> None:
+    """Write the lines of format_compact to a stream, each ending in a newline.
+
+    Args:
+        out: Text stream; see formatters.writer.
+        store: Loaded MapStore.
+
+    Raises:
+        OSError: If writing or flushing fails.
+    """
+    sink = text_sink(out)
+    sink.write_lines(_compact_lines(store))
+    sink.flush()
+
+
+def _compact_lines(store: MapStore) -> Iterator[str]:
+    for rel_path, entry in sorted(store.get_all_files()):
+        yield _header(rel_path, entry)
+        for symbol in entry.symbols:
+            yield compact_line(symbol, entry.language)
+
+
+def compact_line(symbol: Symbol, language: str = "go") -> str:
+    """Render one symbol, members included, as a single line.
+
+    Args:
+        symbol: Symbol to render.
+        language: Language of the symbol's file; Go symbols are written as
+            Go declarations.
+
+    Returns:
+        The line, without a trailing newline.
+    """
+    if language == "go":
+        return _go_declaration(symbol)
+    text = f"{symbol.type} {symbol.name}{_one_line(symbol.signature)}"
+    if symbol.children:
+        text += " { " + "; ".join(_member(c) for c in symbol.children) + " }"
+    return text
+
+
+def _header(rel_path: str, entry: FileEntry) -> str:
+    header = f"// {rel_path}"
+    if entry.package:
+        header += f" (package {entry.package})"
+    if entry.collapsed is not None:
+        kinds = ", ".join(f"{n} {kind}" for kind, n in entry.collapsed.items())
+        header += f": collapsed, {sum(entry.collapsed.values())} symbols ({kinds})"
+    return header
+
+
+def _member(symbol: Symbol) -> str:
+    """Render a class member of a language other than Go."""
+    if symbol.type in _METHOD_KINDS and not symbol.children:
+        return symbol.name + _one_line(symbol.signature or "()")
+    return compact_line(symbol, language="")
+
+
+def _go_declaration(symbol: Symbol) -> str:
+    signature = _one_line(symbol.signature)
+    if symbol.type == "method":
+        receiver = f"({symbol.receiver}) " if symbol.receiver else ""
+        return f"func {receiver}{symbol.name}{signature}"
+    if symbol.type in ("const", "var"):
+        text = f"{symbol.type} {symbol.name}"
+        if signature:
+            text += f" {signature}"
+        if symbol.value:
+            text += f" = {_one_line(symbol.value)}"
+        return text
+    if symbol.type in ("struct", "interface", "type"):
+        return f"type {symbol.name}{_type_params(symbol)}{' = ' if symbol.is_alias else ' '}{_type_body(symbol)}"
+    # Functions, including tests, benchmarks, examples and fuzz targets
+    return f"func {symbol.name}{signature}"
+
+
+def _type_params(symbol: Symbol) -> str:
+    if not symbol.type_params:
+        return ""
+    params = ", ".join(f"{p.name} {p.constraint}" if p.constraint else p.name for p in symbol.type_params)
+    return f"[{params}]"
+
+
+def _type_body(symbol: Symbol) -> str:
+    """Render the type a Go type declaration names, structs and interfaces on one line."""
+    if symbol.type == "struct":
+        members = [_field(f) for f in symbol.fields]
+        return "struct { " + "; ".join(members) + " }" if members else "struct{}"
+    if symbol.type == "interface":
+        members = list(symbol.embeds) + [c.name + _one_line(c.signature or "()") for c in symbol.children]
+        return "interface { " + "; ".join(members) + " }" if members else "interface{}"
+    return _one_line(symbol.signature)
+
+
+def _field(field: Field) -> str:
+    text = field.type if field.embedded or not field.name else f"{field.name} {field.type}"
+    if field.tag:
+        text += f" `{field.tag}`"
+    return _one_line(text)
+
+
+def _one_line(text: Optional[str]) -> str:
+    return _BREAK_RE.sub(" ", text or "").strip()
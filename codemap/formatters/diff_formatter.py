@@ -0,0 +1,85 @@
+"""Plain-text rendering of a map diff."""
+
+from __future__ import annotations
+
+from ..analysis.diff import MapDiff, SymbolChange, SymbolRecord
+
+
+def format_diff(diff: MapDiff, include_docs: bool = True) -> str:
+    """Render a diff as one line per symbol.
+
+    Added symbols start with "+", removed ones with "-", and modified ones
+    with "~" followed by what changed, e.g.:
+
+        + sample.NewCache (function) func NewCache(size int) *Cache
+        - sample.OldCache (type)
+        ~ sample.DefaultService.GetUser signature: (id int) (*User, error) -> (id string) (*User, error)
+        ~ sample.User fields: +Email string
+        ~ sample.Greet doc
+
+    Args:
+        diff: Result of diff_documents().
+        include_docs: Also list symbols whose only change is their doc comment.
+
+    Returns:
+        Rendered diff; empty if nothing changed.
+    """
+    lines: list[tuple[str, str]] = []
+    for record in diff.added:
+        lines.append((record.path, f"+ {_describe(record)}"))
+    for record in diff.removed:
+        lines.append((record.path, f"- {_describe(record)}"))
+
+    modified = {change.path for change in diff.modified}
+    for change in diff.modified:
+        details = [_change_detail(change, kind) for kind in change.changes]
+        if include_docs and change.path in {c.path for c in diff.doc_changed}:
+            details.append("doc")
+        lines.append((change.path, f"~ {change.path} {'; '.join(details)}"))
+    if include_docs:
+        for change in diff.doc_changed:
+            if change.path not in modified:
+                lines.append((change.path, f"~ {change.path} doc"))
+
+    return "\n".join(line for _, line in sorted(lines, key=lambda item: item[0]))
+
+
+def _describe(record: SymbolRecord) -> str:
+    text = f"{record.path} ({record.type})"
+    if record.signature:
+        text += f" {record.signature}"
+    return text
+
+
+def _params_text(record: SymbolRecord) -> str:
+    """Rebuild a Go signature from a record's parameters and results, e.g. "(id int) (*User, error)"."""
+    text = f"({', '.join(record.params)})"
+    if len(record.results) == 1 and " " not in record.results[0]:
+        return f"{text} {record.results[0]}"
+    if record.results:
+        text += f" ({', '.join(record.results)})"
+    return text
+
+
+def _kind(record: SymbolRecord) -> str:
+    return f"{record.type} alias" if record.is_alias else record.type
+
+
+def _change_detail(change: SymbolChange, kind: str) -> str:
+    """Describe one kind of change, e.g. "members: +Delete, -Remove"."""
+    if kind == "type":
+        return f"type: {_kind(change.old)} -> {_kind(change.new)}"
+    if kind == "signature":
+        old, new = change.old.signature, change.new.signature
+        if old == new:
+            # The change is past the cut of a long signature; spell out the parameters
+            old, new = _params_text(change.old), _params_text(change.new)
+        return f"signature: {old or '(none)'} -> {new or '(none)'}"
+    if kind == "value":
+        return f"value: {change.old.value or '(none)'} -> {change.new.value or '(none)'}"
+    if kind == "members":
+        added, removed = change.added_members, change.removed_members
+    else:
+        added, removed = change.added_fields, change.removed_fields
+    parts = [f"+{name}" for name in added] + [f"-{name}" for name in removed]
+    return f"{kind}: {', '.join(parts) or 'reordered'}"
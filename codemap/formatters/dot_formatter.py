@@ -0,0 +1,80 @@
+"""Graphviz DOT export of the Go package import graph."""
+
+from __future__ import annotations
+
+from typing import Optional
+
+from ..analysis.go_module import read_module_path
+from ..analysis.go_packages import collect_go_packages
+from ..analysis.imports import ImportGraph, build_import_graph
+from ..core.map_store import MapStore
+
+# Node standing in for every package outside the module when externals are collapsed
+EXTERNAL_NODE = "external"
+
+
+def format_dot(
+    store: MapStore,
+    internal_only: bool = False,
+    collapse_external: bool = False,
+    opaque: Optional[list[str]] = None,
+) -> str:
+    """Render the package import graph as Graphviz DOT.
+
+    Packages are named by import path, resolved from the nearest go.mod,
+    and by directory when none is found.
+    Edges that are part of an import cycle are drawn in red. Packages
+    under an opaque prefix are drawn as one boundary node per prefix.
+
+    Args:
+        store: Loaded MapStore.
+        internal_only: Only include imports between packages of the module.
+        collapse_external: Draw all packages outside the module as a single
+            "external" node.
+        opaque: Opaque package prefixes; defaults to those the index was
+            built with. Boundary nodes are kept with internal_only.
+
+    Returns:
+        DOT source; nodes and edges are sorted so output is stable.
+    """
+    packages = collect_go_packages(store.get_all_files(), store.root)
+    if opaque is None:
+        opaque = store.manifest.config.get("opaque", [])
+    graph = build_import_graph(packages, read_module_path(store.root), opaque)
+    return render_dot(graph, internal_only=internal_only, collapse_external=collapse_external)
+
+
+def render_dot(graph: ImportGraph, internal_only: bool = False, collapse_external: bool = False) -> str:
+    """Render an ImportGraph as DOT. See format_dot for the options."""
+    external: set[str] = set()
+    edges: set[tuple[str, str]] = set()
+    for source, target in graph.edges:
+        if not graph.is_internal(target) and not graph.is_boundary(target):
+            if internal_only:
+                continue
+            target = EXTERNAL_NODE if collapse_external else target
+            external.add(target)
+        edges.add((source, target))
+
+    lines = [
+        "digraph packages {",
+        "  rankdir=LR;",
+        '  node [shape=box, fontname="Helvetica"];',
+    ]
+    for package in graph.packages:
+        lines.append(f"  {_quote(package)};")
+    for node in graph.boundaries:
+        lines.append(f"  {_quote(node)} [shape=box3d, color=gray40, fontcolor=gray40];")
+    for node in sorted(external):
+        lines.append(f"  {_quote(node)} [style=dashed, color=gray50, fontcolor=gray50];")
+    for source, target in sorted(edges):
+        attrs = " [color=red, penwidth=2]" if (source, target) in graph.cycle_edges else ""
+        lines.append(f"  {_quote(source)} -> {_quote(target)}{attrs};")
+    lines.append("}")
+    return "\n".join(lines)
+
+
+def _quote(identifier: str) -> str:
+    """Quote a DOT identifier, escaping embedded quotes and backslashes."""
+    escaped = identifier.replace("\\", "\\\\").replace('"', '\\"')
+    return f'"{escaped}"'
@@ -0,0 +1,232 @@
+"""JSON export of a codemap index with a stable, versioned schema.
+
+The document groups files into packages (one per directory and declared
+package name) and lists every symbol with a fixed set of keys. Keys are
+always present, using null or an empty list when a value is unknown, so
+consumers never have to guess whether a field exists. See
+docs/output-formats.md for the full schema.
+"""
+
+from __future__ import annotations
+
+import json
+from pathlib import PurePosixPath
+from typing import Any, Iterator, Optional
+
+from ..analysis.go_module import ModuleResolver
+from ..analysis.go_packages import collect_go_packages, package_doc
+from ..analysis.implements import MethodSet, PackageIndex
+from ..core.map_store import FileEntry, MapStore
+from ..parsers.base import Param, Position, Symbol
+from .writer import TextStream, text_sink
+
+# Bump whenever a key is renamed, removed, or changes meaning
+SCHEMA_VERSION = 1
+
+
+def format_json(store: MapStore, method_sets: bool = False) -> str:
+    """Render the index as a JSON document.
+
+    Args:
+        store: Loaded MapStore.
+        method_sets: Fill each Go interface's method_set, embedded
+            interfaces' methods included.
+
+    Returns:
+        Pretty-printed JSON string.
+    """
+    return json.dumps(build_document(store, method_sets), indent=2, sort_keys=True)
+
+
+def write_json(out: TextStream, store: MapStore, method_sets: bool = False) -> None:
+    """Write the document of format_json, and a final newline, to a stream.
+
+    Packages are built and written one at a time, so only one package's
+    part of the document is held in memory, besides the index itself.
+
+    Args:
+        out: Text stream; see formatters.writer.
+        store: Loaded MapStore.
+        method_sets: As for format_json.
+
+    Raises:
+        OSError: If writing or flushing fails.
+    """
+    sink = text_sink(out)
+    # Laid out as json.dumps(indent=2, sort_keys=True) lays out the whole document
+    sink.write('{\n  "packages": [')
+    empty = True
+    for package in _packages(store, method_sets):
+        encoded = json.dumps(package, indent=2, sort_keys=True).replace("\n", "\n    ")
+        sink.write(("\n    " if empty else ",\n    ") + encoded)
+        empty = False
+    sink.write("]" if empty else "\n  ]")
+    sink.write(f',\n  "root": {json.dumps(store.manifest.root)},\n  "version": {SCHEMA_VERSION}\n}}\n')
+    sink.flush()
+
+
+def build_document(store: MapStore, method_sets: bool = False) -> dict[str, Any]:
+    """Build the JSON export document for an index.
+
+    Args:
+        store: Loaded MapStore.
+        method_sets: Fill each Go interface's method_set; it is null otherwise.
+
+    Returns:
+        Dictionary following the export schema.
+    """
+    return {
+        "version": SCHEMA_VERSION,
+        "root": store.manifest.root,
+        "packages": list(_packages(store, method_sets)),
+    }
+
+
+def _packages(store: MapStore, method_sets: bool = False) -> Iterator[dict[str, Any]]:
+    """Build the package objects of the export document one at a time, in document order."""
+    entries: dict[tuple[str, Optional[str]], list[tuple[str, FileEntry]]] = {}
+    for rel_path, entry in sorted(store.get_all_files()):
+        entries.setdefault((str(PurePosixPath(rel_path).parent), entry.package), []).append((rel_path, entry))
+    modules = ModuleResolver(store.root)
+    interface_sets = _interface_method_sets(store) if method_sets else {}
+
+    for directory, name in sorted(entries, key=lambda k: (k[0], k[1] or "")):
+        files = entries[(directory, name)]
+        first = files[0][1]
+        import_path, resolved = _import_path(modules, directory, first)
+        yield {
+            "name": name,
+            "path": directory,
+            "import_path": import_path,
+            "import_path_resolved": resolved,
+            "external_test": first.language == "go" and (name or "").endswith("_test"),
+            "opaque": first.opaque,
+            "doc": package_doc(files),
+            "files": [_file_to_dict(rel_path, entry) for rel_path, entry in files],
+            "symbols": [
+                _symbol_to_dict(s, rel_path, interface_sets) for rel_path, entry in files for s in entry.symbols
+            ],
+        }
+
+
+def _import_path(modules: ModuleResolver, directory: str, entry: FileEntry) -> tuple[Optional[str], bool]:
+    """Import path of a Go package directory; (None, False) for other languages."""
+    if entry.language != "go":
+        return None, False
+    return modules.import_path(directory)
+
+
+def _interface_method_sets(store: MapStore) -> dict[int, MethodSet]:
+    """Method sets of the Go interfaces in an index, by id() of the interface symbol."""
+    go_packages = collect_go_packages(store.get_all_files(), store.root)
+    index = PackageIndex(go_packages)
+    return {
+        id(symbol): index.full_method_set(package, name)
+        for package in go_packages
+        for name, symbol in package.interfaces.items()
+    }
+
+
+def _method_set_dict(method_set: Optional[MethodSet]) -> Optional[dict[str, Any]]:
+    if method_set is None:
+        return None
+    return {
+        "methods": [
+            {
+                "name": m.name,
+                "signature": m.signature,
+                "declared_by": m.declared_by,
+                "package": m.package,
+                "via": list(m.via),
+                "promoted": m.promoted,
+            }
+            for m in method_set.methods
+        ],
+        "unresolved": list(method_set.unresolved),
+    }
+
+
+def _file_to_dict(rel_path: str, entry: FileEntry) -> dict[str, Any]:
+    """Convert a file entry to its export representation."""
+    return {
+        "path": rel_path,
+        "language": entry.language,
+        "hash": entry.hash,
+        "lines": entry.lines,
+        "package_doc": entry.package_doc,
+        "imports": [{"path": i.path, "name": i.name} for i in entry.imports],
+        "collapsed": dict(entry.collapsed) if entry.collapsed is not None else None,
+        "notes": [
+            {"kind": n.kind, "text": n.text, "author": n.author, "pos": n.pos(rel_path).to_dict()}
+            for n in entry.notes
+        ],
+    }
+
+
+def _symbol_to_dict(
+    symbol: Symbol, rel_path: str, method_sets: Optional[dict[int, MethodSet]] = None
+) -> dict[str, Any]:
+    """Convert a symbol to its export representation with every key present."""
+    method_sets = method_sets or {}
+    return {
+        "id": symbol.id,
+        "hash": symbol.content_hash(),
+        "name": symbol.name,
+        "type": symbol.type,
+        "file": rel_path,
+        "lines": list(symbol.lines),
+        "pos": symbol.pos(rel_path).to_dict(),
+        "end": _position_dict(symbol.end_pos(rel_path)),
+        "signature": symbol.signature,
+        "docstring": symbol.docstring,
+        "exported": symbol.exported,
+        "receiver": symbol.receiver,
+        "embeds": list(symbol.embeds),
+        "resolved_embeds": list(symbol.resolved_embeds) or [None] * len(symbol.embeds),
+        "method_set": _method_set_dict(method_sets.get(id(symbol))),
+        "implements": list(symbol.implements),
+        "implemented_by": list(symbol.implemented_by),
+        "methods": list(symbol.methods),
+        "type_params": [{"name": p.name, "constraint": p.constraint} for p in symbol.type_params],
+        "params": [_param_dict(p) for p in symbol.params],
+        "results": [_param_dict(r) for r in symbol.results],
+        "fields": [
+            {
+                "name": f.name,
+                "type": f.type,
+                "resolved_type": f.resolved_type,
+                "tag": f.tag,
+                "embedded": f.embedded,
+                "pos": _position_dict(f.pos(rel_path)),
+                "end": _position_dict(f.end_pos(rel_path)),
+                "deprecated": f.deprecated,
+                "deprecation": f.deprecation,
+            }
+            for f in symbol.fields
+        ],
+        "value": symbol.value,
+        "int_value": symbol.int_value,
+        "enum": symbol.is_enum,
+        "enum_values": [
+            {"name": v.name, "value": v.value, "int_value": v.int_value} for v in symbol.enum_values
+        ],
+        "group": symbol.group,
+        "is_alias": symbol.is_alias,
+        "in_test": symbol.in_test,
+        "generated": symbol.generated,
+        "deprecated": symbol.deprecated,
+        "deprecation": symbol.deprecation,
+        "complexity": symbol.complexity,
+        "body_lines": symbol.body_lines,
+        "is_stub": symbol.is_stub,
+        "metadata": dict(symbol.metadata),
+        "children": [_symbol_to_dict(c, rel_path, method_sets) for c in symbol.children or []],
+    }
+
+
+def _param_dict(param: Param) -> dict[str, Any]:
+    return {"name": param.name, "type": param.type, "resolved_type": param.resolved_type, "variadic": param.variadic}
+
+
+def _position_dict(position: Optional[Position]) -> Optional[dict[str, Any]]:
+    return position.to_dict() if position else None
@@ -0,0 +1,196 @@
+"""JSON Lines export: one self-contained JSON object per file or symbol.
+
+Every line carries the schema version, its kind ("file" or "symbol") and
+the package (name and directory) it belongs to, so consumers can process
+lines one at a time. Symbol lines use the keys of the JSON export's
+symbols, children included. stream_jsonl writes the lines while parsing,
+without building an index, for trees too large to hold in memory.
+"""
+
+from __future__ import annotations
+
+import json
+from datetime import datetime, timezone
+from pathlib import Path, PurePosixPath
+from typing import Any, Iterator, Optional
+
+from ..analysis.go_module import ModuleResolver
+from ..analysis.go_packages import GoPackage
+from ..analysis.opaque import strip_opaque
+from ..analysis.symbol_ids import assign_ids, assign_module_ids, go_qualifier
+from ..core.map_store import FileEntry, MapStore
+from ..core.visitor import SymbolVisitor, visit_symbols
+from ..parsers.base import Symbol
+from ..utils.cancel import CancelToken
+from ..utils.config import Config
+from .json_formatter import SCHEMA_VERSION, _file_to_dict, _import_path, _symbol_to_dict
+from .writer import TextStream, render_to_string, text_sink
+
+
+def format_jsonl(store: MapStore) -> str:
+    """Render the index as JSON Lines, one line per file and per top-level symbol.
+
+    Args:
+        store: Loaded MapStore.
+
+    Returns:
+        Lines sorted by file; each file's line precedes its symbols'.
+    """
+    return render_to_string(write_jsonl, store)
+
+
+def write_jsonl(out: TextStream, store: MapStore) -> None:
+    """Write the lines of format_jsonl to a stream, each ending in a newline.
+
+    Args:
+        out: Text stream; see formatters.writer.
+        store: Loaded MapStore.
+
+    Raises:
+        OSError: If writing or flushing fails.
+    """
+    sink = text_sink(out)
+    modules = ModuleResolver(store.root)
+    for rel_path, entry in sorted(store.get_all_files()):
+        sink.write_lines(json.dumps(r, sort_keys=True) for r in jsonl_records(rel_path, entry, modules=modules))
+    sink.flush()
+
+
+def stream_jsonl(
+    out: TextStream,
+    root: Path,
+    config: Optional[Config] = None,
+    per_file: bool = False,
+    visitors: Optional[list[SymbolVisitor]] = None,
+    cancel: Optional[CancelToken] = None,
+) -> dict:
+    """Parse a tree and write it as JSON Lines as each file is parsed.
+
+    Files are discovered and filtered like codemap init, and parsed by the
+    same worker pool, but nothing is written to .codemap/ and only a few
+    parsed files are held in memory at a time. Workers only parse: every
+    line is written by the calling thread with a single write, in discovery
+    order, so lines never interleave and the output is deterministic.
+
+    Relations that need the whole tree (implements, implemented_by,
+    methods, enum_values) are left empty. IDs are assigned file by file,
+    so a name repeated in several files of a Go package (init functions)
+    gets the same id in each.
+
+    Args:
+        out: Text stream to write to, e.g. sys.stdout; see formatters.writer.
+        root: Directory to parse.
+        config: Config to use; loaded from root if None.
+        per_file: Write one line per file with its symbols nested under
+            "symbols", instead of a line per file and per symbol.
+        visitors: Called for every symbol of each file before its lines
+            are written, as for Indexer.
+        cancel: Optional token that stops the walk and the parsing, as for
+            Indexer.index_all.
+
+    Returns:
+        Statistics like Indexer.index_all's; total_symbols counts
+        top-level symbols.
+
+    Raises:
+        Cancelled: If cancel fired. The lines of the files parsed so far
+            have been written, each one complete.
+        OSError: If writing to out fails.
+    """
+    from ..core.indexer import Indexer
+
+    sink = text_sink(out)
+    indexer = Indexer(root=root, config=config, visitors=visitors)
+    modules = ModuleResolver(indexer.root)
+    files, skipped = indexer.discover(cancel)
+    total_files = 0
+    total_symbols = 0
+    errors = []
+    indexed_at = datetime.now(timezone.utc).isoformat()
+
+    for filepath, parsed, error in indexer.parse_files(files, cancel):
+        file_error = indexer.file_error(filepath, parsed, error)
+        if file_error is not None:
+            errors.append(file_error)
+        if error is not None:
+            continue
+        total_files += 1
+        if parsed is None:
+            continue
+        rel_path = Path(parsed.rel_path).as_posix()
+        opaque = parsed.language == "go" and indexer.is_opaque(rel_path)
+        if opaque:
+            strip_opaque(parsed.result)
+        _assign_ids(rel_path, parsed.language, parsed.result.package, parsed.result.symbols, modules)
+        visit_symbols(indexer.visitors, parsed.result.symbols, rel_path)
+        entry = FileEntry(
+            hash=parsed.hash,
+            indexed_at=indexed_at,
+            language=parsed.language,
+            lines=parsed.lines,
+            symbols=parsed.result.symbols,
+            package=parsed.result.package,
+            imports=parsed.result.imports,
+            error=parsed.result.error,
+            opaque=opaque,
+        )
+        total_symbols += len(entry.symbols)
+        for record in jsonl_records(rel_path, entry, per_file, modules):
+            sink.write(json.dumps(record, sort_keys=True) + "\n")
+
+    sink.flush()
+    return {
+        "total_files": total_files,
+        "total_symbols": total_symbols,
+        "errors": errors,
+        "skipped": skipped,
+    }
+
+
+def _assign_ids(
+    rel_path: str, language: str, package: Optional[str], symbols: list[Symbol], modules: ModuleResolver
+) -> None:
+    """Set symbol ids as the indexer would, from this file alone."""
+    if language != "go":
+        assign_module_ids(symbols, rel_path)
+    elif package:
+        go_package = GoPackage(directory=str(PurePosixPath(rel_path).parent), name=package)
+        go_package.import_path, _ = modules.import_path(go_package.directory)
+        assign_ids(symbols, go_qualifier(go_package))
+
+
+def jsonl_records(
+    rel_path: str, entry: FileEntry, per_file: bool = False, modules: Optional[ModuleResolver] = None
+) -> Iterator[dict[str, Any]]:
+    """Build the JSON Lines records of one file.
+
+    Args:
+        rel_path: Path of the file relative to the root.
+        entry: The file's entry.
+        per_file: Nest the symbols in the file record instead of yielding
+            a record per top-level symbol.
+        modules: Resolver for Go import paths; without it, import_path is null.
+
+    Yields:
+        The file record, then (unless per_file) one record per symbol in
+        source order.
+    """
+    directory = str(PurePosixPath(rel_path).parent)
+    import_path, resolved = _import_path(modules, directory, entry) if modules is not None else (None, False)
+    context = {
+        "version": SCHEMA_VERSION,
+        "package": entry.package,
+        "package_path": directory,
+        "import_path": import_path,
+        "import_path_resolved": resolved,
+        "external_test": entry.language == "go" and (entry.package or "").endswith("_test"),
+        "opaque": entry.opaque,
+    }
+    file_record = {"kind": "file", **context, **_file_to_dict(rel_path, entry), "error": entry.error}
+    if per_file:
+        file_record["symbols"] = [_symbol_to_dict(s, rel_path) for s in entry.symbols]
+        yield file_record
+    else:
+        yield file_record
+        for symbol in entry.symbols:
+            yield {"kind": "symbol", **context, **_symbol_to_dict(symbol, rel_path)}
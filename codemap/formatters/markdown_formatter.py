@@ -0,0 +1,281 @@
+"""Markdown export of a codemap index for PRs and design docs.
+
+Renders one section per package with a subsection per top-level symbol.
+Files collapsed by collapse_large_files get a summary line of symbol
+counts in their package section instead.
+Go methods are listed under their receiver type, wherever in the package
+they are declared, and enum types list their values. Every heading is preceded by an explicit anchor
+("userservice-getuser") so links stay stable regardless of how a Markdown
+renderer slugs headings, and a table of contents links to all of them.
+
+write_markdown writes the document to a stream section by section; the
+table of contents comes first, so every section is laid out before any of
+the body is written, but the document is never joined into one string.
+"""
+
+from __future__ import annotations
+
+import re
+from typing import Any, Iterator, Optional
+
+from ..analysis.go_packages import receiver_base
+from ..core.map_store import MapStore
+from .json_formatter import build_document
+from .writer import TextStream, render_to_string, text_sink
+
+# Deepest heading level; deeper symbols are still rendered at this level
+_MAX_HEADING = 6
+
+
+def format_markdown(store: MapStore) -> str:
+    """Render the index as Markdown with a table of contents.
+
+    Args:
+        store: Loaded MapStore.
+
+    Returns:
+        Markdown document.
+    """
+    return render_to_string(write_markdown, store)
+
+
+def write_markdown(out: TextStream, store: MapStore) -> None:
+    """Write the Markdown document of format_markdown, and a final newline, to a stream.
+
+    Args:
+        out: Text stream; see formatters.writer.
+        store: Loaded MapStore.
+
+    Raises:
+        OSError: If writing or flushing fails.
+    """
+    sink = text_sink(out)
+    blank = 0
+    for line in _document_lines(store):
+        # Blank lines are held back until more text follows, so the document ends with its last line
+        if not line:
+            blank += 1
+            continue
+        sink.write("\n" * blank + line + "\n")
+        blank = 0
+    sink.flush()
+
+
+def _document_lines(store: MapStore) -> Iterator[str]:
+    anchors = _Anchors()
+    sections = [_package_section(p, anchors) for p in build_document(store)["packages"]]
+
+    yield from ["# Code Map", ""]
+    if sections:
+        yield from ["## Contents", ""]
+        for section in sections:
+            yield from section.toc(0)
+        yield ""
+    for section in sections:
+        yield from section.body()
+
+
+class _Anchors:
+    """Hands out unique anchor ids, suffixing repeats like GitHub does."""
+
+    def __init__(self):
+        self._used: dict[str, int] = {}
+
+    def make(self, *parts: str) -> str:
+        base = "-".join(p for p in (_slug(part) for part in parts) if p) or "section"
+        count = self._used.get(base, 0)
+        self._used[base] = count + 1
+        return base if count == 0 else f"{base}-{count}"
+
+
+class _Section:
+    """A heading with its anchor, body lines and nested sections."""
+
+    def __init__(self, title: str, anchor: str, level: int, lines: list[str]):
+        self.title = title
+        self.anchor = anchor
+        self.level = level
+        self.lines = lines
+        self.children: list[_Section] = []
+
+    def toc(self, depth: int) -> list[str]:
+        entries = [f"{'  ' * depth}- [{self.title}](#{self.anchor})"]
+        for child in self.children:
+            entries += child.toc(depth + 1)
+        return entries
+
+    def body(self) -> Iterator[str]:
+        heading = "#" * min(self.level, _MAX_HEADING)
+        yield from [f'<a id="{self.anchor}"></a>', f"{heading} {self.title}", ""]
+        if self.lines:
+            yield from self.lines
+            yield ""
+        for child in self.children:
+            yield from child.body()
+
+
+def _package_section(package: dict[str, Any], anchors: _Anchors) -> _Section:
+    """Build the section for one package, attaching methods to their receivers."""
+    title = package["name"] or package["path"]
+    files = ", ".join(f"`{f['path']}`" for f in package["files"])
+    lines = [f"Path: `{package['path']}` · Files: {files}"]
+    if package["doc"]:
+        lines += ["", package["doc"]]
+    for file in package["files"]:
+        if file["collapsed"] is not None:
+            lines += ["", _collapsed_line(file)]
+    section = _Section(title, anchors.make(title), 2, lines)
+
+    receivers = {s["name"] for s in package["symbols"] if s["type"] != "method"}
+    methods: dict[str, list[dict[str, Any]]] = {}
+    top_level = []
+    for symbol in package["symbols"]:
+        base = receiver_base(symbol["receiver"])[0] if symbol.get("receiver") else None
+        if base in receivers:
+            methods.setdefault(base, []).append(symbol)
+        else:
+            top_level.append(symbol)
+
+    i = 0
+    while i < len(top_level):
+        symbol = top_level[i]
+        if symbol.get("group"):
+            # Keep a const/var block together, e.g. the values of an enum
+            members = [symbol]
+            while i + len(members) < len(top_level) and _same_group(top_level[i + len(members)], symbol):
+                members.append(top_level[i + len(members)])
+            section.children.append(_group_section(members, 3, anchors))
+            i += len(members)
+            continue
+        section.children.append(_symbol_section(symbol, [], 3, anchors, methods.get(symbol["name"], [])))
+        i += 1
+    return section
+
+
+def _collapsed_line(file: dict[str, Any]) -> str:
+    """Summarize a collapsed file, e.g. "`big.go` (collapsed): 412 symbols (12 function, 400 method)"."""
+    counts = file["collapsed"]
+    kinds = ", ".join(f"{n} {kind}" for kind, n in counts.items())
+    return (
+        f"`{file['path']}` (collapsed): {sum(counts.values())} symbols ({kinds}) · "
+        f"run `codemap show {file['path']}` for the listing"
+    )
+
+
+def _same_group(symbol: dict[str, Any], first: dict[str, Any]) -> bool:
+    return (
+        symbol.get("group") == first["group"]
+        and symbol["type"] == first["type"]
+        and symbol["file"] == first["file"]
+    )
+
+
+def _group_section(members: list[dict[str, Any]], level: int, anchors: _Anchors) -> _Section:
+    """Build one section for the symbols declared in a single const/var block."""
+    kind = members[0]["type"]
+    names = [m["name"] for m in members]
+    title = f"{kind} ({', '.join(names[:4])}{', …' if len(names) > 4 else ''})"
+    start, end = members[0]["lines"][0], members[-1]["lines"][1]
+    lines = [f"`{kind}` · `{members[0]['file']}:{start}-{end}`", ""]
+    lines += ["| Name | Type | Value | Description |", "|------|------|-------|-------------|"]
+    for member in members:
+        type_text = f"`{_escape_cell(member['signature'])}`" if member["signature"] else ""
+        value = f"`{_escape_cell(member['value'])}`" if member["value"] else ""
+        doc = _escape_cell(_trim_doc(member["docstring"], member["name"]) or "").replace("\n", " ")
+        lines.append(f"| `{member['name']}` | {type_text} | {value} | {doc} |")
+    return _Section(title, anchors.make(kind, members[0]["group"]), level, lines)
+
+
+def _symbol_section(
+    symbol: dict[str, Any],
+    parents: list[str],
+    level: int,
+    anchors: _Anchors,
+    extra_children: Optional[list[dict[str, Any]]] = None,
+) -> _Section:
+    """Build the section for a symbol and, recursively, its members."""
+    path = parents + [symbol["name"]]
+    section = _Section(symbol["name"], anchors.make(*path), level, _symbol_lines(symbol))
+    for child in list(symbol["children"]) + list(extra_children or []):
+        section.children.append(_symbol_section(child, path, level + 1, anchors))
+    return section
+
+
+def _symbol_lines(symbol: dict[str, Any]) -> list[str]:
+    """Render the body of a symbol section."""
+    start, end = symbol["lines"]
+    meta = f"`{symbol['type']}` · `{symbol['file']}:{start}-{end}`"
+    if symbol.get("receiver"):
+        meta += f" · receiver `{symbol['receiver']}`"
+    lines = [meta]
+
+    declaration = _declaration(symbol)
+    if declaration:
+        lines += ["", f"`{declaration}`"]
+
+    doc = _trim_doc(symbol["docstring"], symbol["name"])
+    if doc:
+        lines += ["", doc]
+
+    if symbol["fields"]:
+        lines += ["", "| Field | Type | Tag |", "|-------|------|-----|"]
+        for fld in symbol["fields"]:
+            name = f"`{fld['name']}`" if fld["name"] else "*(embedded)*"
+            tag = f"`{fld['tag']}`" if fld["tag"] else ""
+            lines.append(f"| {name} | `{_escape_cell(fld['type'])}` | {_escape_cell(tag)} |")
+
+    if symbol.get("enum_values"):
+        lines += ["", "Values: " + ", ".join(_enum_value(v) for v in symbol["enum_values"])]
+
+    for label, key in (("Implements", "implements"), ("Implemented by", "implemented_by")):
+        if symbol.get(key):
+            lines += ["", f"{label}: " + ", ".join(f"`{ref}`" for ref in symbol[key])]
+    return lines
+
+
+def _enum_value(value: dict[str, Any]) -> str:
+    """Render an enum value, e.g. "`Monday` = 1", or the name alone if its value isn't known."""
+    if value["int_value"] is not None:
+        return f"`{value['name']}` = {value['int_value']}"
+    if value["value"]:
+        return f"`{value['name']}` = `{_escape_cell(value['value'])}`"
+    return f"`{value['name']}`"
+
+
+def _declaration(symbol: dict[str, Any]) -> Optional[str]:
+    """Render the code line shown under a heading, e.g. "GetUser(id int) error"."""
+    if symbol["type"] in ("const", "var"):
+        parts = [symbol["name"]] + ([symbol["signature"]] if symbol["signature"] else [])
+        if symbol["value"]:
+            parts += ["=", symbol["value"]]
+        return " ".join(parts)
+    if not symbol["signature"]:
+        return None
+    if symbol["type"] == "type":
+        return f"{symbol['name']} = {symbol['signature']}" if symbol.get("is_alias") else symbol["signature"]
+    return symbol["name"] + symbol["signature"]
+
+
+def _trim_doc(doc: Optional[str], name: str) -> Optional[str]:
+    """Drop the conventional leading symbol name from a doc comment.
+
+    "GetUser retrieves a user by ID." becomes "Retrieves a user by ID.".
+    """
+    if not doc:
+        return None
+    doc = doc.strip()
+    if doc.startswith(name + " "):
+        rest = doc[len(name) + 1:].lstrip()
+        if rest:
+            doc = rest[0].upper() + rest[1:]
+    return doc
+
+
+def _escape_cell(text: str) -> str:
+    """Escape pipes so a value doesn't split a Markdown table cell."""
+    return text.replace("|", "\\|")
+
+
+def _slug(text: str) -> str:
+    """Lower-case anchor text with punctuation removed, e.g. "src/app" -> "srcapp"."""
+    return re.sub(r"[^\w\- ]", "", text.lower()).strip().replace(" ", "-")
@@ -0,0 +1,145 @@
+"""Mermaid class diagram export of Go types."""
+
+from __future__ import annotations
+
+import re
+from typing import Optional
+
+from ..analysis.go_packages import GoPackage, collect_go_packages, receiver_base
+from ..analysis.implements import PackageIndex
+from ..core.map_store import MapStore
+from ..parsers.base import Field, Param, Symbol
+from ..parsers.go_parser import is_exported
+
+# Characters Mermaid would read as syntax, written as its "#code;" entities.
+# "#" comes first so the entities themselves aren't escaped again.
+_ESCAPES = [(c, f"#{ord(c)};") for c in '#"()*<>[]{}~$']
+
+
+def format_mermaid(store: MapStore) -> str:
+    """Render Go types as a Mermaid classDiagram.
+
+    Structs, interfaces and other defined types become classes with their
+    fields and methods. Interface implementations are drawn as "<|..",
+    embedded structs as composition ("*--", or aggregation "o--" for
+    pointers) and embedded interfaces as inheritance ("<|--"). Use
+    filter_exported first to keep large diagrams legible.
+
+    Args:
+        store: Loaded MapStore.
+
+    Returns:
+        Mermaid source, starting with "classDiagram".
+    """
+    packages = collect_go_packages(store.get_all_files())
+    index = PackageIndex(packages)
+    names = [p.name for p in packages]
+    ids: dict[tuple[str, str], str] = {}
+    for package in packages:
+        prefix = package.name if names.count(package.name) == 1 else package.directory
+        for symbol in _types(package):
+            ids[(package.directory, symbol.name)] = _ident(f"{prefix}_{symbol.name}")
+    qualify = len(packages) > 1
+
+    lines = ["classDiagram"]
+    relations: list[str] = []
+    for package in packages:
+        for symbol in _types(package):
+            class_id = ids[(package.directory, symbol.name)]
+            label = f"{package.name}.{symbol.name}" if qualify else symbol.name
+            if symbol.type_params:
+                label += "[" + ", ".join(p.name for p in symbol.type_params) + "]"
+            lines.append(f'  class {class_id}["{escape(label)}"]')
+            if symbol.type == "interface":
+                lines.append(f"  <<interface>> {class_id}")
+            for member in _members(package, symbol):
+                lines.append(f"  {class_id} : {member}")
+            relations.extend(_relations(index, ids, package, symbol, class_id))
+    lines.extend(relations)
+    return "\n".join(lines)
+
+
+def escape(text: str) -> str:
+    """Escape Mermaid-unfriendly characters, e.g. "map[int]*User" -> "map#91;int#93;#42;User"."""
+    for char, entity in _ESCAPES:
+        text = text.replace(char, entity)
+    return text
+
+
+def _types(package: GoPackage) -> list[Symbol]:
+    """Type declarations of a package in file order, without aliases."""
+    return [
+        symbol
+        for _, entry in package.files
+        for symbol in entry.symbols
+        if symbol.type in ("struct", "interface", "type") and not symbol.is_alias
+    ]
+
+
+def _members(package: GoPackage, symbol: Symbol) -> list[str]:
+    members = [_field(f) for f in symbol.fields]
+    if symbol.type == "interface":
+        methods = [m for m in symbol.children if m.type == "method"]
+    else:
+        methods = package.methods.get(symbol.name, [])
+    members.extend(_method(m) for m in methods)
+    return members
+
+
+def _field(field: Field) -> str:
+    if field.embedded:
+        name = receiver_base(field.type)[0].rsplit(".", 1)[-1]
+        return _visibility(is_exported(name)) + escape(field.type)
+    return f"{_visibility(is_exported(field.name or ''))}{field.name} {escape(field.type)}"
+
+
+def _method(method: Symbol) -> str:
+    params = escape(", ".join(str(p) for p in method.params))
+    text = f"{_visibility(method.exported)}{method.name}({params})"
+    results = _results(method.results)
+    return f"{text} {escape(results)}" if results else text
+
+
+def _results(results: list[Param]) -> str:
+    if len(results) == 1 and not results[0].name:
+        return results[0].type
+    return "(" + ", ".join(str(r) for r in results) + ")" if results else ""
+
+
+def _visibility(exported: Optional[bool]) -> str:
+    if exported is None:
+        return ""
+    return "+" if exported else "-"
+
+
+def _relations(
+    index: PackageIndex, ids: dict[tuple[str, str], str], package: GoPackage, symbol: Symbol, class_id: str
+) -> list[str]:
+    """Edges from a type to the interfaces it implements and the types it embeds."""
+    relations = []
+    for ref in symbol.implements:
+        target = _resolve(index, ids, package, ref)
+        if target is not None:
+            relations.append(f"  {target} <|.. {class_id}")
+    for embed in symbol.embeds:
+        target = _resolve(index, ids, package, embed)
+        if target is None:
+            continue
+        if symbol.type == "interface":
+            relations.append(f"  {target} <|-- {class_id}")
+        else:
+            arrow = "o--" if embed.startswith("*") else "*--"
+            relations.append(f"  {class_id} {arrow} {target}")
+    return relations
+
+
+def _resolve(index: PackageIndex, ids: dict[tuple[str, str], str], package: GoPackage, ref: str) -> Optional[str]:
+    resolved = index.resolve(package, ref)
+    if resolved is None:
+        return None
+    return ids.get((resolved[0].directory, resolved[1]))
+
+
+def _ident(text: str) -> str:
+    """Make a Mermaid class id: letters, digits and underscores only."""
+    return re.sub(r"\W", "_", text.strip("./"))
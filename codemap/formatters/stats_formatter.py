@@ -0,0 +1,74 @@
+"""Plain-text rendering of index statistics."""
+
+from __future__ import annotations
+
+from typing import Optional
+
+from ..analysis.stats import KindCount, MapStats, PackageStats
+
+
+def format_stats(stats: MapStats, top: int = 10, package: Optional[PackageStats] = None) -> str:
+    """Render symbol counts as compact tables, e.g.:
+
+        Symbols: 42 (30 exported, 12 unexported) in 5 files, 2 packages
+
+        Type      Total  Exported  Unexported
+        method       26        19           7
+        function     16        11           5
+
+        Largest packages        Files  Symbols  Exported  Unexported
+        store (internal/store)      3       30        22           8
+        cli (cmd/cli)               2       12         8           4
+
+    Args:
+        stats: Result of map_stats().
+        top: Number of largest packages to list; 0 leaves the list out.
+        package: Render this package's counts instead of the whole index.
+
+    Returns:
+        Rendered tables.
+    """
+    if package is not None:
+        header = (
+            f"Package {_label(package)}: {package.total} symbols "
+            f"({package.exported} exported, {package.unexported} unexported) in {package.files} files"
+        )
+        return "\n\n".join([header, _kind_table(package.kinds)]) if package.kinds else header
+
+    header = (
+        f"Symbols: {stats.total} ({stats.exported} exported, {stats.unexported} unexported) "
+        f"in {stats.files} files, {len(stats.packages)} packages"
+    )
+    parts = [header]
+    if stats.kinds:
+        parts.append(_kind_table(stats.kinds))
+    largest = stats.largest(top) if top > 0 else []
+    if largest:
+        rows = [[_label(p), str(p.files), str(p.total), str(p.exported), str(p.unexported)] for p in largest]
+        parts.append(_table(["Largest packages", "Files", "Symbols", "Exported", "Unexported"], rows))
+    return "\n\n".join(parts)
+
+
+def _kind_table(kinds: dict[str, KindCount]) -> str:
+    ordered = sorted(kinds.items(), key=lambda item: (-item[1].total, item[0]))
+    rows = [[kind, str(c.total), str(c.exported), str(c.unexported)] for kind, c in ordered]
+    return _table(["Type", "Total", "Exported", "Unexported"], rows)
+
+
+def _label(package: PackageStats) -> str:
+    """Name a package by its declared name and directory, e.g. "store (internal/store)"."""
+    if package.name is None:
+        return package.path
+    return f"{package.name} ({package.path})"
+
+
+def _table(headers: list[str], rows: list[list[str]]) -> str:
+    """Align columns: the first to the left, the rest (numbers) to the right."""
+    widths = [max(len(row[i]) for row in [headers] + rows) for i in range(len(headers))]
+
+    def line(cells: list[str]) -> str:
+        first = cells[0].ljust(widths[0])
+        rest = [cell.rjust(width) for cell, width in zip(cells[1:], widths[1:])]
+        return "  ".join([first] + rest).rstrip()
+
+    return "\n".join(line(cells) for cells in [headers] + rows)
@@ -0,0 +1,85 @@
+"""Writing rendered output to text streams.
+
+The write_* functions of the formatters take any object with a
+write(str) method, such as a file, sys.stdout, io.StringIO or a wrapper
+around an HTTP response, and write to it through a TextSink. A write that
+reports fewer characters than it was given is retried with the rest, and
+one that makes no progress raises ShortWriteError. Errors raised by the
+stream's write() and flush() are not caught, so a full disk or a closed
+pipe surfaces as the OSError it is, after whatever was written before it.
+"""
+
+from __future__ import annotations
+
+import io
+from typing import Callable, Iterable, Protocol
+
+
+class TextStream(Protocol):
+    """Anything output can be written to."""
+
+    def write(self, text: str, /) -> object: ...
+
+
+class ShortWriteError(OSError):
+    """A stream accepted only part of the output and then nothing more."""
+
+
+class TextSink:
+    """Writes everything it is given to a stream, or raises."""
+
+    def __init__(self, out: TextStream):
+        self._out = out
+
+    def write(self, text: str) -> int:
+        """Write all of text, retrying short writes.
+
+        Returns:
+            len(text).
+
+        Raises:
+            ShortWriteError: If the stream wrote nothing of what was left.
+        """
+        remaining = text
+        while remaining:
+            written = self._out.write(remaining)
+            if written is None:
+                break  # Streams that don't report a count wrote everything
+            if not isinstance(written, int) or written <= 0:
+                raise ShortWriteError(f"Stream accepted {len(text) - len(remaining)} of {len(text)} characters")
+            remaining = remaining[written:]
+        return len(text)
+
+    def write_lines(self, lines: Iterable[str]) -> None:
+        """Write each line followed by a newline."""
+        for line in lines:
+            self.write(line + "\n")
+
+    def flush(self) -> None:
+        """Flush the stream, if it can be flushed."""
+        flush = getattr(self._out, "flush", None)
+        if flush is not None:
+            flush()
+
+
+def text_sink(out: TextStream) -> TextSink:
+    """Wrap a stream in a TextSink, unless it is one already."""
+    return out if isinstance(out, TextSink) else TextSink(out)
+
+
+def writer_for(format_text: Callable[..., str]) -> Callable[..., None]:
+    """Make a write_* function from a formatter that returns its output as a string."""
+
+    def write(out: TextStream, *args, **kwargs) -> None:
+        sink = text_sink(out)
+        sink.write(format_text(*args, **kwargs) + "\n")
+        sink.flush()
+
+    return write
+
+
+def render_to_string(write: Callable[..., None], *args, **kwargs) -> str:
+    """Run a write_* function into a string, without its final newline."""
+    buffer = io.StringIO()
+    write(buffer, *args, **kwargs)
+    return buffer.getvalue().removesuffix("\n")
@@ -0,0 +1,360 @@
+"""XML export of a codemap index, mirroring the JSON export.
+
+The document holds the same data as build_document() in a fixed element
+structure, so an XSD can be written against it:
+
+- Identifiers, numbers and flags are attributes; true/false for booleans.
+  Attributes whose value is null, and flags that are false, are left out.
+- Free text that may be long or span lines (signatures, doc comments,
+  values) is element text.
+- Lists are a container element with one child per item, left out when
+  the list is empty.
+
+Markup characters are escaped; control characters XML can't hold at all
+are replaced with U+FFFD.
+
+Every element is in the NAMESPACE namespace. load_xml() reads a document
+back into the JSON structure. See docs/output-formats.md for the schema.
+"""
+
+from __future__ import annotations
+
+import json
+import re
+import xml.etree.ElementTree as ET
+from dataclasses import dataclass
+from typing import Any, Optional, Union
+
+from ..core.map_store import MapStore
+from .json_formatter import build_document
+from .writer import TextStream, render_to_string, text_sink
+
+NAMESPACE = "urn:codemap:export"
+
+
+@dataclass(frozen=True)
+class _Attr:
+    """A scalar stored as an attribute; omitted when equal to default."""
+
+    key: str
+    kind: type = str
+    default: Any = None
+
+
+@dataclass(frozen=True)
+class _Text:
+    """An optional string stored as a child element's text."""
+
+    key: str
+
+
+@dataclass(frozen=True)
+class _Object:
+    """An optional nested object stored as a child element."""
+
+    key: str
+    fields: tuple
+
+
+@dataclass(frozen=True)
+class _List:
+    """A list stored as a container of item elements.
+
+    Items are objects with the given fields, or strings (possibly null)
+    stored as item text when fields is None.
+    """
+
+    key: str
+    item: str
+    fields: Optional[tuple] = None
+
+
+@dataclass(frozen=True)
+class _Children:
+    """Nested symbols, each with the fields of a symbol."""
+
+    key: str
+
+
+@dataclass(frozen=True)
+class _Range:
+    """A [start, end] pair stored as an element with start and end attributes."""
+
+    key: str
+
+
+@dataclass(frozen=True)
+class _Counts:
+    """An optional str -> int mapping stored as <count type="...">n</count> children."""
+
+    key: str
+
+
+@dataclass(frozen=True)
+class _Entries:
+    """A free-form mapping stored as <entry key="...">JSON value</entry> children."""
+
+    key: str
+
+
+_Spec = Union[_Attr, _Text, _Object, _List, _Children, _Range, _Counts, _Entries]
+
+# Characters XML 1.0 can't represent, even escaped
+_INVALID_CHARS_RE = re.compile("[\x00-\x08\x0b\x0c\x0e-\x1f\ufffe\uffff]")
+
+_POSITION = (_Attr("file"), _Attr("line", int), _Attr("column", int))
+
+_PARAM = (
+    _Attr("name"),
+    _Attr("type"),
+    _Attr("resolved_type"),
+    _Attr("variadic", bool, False),
+)
+
+_FIELD = (
+    _Attr("name"),
+    _Attr("type"),
+    _Attr("resolved_type"),
+    _Attr("tag"),
+    _Attr("embedded", bool, False),
+    _Attr("deprecated", bool, False),
+    _Object("pos", _POSITION),
+    _Object("end", _POSITION),
+    _Text("deprecation"),
+)
+
+_METHOD = (
+    _Attr("name"),
+    _Attr("declared_by"),
+    _Attr("package"),
+    _Attr("promoted", bool, False),
+    _Text("signature"),
+    _List("via", "embed"),
+)
+
+_SYMBOL = (
+    _Attr("id"),
+    _Attr("hash"),
+    _Attr("name"),
+    _Attr("type"),
+    _Attr("file"),
+    _Attr("exported", bool),
+    _Attr("receiver"),
+    _Attr("group"),
+    _Attr("is_alias", bool, False),
+    _Attr("in_test", bool, False),
+    _Attr("generated", bool, False),
+    _Attr("deprecated", bool, False),
+    _Attr("complexity", int),
+    _Attr("body_lines", int),
+    _Attr("is_stub", bool, False),
+    _Attr("int_value", int),
+    _Attr("enum", bool, False),
+    _Range("lines"),
+    _Object("pos", _POSITION),
+    _Object("end", _POSITION),
+    _Text("signature"),
+    _Text("docstring"),
+    _Text("value"),
+    _Text("deprecation"),
+    _List("embeds", "embed"),
+    _List("resolved_embeds", "embed"),
+    _List("implements", "type"),
+    _List("implemented_by", "type"),
+    _List("methods", "method"),
+    _List("enum_values", "enum_value", (_Attr("name"), _Attr("int_value", int), _Text("value"))),
+    _List("type_params", "type_param", (_Attr("name"), _Attr("constraint"))),
+    _List("params", "param", _PARAM),
+    _List("results", "result", _PARAM),
+    _List("fields", "field", _FIELD),
+    _Object("method_set", (_List("methods", "method", _METHOD), _List("unresolved", "embed"))),
+    _Entries("metadata"),
+    _Children("children"),
+)
+
+_FILE = (
+    _Attr("path"),
+    _Attr("language"),
+    _Attr("hash"),
+    _Attr("lines", int),
+    _Text("package_doc"),
+    _List("imports", "import", (_Attr("path"), _Attr("name"))),
+    _Counts("collapsed"),
+    _List("notes", "note", (_Attr("kind"), _Attr("author"), _Object("pos", _POSITION), _Text("text"))),
+)
+
+_PACKAGE = (
+    _Attr("name"),
+    _Attr("path"),
+    _Attr("import_path"),
+    _Attr("import_path_resolved", bool, False),
+    _Attr("external_test", bool, False),
+    _Attr("opaque", bool, False),
+    _Text("doc"),
+    _List("files", "file", _FILE),
+    _List("symbols", "symbol", _SYMBOL),
+)
+
+_DOCUMENT = (
+    _Attr("version", int),
+    _Attr("root"),
+    _List("packages", "package", _PACKAGE),
+)
+
+
+def format_xml(store: MapStore, method_sets: bool = False) -> str:
+    """Render the index as an XML document.
+
+    Args:
+        store: Loaded MapStore.
+        method_sets: Fill each Go interface's method_set, as for format_json.
+
+    Returns:
+        Indented XML with an XML declaration.
+    """
+    return render_to_string(write_xml, store, method_sets)
+
+
+def write_xml(out: TextStream, store: MapStore, method_sets: bool = False) -> None:
+    """Write the document of format_xml, and a final newline, to a stream.
+
+    Args:
+        out: Text stream; see formatters.writer.
+        store: Loaded MapStore.
+        method_sets: As for format_xml.
+
+    Raises:
+        OSError: If writing or flushing fails.
+    """
+    root = _element("codemap", build_document(store, method_sets), _DOCUMENT)
+    # Elements are written unqualified under a default namespace declaration;
+    # ElementTree's default_namespace option rejects unqualified attributes
+    root.attrib = {"xmlns": NAMESPACE, **root.attrib}
+    ET.indent(root)
+    sink = text_sink(out)
+    sink.write('<?xml version="1.0" encoding="UTF-8"?>\n')
+    ET.ElementTree(root).write(sink, encoding="unicode")
+    sink.write("\n")
+    sink.flush()
+
+
+def load_xml(text: str) -> dict[str, Any]:
+    """Read an XML export back into the structure build_document() returns.
+
+    Args:
+        text: XML document from format_xml.
+
+    Returns:
+        Dictionary following the JSON export schema.
+
+    Raises:
+        ValueError: If the text isn't a codemap XML export.
+    """
+    try:
+        root = ET.fromstring(text)
+    except ET.ParseError as e:
+        raise ValueError(f"Invalid XML: {e}") from e
+    if root.tag != _tag("codemap"):
+        raise ValueError(f"Not a codemap XML export: root element is {root.tag}")
+    return _read(root, _DOCUMENT)
+
+
+def _tag(name: str) -> str:
+    """Qualified name of an element as parsed."""
+    return f"{{{NAMESPACE}}}{name}"
+
+
+def _element(tag: str, data: dict[str, Any], fields: tuple) -> ET.Element:
+    element = ET.Element(tag)
+    for spec in fields:
+        _write(element, spec, data[spec.key])
+    return element
+
+
+def _write(element: ET.Element, spec: _Spec, value: Any) -> None:
+    if isinstance(spec, _Attr):
+        if value is not None and value != spec.default:
+            element.set(spec.key, _attr_text(value))
+    elif isinstance(spec, _Text):
+        if value is not None:
+            ET.SubElement(element, spec.key).text = _clean(value)
+    elif isinstance(spec, _Object):
+        if value is not None:
+            element.append(_element(spec.key, value, spec.fields))
+    elif isinstance(spec, _List):
+        if value:
+            container = ET.SubElement(element, spec.key)
+            for item in value:
+                if spec.fields is None:
+                    ET.SubElement(container, spec.item).text = None if item is None else _clean(item)
+                else:
+                    container.append(_element(spec.item, item, spec.fields))
+    elif isinstance(spec, _Children):
+        if value:
+            container = ET.SubElement(element, spec.key)
+            for item in value:
+                container.append(_element("symbol", item, _SYMBOL))
+    elif isinstance(spec, _Range):
+        ET.SubElement(element, spec.key, start=str(value[0]), end=str(value[1]))
+    elif isinstance(spec, _Counts):
+        if value is not None:
+            container = ET.SubElement(element, spec.key)
+            for name, count in value.items():
+                ET.SubElement(container, "count", type=_clean(name)).text = str(count)
+    elif isinstance(spec, _Entries):
+        if value:
+            container = ET.SubElement(element, spec.key)
+            for key, item in sorted(value.items()):
+                ET.SubElement(container, "entry", key=_clean(key)).text = json.dumps(item, sort_keys=True)
+
+
+def _attr_text(value: Any) -> str:
+    if isinstance(value, bool):
+        return "true" if value else "false"
+    return _clean(str(value))
+
+
+def _clean(text: str) -> str:
+    return _INVALID_CHARS_RE.sub("\ufffd", text)
+
+
+def _read(element: ET.Element, fields: tuple) -> dict[str, Any]:
+    data: dict[str, Any] = {}
+    for spec in fields:
+        if isinstance(spec, _Attr):
+            raw = element.get(spec.key)
+            data[spec.key] = spec.default if raw is None else _parse_attr(raw, spec.kind)
+            continue
+        child = element.find(_tag(spec.key))
+        if isinstance(spec, _Text):
+            data[spec.key] = None if child is None else child.text or ""
+        elif isinstance(spec, _Object):
+            data[spec.key] = None if child is None else _read(child, spec.fields)
+        elif isinstance(spec, _List):
+            items = [] if child is None else child.findall(_tag(spec.item))
+            if spec.fields is None:
+                data[spec.key] = [item.text for item in items]
+            else:
+                data[spec.key] = [_read(item, spec.fields) for item in items]
+        elif isinstance(spec, _Children):
+            items = [] if child is None else child.findall(_tag("symbol"))
+            data[spec.key] = [_read(item, _SYMBOL) for item in items]
+        elif isinstance(spec, _Range):
+            data[spec.key] = [int(child.get("start")), int(child.get("end"))]
+        elif isinstance(spec, _Counts):
+            data[spec.key] = (
+                None if child is None
+                else {c.get("type"): int(c.text) for c in child.findall(_tag("count"))}
+            )
+        elif isinstance(spec, _Entries):
+            entries = [] if child is None else child.findall(_tag("entry"))
+            data[spec.key] = {e.get("key"): json.loads(e.text) for e in entries}
+    return data
+
+
+def _parse_attr(raw: str, kind: type) -> Any:
+    if kind is bool:
+        return raw == "true"
+    if kind is int:
+        return int(raw)
+    return raw
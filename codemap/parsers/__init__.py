@@ -1,9 +1,13 @@
 """Language parsers for symbol extraction."""
 
-from .base import Parser, Symbol
+from pathlib import PurePath
+from typing import IO, Union
+
+from .base import Field, Import, Param, Parser, ParseResult, Position, Symbol, TypeParam
 from .python_parser import PythonParser
+from .syntax import SyntaxTree
 
-__all__ = ["Parser", "Symbol", "PythonParser"]
+__all__ = ["Field", "Import", "Param", "Parser", "ParseResult", "Position", "Symbol", "SyntaxTree", "TypeParam", "PythonParser", "parse_reader"]
 
 # Optional tree-sitter parsers - each imports gracefully if grammar is available
 
@@ -149,3 +153,35 @@ def get_parser_for_extension(ext: str) -> type[Parser] | None:
         except (TypeError, AttributeError):
             continue
     return None
+
+
+def parse_reader(reader: IO[Union[str, bytes]], filename: str) -> ParseResult:
+    """Parse source code read from a stream rather than a file on disk.
+
+    Useful for code that only exists in memory, such as generated files or
+    blobs read from version control.
+
+    Args:
+        reader: Text or binary stream; bytes are decoded as UTF-8.
+        filename: Name used to pick the parser by extension and in error
+            messages. It doesn't need to exist on disk.
+
+    Returns:
+        ParseResult for the source. On a syntax error the result still holds
+        whatever symbols were recovered (none for Python) and error is set.
+
+    Raises:
+        ValueError: If no parser is available for the file extension.
+    """
+    parser_cls = get_parser_for_extension(PurePath(filename).suffix)
+    if parser_cls is None:
+        raise ValueError(f"No parser available for {filename}")
+
+    source = reader.read()
+    if isinstance(source, bytes):
+        source = source.decode("utf-8", errors="replace")
+
+    try:
+        return parser_cls().parse_file(source, filename)
+    except SyntaxError as e:
+        return ParseResult(symbols=[], error=str(e))
@@ -2,9 +2,232 @@
 
 from __future__ import annotations
 
+import hashlib
+import json
+import re
 from abc import ABC, abstractmethod
 from dataclasses import dataclass, field
-from typing import Optional
+from typing import Any, Optional
+
+# Longest signature stored in the index; longer ones are cut, ending in "..."
+MAX_SIGNATURE_LENGTH = 100
+
+# String and rune literals, words and single punctuation characters; whitespace
+# between them doesn't change a shape
+_TOKEN_RE = re.compile(r'"(?:[^"\\]|\\.)*"|`[^`]*`|\'(?:[^\'\\]|\\.)*\'|\w+|[^\w\s]')
+
+
+@dataclass(frozen=True)
+class Position:
+    """A source position: 1-based line and column, as go/token reports them.
+
+    Columns count bytes, and an end position is the column just after the
+    last character of the range.
+    """
+
+    file: str
+    line: int
+    column: int
+
+    def __str__(self) -> str:
+        return f"{self.file}:{self.line}:{self.column}"
+
+    def to_dict(self) -> dict:
+        """Convert position to dictionary for JSON serialization."""
+        return {"file": self.file, "line": self.line, "column": self.column}
+
+
+@dataclass
+class TypeParam:
+    """A generic type parameter, e.g. T with constraint "comparable"."""
+
+    name: str
+    constraint: Optional[str] = None
+
+    def to_dict(self) -> dict:
+        """Convert type parameter to dictionary for JSON serialization."""
+        result = {"name": self.name}
+        if self.constraint:
+            result["constraint"] = self.constraint
+        return result
+
+    @classmethod
+    def from_dict(cls, data: dict) -> "TypeParam":
+        """Create a TypeParam from a dictionary."""
+        return cls(name=data["name"], constraint=data.get("constraint"))
+
+
+@dataclass
+class Param:
+    """A function parameter or result, e.g. "id int" or an unnamed "error".
+
+    A variadic parameter "args ...string" has type "string" and variadic=True.
+    """
+
+    name: Optional[str]  # None for unnamed parameters and results; "_" is kept
+    type: str
+    variadic: bool = False
+    resolved_type: Optional[str] = None  # Type with import paths for qualifiers, e.g. "*github.com/x/other.Thing" (Go)
+
+    def __str__(self) -> str:
+        type_text = f"...{self.type}" if self.variadic else self.type
+        return f"{self.name} {type_text}" if self.name else type_text
+
+    def to_dict(self) -> dict:
+        """Convert parameter to dictionary for JSON serialization."""
+        result = {"type": self.type}
+        if self.name:
+            result["name"] = self.name
+        if self.variadic:
+            result["variadic"] = True
+        if self.resolved_type:
+            result["resolved_type"] = self.resolved_type
+        return result
+
+    @classmethod
+    def from_dict(cls, data: dict) -> "Param":
+        """Create a Param from a dictionary."""
+        return cls(
+            name=data.get("name"),
+            type=data["type"],
+            variadic=data.get("variadic", False),
+            resolved_type=data.get("resolved_type"),
+        )
+
+
+@dataclass
+class Field:
+    """A struct field. Embedded fields have no name; their type is the embedded type."""
+
+    name: Optional[str]
+    type: str
+    tag: Optional[str] = None  # Raw struct tag without quotes, e.g. json:"id"
+    embedded: bool = False
+    lines: Optional[tuple[int, int]] = None  # (start_line, end_line), 1-indexed
+    columns: Optional[tuple[int, int]] = None  # (start_column, end_column), 1-indexed bytes
+    deprecated: bool = False  # Doc comment has a "Deprecated:" paragraph
+    deprecation: Optional[str] = None  # Text of the "Deprecated:" paragraph
+    resolved_type: Optional[str] = None  # Type with import paths for qualifiers, as for Param (Go)
+
+    def pos(self, file: str) -> Optional[Position]:
+        """Start position of the field in a file, if known."""
+        return _position(file, self.lines, self.columns, 0)
+
+    def end_pos(self, file: str) -> Optional[Position]:
+        """End position of the field in a file, if known."""
+        return _position(file, self.lines, self.columns, 1)
+
+    def to_dict(self) -> dict:
+        """Convert field to dictionary for JSON serialization."""
+        result = {"type": self.type}
+        if self.name:
+            result["name"] = self.name
+        if self.tag:
+            result["tag"] = self.tag
+        if self.embedded:
+            result["embedded"] = True
+        if self.lines:
+            result["lines"] = list(self.lines)
+        if self.columns:
+            result["columns"] = list(self.columns)
+        if self.deprecated:
+            result["deprecated"] = True
+        if self.deprecation:
+            result["deprecation"] = self.deprecation
+        if self.resolved_type:
+            result["resolved_type"] = self.resolved_type
+        return result
+
+    @classmethod
+    def from_dict(cls, data: dict) -> "Field":
+        """Create a Field from a dictionary."""
+        return cls(
+            name=data.get("name"),
+            type=data["type"],
+            tag=data.get("tag"),
+            embedded=data.get("embedded", False),
+            lines=tuple(data["lines"]) if data.get("lines") else None,
+            columns=tuple(data["columns"]) if data.get("columns") else None,
+            deprecated=data.get("deprecated", False),
+            deprecation=data.get("deprecation"),
+            resolved_type=data.get("resolved_type"),
+        )
+
+
+@dataclass
+class Import:
+    """An imported package, e.g. path "net/http" with optional local name "h"."""
+
+    path: str
+    name: Optional[str] = None  # Alias, "." for dot imports or "_" for side-effect imports
+
+    def to_dict(self) -> dict:
+        """Convert import to dictionary for JSON serialization."""
+        result = {"path": self.path}
+        if self.name:
+            result["name"] = self.name
+        return result
+
+    @classmethod
+    def from_dict(cls, data: dict) -> "Import":
+        """Create an Import from a dictionary."""
+        return cls(path=data["path"], name=data.get("name"))
+
+
+@dataclass
+class EnumValue:
+    """A constant of a Go enum type, e.g. StatusActive = iota + 1 of type Status."""
+
+    name: str
+    value: Optional[str] = None  # Value as written; None for names repeating the previous spec's
+    int_value: Optional[int] = None  # Integer value, iota included, when it could be worked out
+
+    def to_dict(self) -> dict:
+        """Convert enum value to dictionary for JSON serialization."""
+        result: dict[str, Any] = {"name": self.name}
+        if self.value:
+            result["value"] = self.value
+        if self.int_value is not None:
+            result["int_value"] = self.int_value
+        return result
+
+    @classmethod
+    def from_dict(cls, data: dict) -> "EnumValue":
+        """Create an EnumValue from a dictionary."""
+        return cls(name=data["name"], value=data.get("value"), int_value=data.get("int_value"))
+
+
+@dataclass
+class Note:
+    """A work marker in a comment, e.g. "// TODO(alice): drop the v1 API"."""
+
+    kind: str  # Marker: "TODO", "FIXME", "XXX" or "HACK"
+    text: str  # Text after the marker, with continuation lines joined
+    line: int  # 1-indexed line of the marker
+    column: int  # 1-based byte column of the marker
+    author: Optional[str] = None  # Name in "TODO(name):", if given
+
+    def pos(self, file: str) -> Position:
+        """Position of the marker in a file."""
+        return Position(file, self.line, self.column)
+
+    def to_dict(self) -> dict:
+        """Convert note to dictionary for JSON serialization."""
+        result = {"kind": self.kind, "text": self.text, "line": self.line, "column": self.column}
+        if self.author:
+            result["author"] = self.author
+        return result
+
+    @classmethod
+    def from_dict(cls, data: dict) -> "Note":
+        """Create a Note from a dictionary."""
+        return cls(
+            kind=data["kind"],
+            text=data["text"],
+            line=data["line"],
+            column=data["column"],
+            author=data.get("author"),
+        )
 
 
 @dataclass
@@ -12,11 +235,87 @@ class Symbol:
     """Represents a code symbol (class, function, method, etc.)."""
 
     name: str
-    type: str  # class, function, method, async_function, async_method
+    type: str  # class, function, method, async_function, async_method, test, benchmark, ...
     lines: tuple[int, int]  # (start_line, end_line), 1-indexed
     signature: Optional[str] = None
     docstring: Optional[str] = None
     children: list["Symbol"] = field(default_factory=list)
+    exported: Optional[bool] = None  # Set by languages with an export convention (e.g. Go)
+    receiver: Optional[str] = None  # Receiver type for Go methods, e.g. "*Service"
+    embeds: list[str] = field(default_factory=list)  # Embedded types (Go structs/interfaces)
+    resolved_embeds: list[Optional[str]] = field(default_factory=list)  # embeds with import paths; see analysis.type_refs
+    implements: list[str] = field(default_factory=list)  # Interfaces this type satisfies
+    implemented_by: list[str] = field(default_factory=list)  # Types satisfying this interface
+    methods: list[str] = field(default_factory=list)  # Methods declared on this type anywhere in its package (Go)
+    type_params: list[TypeParam] = field(default_factory=list)  # Generic type parameters
+    params: list[Param] = field(default_factory=list)  # Function/method parameters, one per name
+    results: list[Param] = field(default_factory=list)  # Result parameters, named or not
+    fields: list[Field] = field(default_factory=list)  # Struct fields
+    value: Optional[str] = None  # Source text of a constant or variable's value
+    int_value: Optional[int] = None  # Integer value of a Go constant, from literals, iota and its block's names
+    enum_values: list[EnumValue] = field(default_factory=list)  # Grouped constants of a Go enum type, in order
+    group: Optional[str] = None  # First name of the const/var block this symbol was declared in
+    is_alias: bool = False  # Type alias (Go "type A = B") rather than a defined type
+    columns: Optional[tuple[int, int]] = None  # (start_column, end_column) on the start/end lines, 1-indexed bytes
+    in_test: bool = False  # Declared in a test file (Go _test.go)
+    generated: bool = False  # Declared in a generated file ("// Code generated ... DO NOT EDIT.")
+    deprecated: bool = False  # Doc comment has a "Deprecated:" paragraph
+    deprecation: Optional[str] = None  # Text of the "Deprecated:" paragraph
+    calls: list[str] = field(default_factory=list)  # Calls made in the body, e.g. "Service.repo.Get" (Go)
+    complexity: Optional[int] = None  # Cyclomatic complexity of a function or method body (Go)
+    body_lines: Optional[int] = None  # Lines between the braces of a function or method body (Go); None without a body
+    is_stub: bool = False  # Body is empty, a bare or nil return, or panic("not implemented") (Go)
+    metadata: dict[str, Any] = field(default_factory=dict)  # Free-form, JSON-serializable data set by visitors
+    id: Optional[str] = None  # Fully-qualified path, e.g. "example.com/app/sample.DefaultService.GetUser"; set by the indexer
+
+    def pos(self, file: str) -> Position:
+        """Start position of the symbol in a file; column 1 if columns weren't recorded."""
+        return _position(file, self.lines, self.columns, 0)
+
+    def end_pos(self, file: str) -> Optional[Position]:
+        """End position of the symbol in a file, or None if columns weren't recorded."""
+        return _position(file, self.lines, self.columns, 1) if self.columns else None
+
+    def content_hash(self) -> str:
+        """Hash the symbol's shape, to tell cheaply whether it changed.
+
+        The shape is the name, kind, signature, parameters, results, type
+        parameters, receiver, value, fields, embeds, methods, enum values
+        and nested symbols. The signature counts as stored, cut to
+        MAX_SIGNATURE_LENGTH, so a symbol keeps its hash when saved and
+        loaded; a change past the cut shows in its params and results.
+        Positions, doc comments and relations computed from other symbols
+        (implements, resolved types) are left out, and so is whitespace
+        between tokens, so moving or reformatting a symbol keeps its hash.
+
+        Returns:
+            First 12 characters of the SHA256 hex digest, like file hashes.
+        """
+        encoded = json.dumps(self._shape(), sort_keys=True, separators=(",", ":"))
+        return hashlib.sha256(encoded.encode("utf-8")).hexdigest()[:12]
+
+    def _shape(self) -> dict[str, Any]:
+        return {
+            "name": self.name,
+            "type": self.type,
+            "signature": _tokens(stored_signature(self.signature)),
+            "params": [_param_shape(p) for p in self.params],
+            "results": [_param_shape(r) for r in self.results],
+            "type_params": [[p.name, _tokens(p.constraint)] for p in self.type_params],
+            "receiver": _tokens(self.receiver),
+            "value": _tokens(self.value),
+            "is_alias": self.is_alias,
+            "fields": [[f.name, _tokens(f.type), f.tag, f.embedded] for f in self.fields],
+            "embeds": [_tokens(e) for e in self.embeds],
+            "methods": sorted(self.methods),
+            "enum_values": [[v.name, v.int_value] for v in self.enum_values],
+            "children": [c._shape() for c in self.children],
+        }
+
+    @property
+    def is_enum(self) -> bool:
+        """Whether this is a Go type with a const block of values of its type."""
+        return bool(self.enum_values)
 
     def to_dict(self) -> dict:
         """Convert symbol to dictionary for JSON serialization."""
@@ -26,15 +325,67 @@ class Symbol:
             "lines": list(self.lines),
         }
         if self.signature:
-            # Truncate long signatures
-            sig = self.signature if len(self.signature) <= 100 else self.signature[:97] + "..."
-            result["signature"] = sig
+            result["signature"] = stored_signature(self.signature)
         if self.docstring:
             # Truncate long docstrings
             doc = self.docstring.strip()
             result["docstring"] = doc[:150] if len(doc) > 150 else doc
         if self.children:
             result["children"] = [c.to_dict() for c in self.children]
+        if self.exported is not None:
+            result["exported"] = self.exported
+        if self.receiver:
+            result["receiver"] = self.receiver
+        if self.embeds:
+            result["embeds"] = list(self.embeds)
+        if self.resolved_embeds:
+            result["resolved_embeds"] = list(self.resolved_embeds)
+        if self.implements:
+            result["implements"] = list(self.implements)
+        if self.implemented_by:
+            result["implemented_by"] = list(self.implemented_by)
+        if self.methods:
+            result["methods"] = list(self.methods)
+        if self.type_params:
+            result["type_params"] = [p.to_dict() for p in self.type_params]
+        if self.params:
+            result["params"] = [p.to_dict() for p in self.params]
+        if self.results:
+            result["results"] = [r.to_dict() for r in self.results]
+        if self.fields:
+            result["fields"] = [f.to_dict() for f in self.fields]
+        if self.value:
+            result["value"] = self.value
+        if self.int_value is not None:
+            result["int_value"] = self.int_value
+        if self.enum_values:
+            result["enum_values"] = [v.to_dict() for v in self.enum_values]
+        if self.group:
+            result["group"] = self.group
+        if self.is_alias:
+            result["is_alias"] = True
+        if self.columns:
+            result["columns"] = list(self.columns)
+        if self.in_test:
+            result["in_test"] = True
+        if self.generated:
+            result["generated"] = True
+        if self.deprecated:
+            result["deprecated"] = True
+        if self.deprecation:
+            result["deprecation"] = self.deprecation
+        if self.calls:
+            result["calls"] = list(self.calls)
+        if self.complexity is not None:
+            result["complexity"] = self.complexity
+        if self.body_lines is not None:
+            result["body_lines"] = self.body_lines
+        if self.is_stub:
+            result["is_stub"] = True
+        if self.metadata:
+            result["metadata"] = dict(self.metadata)
+        if self.id:
+            result["id"] = self.id
         return result
 
     @classmethod
@@ -48,9 +399,73 @@ class Symbol:
             signature=data.get("signature"),
             docstring=data.get("docstring"),
             children=children if children else [],
+            exported=data.get("exported"),
+            receiver=data.get("receiver"),
+            embeds=data.get("embeds", []),
+            resolved_embeds=data.get("resolved_embeds", []),
+            implements=data.get("implements", []),
+            implemented_by=data.get("implemented_by", []),
+            methods=data.get("methods", []),
+            type_params=[TypeParam.from_dict(p) for p in data.get("type_params", [])],
+            params=[Param.from_dict(p) for p in data.get("params", [])],
+            results=[Param.from_dict(r) for r in data.get("results", [])],
+            fields=[Field.from_dict(f) for f in data.get("fields", [])],
+            value=data.get("value"),
+            int_value=data.get("int_value"),
+            enum_values=[EnumValue.from_dict(v) for v in data.get("enum_values", [])],
+            group=data.get("group"),
+            is_alias=data.get("is_alias", False),
+            columns=tuple(data["columns"]) if data.get("columns") else None,
+            in_test=data.get("in_test", False),
+            generated=data.get("generated", False),
+            deprecated=data.get("deprecated", False),
+            deprecation=data.get("deprecation"),
+            calls=data.get("calls", []),
+            complexity=data.get("complexity"),
+            body_lines=data.get("body_lines"),
+            is_stub=data.get("is_stub", False),
+            metadata=data.get("metadata", {}),
+            id=data.get("id"),
         )
 
 
+def stored_signature(signature: Optional[str]) -> Optional[str]:
+    """Cut a signature to the MAX_SIGNATURE_LENGTH characters the index keeps of it."""
+    if signature is None or len(signature) <= MAX_SIGNATURE_LENGTH:
+        return signature
+    return signature[:MAX_SIGNATURE_LENGTH - 3] + "..."
+
+
+def _param_shape(param: Param) -> list:
+    return [param.name, _tokens(param.type), param.variadic]
+
+
+def _tokens(text: Optional[str]) -> Optional[str]:
+    """Normalize the whitespace of a type or expression, e.g. "( id  int )" -> "( id int )"."""
+    return " ".join(_TOKEN_RE.findall(text)) if text is not None else None
+
+
+def _position(
+    file: str, lines: Optional[tuple[int, int]], columns: Optional[tuple[int, int]], index: int
+) -> Optional[Position]:
+    """Build the start (index 0) or end (index 1) position from line and column ranges."""
+    if not lines:
+        return None
+    return Position(file=file, line=lines[index], column=columns[index] if columns else 1)
+
+
+@dataclass
+class ParseResult:
+    """Symbols plus file-level details extracted by a parser."""
+
+    symbols: list[Symbol]
+    package: Optional[str] = None  # Package/namespace declared by the file, if any
+    imports: list[Import] = field(default_factory=list)  # Packages the file imports
+    error: Optional[str] = None  # Syntax error, if symbols are only what parsed before/around it
+    notes: list[Note] = field(default_factory=list)  # TODO/FIXME/XXX/HACK comments, in source order
+    package_doc: Optional[str] = None  # Doc comment of the package clause
+
+
 class Parser(ABC):
     """Abstract base class for language parsers."""
 
@@ -76,6 +491,39 @@ class Parser(ABC):
         """
         pass
 
+    def parse_file(self, source: str, filepath: str = "") -> ParseResult:
+        """Parse source code and return symbols with file-level details.
+
+        Parsers that know more about a file than its symbols (such as the
+        package it declares) override this; the default wraps parse().
+
+        Args:
+            source: The source code to parse.
+            filepath: Optional file path for error messages.
+
+        Returns:
+            ParseResult for the file.
+
+        Raises:
+            SyntaxError: If the source code has syntax errors.
+        """
+        return ParseResult(symbols=self.parse(source, filepath))
+
+    def syntax_tree(self, source: str, filepath: str = "") -> Optional[Any]:
+        """Parse source into the parser's own syntax tree.
+
+        Used by indexers that retain trees (see parsers.syntax); parsers
+        without a tree to offer return None, the default.
+
+        Args:
+            source: The source code to parse.
+            filepath: Optional file path, for parsers that depend on it.
+
+        Returns:
+            A tree_sitter.Tree or ast.Module, or None.
+        """
+        return None
+
     def can_parse(self, filepath: str) -> bool:
         """Check if this parser can handle the given file.
 
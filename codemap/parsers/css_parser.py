@@ -44,6 +44,10 @@ class CssParser(Parser):
         tree = self._parser.parse(source_bytes)
         return self._extract_symbols(tree.root_node, source_bytes)
 
+    def syntax_tree(self, source: str, filepath: str = ""):
+        """Parse CSS source into a tree-sitter tree."""
+        return self._parser.parse(source.encode("utf-8"))
+
     def _extract_symbols(self, node, source_bytes: bytes) -> list[Symbol]:
         """Extract symbols from AST node."""
         symbols = []
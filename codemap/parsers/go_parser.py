@@ -2,7 +2,13 @@
 
 from __future__ import annotations
 
+import json
+import re
+from typing import Iterator, Optional
+
+from .base import Field, Import, Param, ParseResult, Symbol, TypeParam
 from .treesitter_base import TreeSitterParser, LanguageConfig, NodeMapping
+from ..utils.file_utils import is_generated
 
 
 def _is_async(node) -> bool:
@@ -49,7 +55,904 @@ GO_CONFIG = LanguageConfig(
 )
 
 
+def _columns(node: "Node", end: Optional["Node"] = None) -> tuple[int, int]:
+    """1-based start and end byte columns of a node (or from node to end), as go/token counts them."""
+    return (node.start_point[1] + 1, (end or node).end_point[1] + 1)
+
+
+def _body_lines(node: "Node") -> Optional[int]:
+    """Count the lines a function body holds between its braces, or None without a body.
+
+    The count runs from the first statement or comment inside the braces
+    to the last, wherever the braces are: "{}" and "{\n}" have 0 lines,
+    "{ return x }" has 1, and blank lines before the first statement or
+    after the last don't count.
+    """
+    body = node.child_by_field_name("body")
+    if body is None:
+        return None
+    inside = body.named_children
+    if not inside:
+        return 0
+    return inside[-1].end_point[0] - inside[0].start_point[0] + 1
+
+
+def _statements(block: "Node") -> list["Node"]:
+    """Statements of a block, without comments, in grammars with and without a statement_list node."""
+    statements = []
+    for child in block.named_children:
+        if child.type == "statement_list":
+            statements.extend(c for c in child.named_children if c.type != "comment")
+        elif child.type != "comment":
+            statements.append(child)
+    return statements
+
+
+# Comment lines that are directives, not documentation, as go/ast.CommentGroup.Text drops them
+_DIRECTIVE_RE = re.compile(r"//(?:line |extern |export |[a-z0-9]+:[a-z0-9])")
+
+# Function name prefix -> symbol type for functions run by "go test"
+TEST_FUNCTION_KINDS = {
+    "Test": "test",
+    "Benchmark": "benchmark",
+    "Example": "example",
+    "Fuzz": "fuzz",
+}
+
+
+def classify_test_function(name: str) -> Optional[str]:
+    """Classify a test-file function by the go test naming rules.
+
+    "TestParse" and "Test_parse" are tests but "Testify" isn't: the prefix
+    must be followed by nothing or a character that isn't a lower-case letter.
+    """
+    for prefix, kind in TEST_FUNCTION_KINDS.items():
+        rest = name[len(prefix):]
+        if name.startswith(prefix) and not rest[:1].islower():
+            return kind
+    return None
+
+
+def deprecation_notice(doc: Optional[str]) -> Optional[str]:
+    """Get the text of a "Deprecated:" paragraph in a Go doc comment.
+
+    The paragraph runs to the next blank line; its lines are joined with
+    spaces. Returns None if the comment has no such paragraph, and "" for a
+    bare "Deprecated:".
+    """
+    if not doc:
+        return None
+    for paragraph in re.split(r"\n\s*\n", doc):
+        paragraph = paragraph.strip()
+        if paragraph.startswith("Deprecated:"):
+            return " ".join(line.strip() for line in paragraph[len("Deprecated:"):].splitlines()).strip()
+    return None
+
+
+def _walk(symbols: list[Symbol]) -> Iterator[Symbol]:
+    """Yield symbols and all their nested children."""
+    stack = list(symbols)
+    while stack:
+        symbol = stack.pop()
+        yield symbol
+        stack.extend(symbol.children)
+
+
+# Predeclared functions, which are never recorded as calls
+_BUILTINS = {
+    "append", "cap", "clear", "close", "complex", "copy", "delete", "imag", "len", "make",
+    "max", "min", "new", "panic", "print", "println", "real", "recover",
+}
+
+# Statements that add a path through a function, as counted by gocyclo; "default" cases don't
+_DECISION_NODES = {"if_statement", "for_statement", "expression_case", "type_case", "communication_case"}
+
+# Panic messages of a placeholder body, matched lowercased: "not implemented", ErrUnimplemented
+_STUB_PANIC_RE = re.compile(r"implemented|todo")
+
+# Largest shift count evaluated in constant expressions, keeping values small
+_MAX_SHIFT = 256
+
+# Called expressions that may name the type of a conversion, e.g. Weekday or time.Duration
+_CONVERSION_NODES = {"identifier", "type_identifier", "selector_expression", "qualified_type"}
+
+
+# Single-character escapes of rune literals
+_RUNE_ESCAPES = {"a": 7, "b": 8, "f": 12, "n": 10, "r": 13, "t": 9, "v": 11, "\\": 92, "'": 39}
+
+
+def _rune_value(inner: str) -> Optional[int]:
+    """Code point of a rune literal's contents, e.g. "a", "\\n", "\\x41" or "\\u00e9"."""
+    if len(inner) == 1:
+        return ord(inner)
+    if not inner.startswith("\\"):
+        return None
+    if len(inner) == 2:
+        return _RUNE_ESCAPES.get(inner[1])
+    try:
+        if inner[1] in "xuU":
+            return int(inner[2:], 16)
+        return int(inner[1:], 8)  # Octal, e.g. "\\101"
+    except ValueError:
+        return None
+
+
+def _integer_operation(operator: str, left: int, right: int) -> Optional[int]:
+    """Apply a Go binary operator to integer constants; None for others and invalid operands."""
+    if operator in ("/", "%"):
+        if right == 0:
+            return None
+        quotient = abs(left) // abs(right) * (1 if (left < 0) == (right < 0) else -1)  # Truncated, as in Go
+        return quotient if operator == "/" else left - quotient * right
+    if operator in ("<<", ">>") and not 0 <= right <= _MAX_SHIFT:
+        return None
+    operations = {
+        "+": lambda: left + right,
+        "-": lambda: left - right,
+        "*": lambda: left * right,
+        "<<": lambda: left << right,
+        ">>": lambda: left >> right,
+        "&": lambda: left & right,
+        "|": lambda: left | right,
+        "^": lambda: left ^ right,
+        "&^": lambda: left & ~right,
+    }
+    operation = operations.get(operator)
+    return operation() if operation is not None else None
+
+
+# A named type, possibly a pointer or instantiated: "User", "*Set[T]"
+_NAMED_TYPE_RE = re.compile(r"\*?\s*([A-Za-z_]\w*)\s*(\[.*\])?")
+
+
+def _type_chain(type_text: Optional[str]) -> Optional[list[str]]:
+    """Start a call chain from a type, e.g. "*Set[T]" -> ["Set"]; None for unnamed or qualified types."""
+    match = _NAMED_TYPE_RE.fullmatch((type_text or "").strip())
+    if match is None or match.group(1) in ("map", "chan", "func", "struct", "interface"):
+        return None
+    return [match.group(1)]
+
+
+def is_exported(name: str) -> bool:
+    """Check if a Go identifier is exported (starts with an upper-case letter)."""
+    return name[:1].isupper()
+
+
 class GoParser(TreeSitterParser):
-    """Parser for Go files using tree-sitter."""
+    """Parser for Go files using tree-sitter.
+
+    Supports:
+    - Package clause, its doc comment and imports (reported on the ParseResult,
+      not as symbols)
+    - Functions and methods (with receiver type)
+    - Structs (with fields and tags), interfaces (with method elements) and
+      other type declarations
+    - Type parameters on generic functions, types and their methods
+    - Package-level const and var declarations, keeping "const (...)" groups
+      together, and type aliases
+    - "Deprecated:" paragraphs in doc comments of symbols and struct fields
+    - Test files: symbols are marked in_test, and Test/Benchmark/Example/Fuzz
+      functions get their own symbol types
+    - Generated files ("// Code generated ... DO NOT EDIT."): symbols are
+      marked generated
+    - Calls made in function and method bodies, for the call graph
+    - TODO/FIXME/XXX/HACK markers in any comment (reported on the ParseResult)
+    """
 
     config = GO_CONFIG
+    extensions = [".go"]
+    language = "go"
+
+    def parse(self, source: str, filepath: str = "") -> list[Symbol]:
+        """Parse Go source code and extract symbols."""
+        return self.parse_file(source, filepath).symbols
+
+    def parse_file(self, source: str, filepath: str = "") -> ParseResult:
+        """Parse Go source code and extract symbols plus the package name.
+
+        tree-sitter recovers from syntax errors, so a file that doesn't parse
+        cleanly still yields the declarations outside the broken region, with
+        the first error described on the result.
+        """
+        source_bytes = source.encode("utf-8")
+        tree = self._parser.parse(source_bytes)
+        root = tree.root_node
+
+        package = None
+        package_doc = None
+        symbols = []
+        imports: list[Import] = []
+        for child in root.children:
+            if child.type == "package_clause":
+                package = self._package_name(child, source_bytes)
+                package_doc = self._doc_comment(child, source_bytes)
+            elif child.type == "import_declaration":
+                imports.extend(self._parse_imports(child, source_bytes))
+            elif child.type == "function_declaration":
+                symbols.append(self._parse_function(child, source_bytes))
+            elif child.type == "method_declaration":
+                symbols.append(self._parse_method(child, source_bytes))
+            elif child.type == "type_declaration":
+                symbols.extend(self._parse_type_declaration(child, source_bytes))
+            elif child.type in ("const_declaration", "var_declaration"):
+                symbols.extend(self._parse_value_declaration(child, source_bytes))
+
+        self._mark_deprecated(symbols)
+        if filepath.endswith("_test.go"):
+            self._mark_test_symbols(symbols)
+        if is_generated(source):
+            for symbol in _walk(symbols):
+                symbol.generated = True
+
+        error = self._syntax_error(root, filepath) if root.has_error else None
+        notes = self._extract_notes(root, source_bytes)
+        return ParseResult(
+            symbols=symbols, package=package, imports=imports, error=error, notes=notes, package_doc=package_doc
+        )
+
+    def _mark_deprecated(self, symbols: list[Symbol]) -> None:
+        """Flag symbols whose doc comment has a "Deprecated:" paragraph."""
+        for symbol in _walk(symbols):
+            notice = deprecation_notice(symbol.docstring)
+            if notice is not None:
+                symbol.deprecated = True
+                symbol.deprecation = notice or None
+
+    def _mark_test_symbols(self, symbols: list[Symbol]) -> None:
+        """Flag symbols from a _test.go file and classify its test functions."""
+        for symbol in _walk(symbols):
+            symbol.in_test = True
+        for symbol in symbols:
+            if symbol.type == "function":
+                symbol.type = classify_test_function(symbol.name) or symbol.type
+
+    def _syntax_error(self, root: "Node", filepath: str) -> str:
+        """Describe the first syntax error in a tree, e.g. "main.go:3:5: syntax error"."""
+        stack = [root]
+        while stack:
+            node = stack.pop()
+            if node.type == "ERROR" or getattr(node, "is_missing", False):
+                row, col = node.start_point
+                return f"{filepath or '<string>'}:{row + 1}:{col + 1}: syntax error"
+            if node.has_error:
+                stack.extend(reversed(node.children))
+        return f"{filepath or '<string>'}: syntax error"
+
+    def _package_name(self, node: "Node", source_bytes: bytes) -> Optional[str]:
+        """Get the package name from a package_clause node."""
+        name_node = self._find_child(node, "package_identifier")
+        return self._get_node_text(name_node, source_bytes) or None
+
+    def _parse_imports(self, node: "Node", source_bytes: bytes) -> list[Import]:
+        """Parse an import declaration, which may hold a parenthesized list of specs."""
+        specs = []
+        for child in node.children:
+            if child.type == "import_spec":
+                specs.append(child)
+            elif child.type == "import_spec_list":
+                specs.extend(c for c in child.children if c.type == "import_spec")
+
+        imports = []
+        for spec in specs:
+            path = self._get_node_text(spec.child_by_field_name("path"), source_bytes).strip('"`')
+            name = self._get_node_text(spec.child_by_field_name("name"), source_bytes) or None
+            if path:
+                imports.append(Import(path=path, name=name))
+        return imports
+
+    def _parse_function(self, node: "Node", source_bytes: bytes) -> Symbol:
+        """Parse a top-level function declaration."""
+        name = self._get_node_text(node.child_by_field_name("name"), source_bytes)
+        signature = self._signature(node, source_bytes)
+        type_params_node = node.child_by_field_name("type_parameters")
+        if type_params_node is not None and signature is not None:
+            # Keep the signature readable as Go: "[T any](s []T) T"
+            signature = self._get_node_text(type_params_node, source_bytes) + signature
+        return Symbol(
+            name=name,
+            type="function",
+            lines=(node.start_point[0] + 1, node.end_point[0] + 1),
+            columns=_columns(node),
+            signature=signature,
+            docstring=self._doc_comment(node, source_bytes),
+            exported=is_exported(name),
+            type_params=self._type_params(type_params_node, source_bytes),
+            params=self._params(node.child_by_field_name("parameters"), source_bytes),
+            results=self._results(node.child_by_field_name("result"), source_bytes),
+            calls=self._calls(node, source_bytes),
+            complexity=self._complexity(node, source_bytes),
+            body_lines=_body_lines(node),
+            is_stub=self._is_stub(node, source_bytes),
+        )
+
+    def _parse_method(self, node: "Node", source_bytes: bytes) -> Symbol:
+        """Parse a method declaration, recording its receiver type."""
+        name = self._get_node_text(node.child_by_field_name("name"), source_bytes)
+        receiver = self._receiver_type(node, source_bytes)
+        return Symbol(
+            name=name,
+            type="method",
+            lines=(node.start_point[0] + 1, node.end_point[0] + 1),
+            columns=_columns(node),
+            signature=self._signature(node, source_bytes),
+            docstring=self._doc_comment(node, source_bytes),
+            exported=is_exported(name),
+            receiver=receiver,
+            type_params=self._receiver_type_params(node, source_bytes),
+            params=self._params(node.child_by_field_name("parameters"), source_bytes),
+            results=self._results(node.child_by_field_name("result"), source_bytes),
+            calls=self._calls(node, source_bytes),
+            complexity=self._complexity(node, source_bytes),
+            body_lines=_body_lines(node),
+            is_stub=self._is_stub(node, source_bytes),
+        )
+
+    def _calls(self, node: "Node", source_bytes: bytes) -> list[str]:
+        """Record the calls in a function body as chains of names, without duplicates.
+
+        A chain starts at a package-level name and follows fields and call
+        results to the callee: "helper", "Service.repo.Get", "NewStore().Open".
+        Local variables are replaced by their type or value where the
+        declaration shows it (receivers, parameters, "x := &T{}",
+        "x := NewT()"); calls on locals of unknown type, such as range
+        variables, are left out. Scoping is flat: a name declared anywhere
+        in the body, including function literals, counts for all of it.
+        """
+        body = node.child_by_field_name("body")
+        if body is None:
+            return []
+        scope: dict[str, Optional[list[str]]] = {}
+        for name in ("receiver", "parameters", "result"):
+            self._declare_params(node.child_by_field_name(name), source_bytes, scope)
+
+        calls: list[str] = []
+        stack = [body]
+        while stack:
+            current = stack.pop()
+            if current.type == "call_expression":
+                chain = self._chain(self._callee(current), source_bytes, scope)
+                call = ".".join(chain) if chain else None
+                if call and call not in _BUILTINS and call not in calls:
+                    calls.append(call)
+            elif current.type == "short_var_declaration":
+                left = current.child_by_field_name("left")
+                names = left.named_children if left is not None else []
+                self._declare_values(names, current.child_by_field_name("right"), source_bytes, scope)
+            elif current.type == "var_spec":
+                names = current.children_by_field_name("name")
+                type_node = current.child_by_field_name("type")
+                if type_node is None:
+                    self._declare_values(names, current.child_by_field_name("value"), source_bytes, scope)
+                else:
+                    chain = _type_chain(self._get_node_text(type_node, source_bytes))
+                    for name_node in names:
+                        scope[self._get_node_text(name_node, source_bytes)] = chain
+            elif current.type == "func_literal":
+                self._declare_params(current.child_by_field_name("parameters"), source_bytes, scope)
+            elif current.type in ("range_clause", "type_switch_statement"):
+                target = current.child_by_field_name("left") or current.child_by_field_name("alias")
+                for name_node in (target.named_children if target is not None else []):
+                    scope[self._get_node_text(name_node, source_bytes)] = None
+            stack.extend(reversed(current.children))
+        return calls
+
+    def _complexity(self, node: "Node", source_bytes: bytes) -> Optional[int]:
+        """Compute the cyclomatic complexity of a function body: one plus its decision points.
+
+        Decision points are if and for statements (range loops included),
+        non-default switch and select cases, and each && and || operator.
+        Function literals aren't reported separately; their decision points
+        count toward the enclosing function, like the calls they make.
+        Declarations without a body (assembly stubs) get None.
+        """
+        body = node.child_by_field_name("body")
+        if body is None:
+            return None
+        complexity = 1
+        stack = [body]
+        while stack:
+            current = stack.pop()
+            if current.type in _DECISION_NODES:
+                complexity += 1
+            elif current.type == "binary_expression":
+                operator = current.child_by_field_name("operator")
+                if operator is not None and self._get_node_text(operator, source_bytes) in ("&&", "||"):
+                    complexity += 1
+            stack.extend(current.children)
+        return complexity
+
+    def _is_stub(self, node: "Node", source_bytes: bytes) -> bool:
+        """Tell whether a function body is a placeholder.
+
+        Placeholders are bodies with no statements (comments aside) or only
+        one of: a bare return, a return of nothing but nil, or a panic whose
+        argument mentions "implemented" or "TODO", e.g.
+        panic("not implemented") or panic(ErrUnimplemented). Declarations
+        without a body aren't stubs.
+        """
+        body = node.child_by_field_name("body")
+        if body is None:
+            return False
+        statements = _statements(body)
+        if not statements:
+            return True
+        if len(statements) > 1:
+            return False
+        statement = statements[0]
+        if statement.type == "return_statement":
+            values = next((c for c in statement.named_children if c.type == "expression_list"), None)
+            return values is None or all(v.type == "nil" for v in values.named_children)
+        if statement.type == "expression_statement" and statement.named_children:
+            call = statement.named_children[0]
+            if call.type != "call_expression":
+                return False
+            arguments = self._get_node_text(call.child_by_field_name("arguments"), source_bytes)
+            return (
+                self._get_node_text(call.child_by_field_name("function"), source_bytes) == "panic"
+                and _STUB_PANIC_RE.search(arguments.lower()) is not None
+            )
+        return False
+
+    def _callee(self, call: "Node") -> Optional["Node"]:
+        """Get the called expression of a call, without explicit type arguments."""
+        function = call.child_by_field_name("function")
+        if function is not None and function.type == "index_expression":
+            return function.child_by_field_name("operand")  # F[int](x) in grammars without type_arguments
+        return function
+
+    def _chain(self, node: Optional["Node"], source_bytes: bytes, scope: dict) -> Optional[list[str]]:
+        """Follow an expression back to a package-level name, or None if it can't be followed."""
+        if node is None:
+            return None
+        if node.type == "identifier":
+            name = self._get_node_text(node, source_bytes)
+            if name in scope:
+                return list(scope[name]) if scope[name] else None
+            return [name]
+        if node.type == "selector_expression":
+            base = self._chain(node.child_by_field_name("operand"), source_bytes, scope)
+            field_node = node.child_by_field_name("field")
+            return base + [self._get_node_text(field_node, source_bytes)] if base and field_node else None
+        if node.type == "call_expression":
+            function = self._callee(node)
+            if function is not None and self._get_node_text(function, source_bytes) == "new" and "new" not in scope:
+                args = node.child_by_field_name("arguments")
+                if args is None or not args.named_children:
+                    return None
+                return _type_chain(self._get_node_text(args.named_children[0], source_bytes))
+            base = self._chain(function, source_bytes, scope)
+            return base[:-1] + [base[-1] + "()"] if base else None
+        if node.type in ("composite_literal", "type_assertion_expression"):
+            return _type_chain(self._get_node_text(node.child_by_field_name("type"), source_bytes))
+        if node.type == "unary_expression" and (self._find_child(node, "&") or self._find_child(node, "*")):
+            return self._chain(node.child_by_field_name("operand"), source_bytes, scope)
+        if node.type == "parenthesized_expression" and node.named_children:
+            return self._chain(node.named_children[0], source_bytes, scope)
+        return None
+
+    def _declare_params(self, node: Optional["Node"], source_bytes: bytes, scope: dict) -> None:
+        """Add the named parameters of a parameter_list to a call scope, typed by their declaration."""
+        if node is None or node.type != "parameter_list":
+            return
+        for decl in node.named_children:
+            chain = _type_chain(self._get_node_text(decl.child_by_field_name("type"), source_bytes))
+            if decl.type == "variadic_parameter_declaration":
+                chain = None  # A slice of the type
+            for name_node in decl.children_by_field_name("name"):
+                scope[self._get_node_text(name_node, source_bytes)] = chain
+
+    def _declare_values(self, names: list["Node"], right: Optional["Node"], source_bytes: bytes, scope: dict) -> None:
+        """Add names assigned from values ("a, b := x, y" or "v, err := f()") to a call scope."""
+        values = right.named_children if right is not None else []
+        for i, name_node in enumerate(names):
+            if len(values) == len(names):
+                value = values[i]
+            else:
+                value = values[0] if i == 0 and len(values) == 1 else None  # First result of a call
+            chain = self._chain(value, source_bytes, scope) if value is not None else None
+            scope[self._get_node_text(name_node, source_bytes)] = chain
+
+    def _receiver_type(self, node: "Node", source_bytes: bytes) -> Optional[str]:
+        """Get the receiver type of a method, e.g. "*DefaultService"."""
+        receiver = node.child_by_field_name("receiver")
+        if receiver is None:
+            return None
+        for param in receiver.named_children:
+            type_node = param.child_by_field_name("type")
+            if type_node is not None:
+                return self._get_node_text(type_node, source_bytes)
+        return None
+
+    def _receiver_type_params(self, node: "Node", source_bytes: bytes) -> list[TypeParam]:
+        """Get the type parameters bound by a generic receiver, e.g. T in "*Set[T]".
+
+        Constraints are declared on the receiver's type, so they are left unset.
+        """
+        receiver = node.child_by_field_name("receiver")
+        for param in receiver.named_children if receiver is not None else []:
+            type_node = param.child_by_field_name("type")
+            if type_node is not None and type_node.type == "pointer_type":
+                type_node = type_node.named_children[0] if type_node.named_children else None
+            if type_node is None or type_node.type != "generic_type":
+                continue
+            args = type_node.child_by_field_name("type_arguments")
+            if args is None:
+                return []
+            return [TypeParam(name=self._get_node_text(a, source_bytes)) for a in args.named_children]
+        return []
+
+    def _type_params(self, node: Optional["Node"], source_bytes: bytes) -> list[TypeParam]:
+        """Parse a type_parameter_list like "[K comparable, V any]"."""
+        if node is None:
+            return []
+        params = []
+        for decl in node.named_children:
+            if decl.type != "type_parameter_declaration":
+                continue
+            constraint_node = decl.child_by_field_name("type")
+            constraint = self._get_node_text(constraint_node, source_bytes) or None
+            for name_node in decl.children_by_field_name("name"):
+                params.append(TypeParam(name=self._get_node_text(name_node, source_bytes), constraint=constraint))
+        return params
+
+    def _parse_type_declaration(self, node: "Node", source_bytes: bytes) -> list[Symbol]:
+        """Parse a type declaration, which may group several type specs."""
+        specs = [c for c in node.children if c.type in ("type_spec", "type_alias")]
+        if len(specs) == 1:
+            # Single spec: the declaration (including "type") is the symbol range
+            return [self._parse_type_spec(specs[0], node, source_bytes)]
+        return [self._parse_type_spec(spec, spec, source_bytes) for spec in specs]
+
+    def _parse_type_spec(self, spec: "Node", outer: "Node", source_bytes: bytes) -> Symbol:
+        """Parse a single type spec into a struct, interface, or type symbol.
+
+        Args:
+            spec: The type_spec node.
+            outer: Node whose range and doc comment belong to the symbol.
+            source_bytes: Original source code as bytes.
+        """
+        name = self._get_node_text(spec.child_by_field_name("name"), source_bytes)
+        type_node = spec.child_by_field_name("type")
+
+        symbol_type = "type"
+        signature = None
+        children: list[Symbol] = []
+        embeds: list[str] = []
+        fields: list[Field] = []
+        if type_node is not None and type_node.type == "struct_type":
+            symbol_type = "struct"
+            fields = self._struct_fields(type_node, source_bytes)
+            embeds = [f.type for f in fields if f.embedded]
+        elif type_node is not None and type_node.type == "interface_type":
+            symbol_type = "interface"
+            children = self._parse_interface_methods(type_node, source_bytes)
+            embeds = self._interface_embeds(type_node, source_bytes)
+        elif type_node is not None:
+            signature = self._get_node_text(type_node, source_bytes)
+
+        return Symbol(
+            name=name,
+            type=symbol_type,
+            lines=(outer.start_point[0] + 1, outer.end_point[0] + 1),
+            columns=_columns(outer),
+            signature=signature,
+            docstring=self._doc_comment(outer, source_bytes),
+            children=children,
+            exported=is_exported(name),
+            embeds=embeds,
+            fields=fields,
+            type_params=self._type_params(spec.child_by_field_name("type_parameters"), source_bytes),
+            is_alias=spec.type == "type_alias",
+        )
+
+    def _parse_value_declaration(self, node: "Node", source_bytes: bytes) -> list[Symbol]:
+        """Parse a const or var declaration into one symbol per declared name.
+
+        Names declared in a parenthesized block share a group (the block's
+        first name), so enum-style sets stay together. Constants without a
+        type or value repeat the previous spec's type, as in Go's implicit
+        repetition of iota expressions. The block's doc comment is kept on
+        its first symbol unless that symbol has its own. Constants whose
+        value is an integer expression of literals, iota and earlier names
+        of the block get its value as int_value, repeated specs included.
+        """
+        kind = "const" if node.type == "const_declaration" else "var"
+        specs = []
+        for child in node.children:
+            if child.type == f"{kind}_spec":
+                specs.append(child)
+            elif child.type == "var_spec_list":
+                specs.extend(c for c in child.children if c.type == "var_spec")
+        grouped = any(c.type in ("(", "var_spec_list") for c in node.children)
+
+        symbols: list[Symbol] = []
+        previous_type: Optional[str] = None
+        previous_values: list["Node"] = []
+        known: dict[str, int] = {}  # Integer values of the block's names so far
+        for iota, spec in enumerate(specs):
+            type_node = spec.child_by_field_name("type")
+            value_node = spec.child_by_field_name("value")
+            values = value_node.named_children if value_node is not None else []
+            declared = self._get_node_text(type_node, source_bytes) if type_node is not None else None
+            if kind == "const" and value_node is None and declared is None:
+                declared = previous_type  # Implicit repetition of the previous spec
+            elif kind == "const":
+                previous_type = declared or (self._infer_type(values[0], source_bytes) if values else None)
+                previous_values = values
+            outer = spec if grouped else node
+            doc = self._doc_comment(spec, source_bytes) if grouped else self._doc_comment(node, source_bytes)
+            if grouped and doc is None:
+                doc = self._line_comment(spec, source_bytes)
+
+            names = spec.children_by_field_name("name")
+            for i, name_node in enumerate(names):
+                name = self._get_node_text(name_node, source_bytes)
+                value = values[i] if len(values) == len(names) else None
+                value_text = self._get_node_text(value, source_bytes) if value is not None else None
+                if value is None and value_node is not None:
+                    value_text = self._get_node_text(value_node, source_bytes)  # e.g. "a, b = f()"
+                value_type = declared or (self._infer_type(value, source_bytes) if value is not None else None)
+                int_value = None
+                if kind == "const":
+                    expression = value if value_node is not None else (
+                        previous_values[i] if len(previous_values) == len(names) else None
+                    )
+                    if expression is not None:
+                        int_value = self._const_int(expression, source_bytes, iota, known)
+                    if int_value is not None and name != "_":
+                        known[name] = int_value
+                if name == "_":
+                    continue  # Blank identifiers, e.g. interface assertions, aren't part of the API
+                symbols.append(Symbol(
+                    name=name,
+                    type=kind,
+                    lines=(outer.start_point[0] + 1, outer.end_point[0] + 1),
+                    columns=_columns(outer),
+                    signature=value_type,
+                    docstring=doc,
+                    exported=is_exported(name),
+                    value=value_text,
+                    int_value=int_value,
+                ))
+
+        if grouped and len(symbols) > 1:
+            for symbol in symbols:
+                symbol.group = symbols[0].name
+            if symbols[0].docstring is None:
+                symbols[0].docstring = self._doc_comment(node, source_bytes)
+        return symbols
+
+    def _infer_type(self, node: "Node", source_bytes: bytes) -> Optional[str]:
+        """Infer the type of a value from its literal form, or None if it isn't obvious."""
+        literal_types = {
+            "int_literal": "int",
+            "float_literal": "float64",
+            "imaginary_literal": "complex128",
+            "rune_literal": "rune",
+            "interpreted_string_literal": "string",
+            "raw_string_literal": "string",
+            "true": "bool",
+            "false": "bool",
+            "iota": "int",
+        }
+        if node.type in literal_types:
+            return literal_types[node.type]
+        if node.type == "composite_literal":
+            return self._get_node_text(node.child_by_field_name("type"), source_bytes) or None
+        if node.type == "unary_expression":
+            operand = node.child_by_field_name("operand")
+            if operand is not None and operand.type == "composite_literal" and self._find_child(node, "&"):
+                inner = self._infer_type(operand, source_bytes)
+                return f"*{inner}" if inner else None
+        return None
+
+    def _const_int(self, node: "Node", source_bytes: bytes, iota: int, known: dict[str, int]) -> Optional[int]:
+        """Evaluate an integer constant expression, or None if it isn't one this can work out.
+
+        Handles integer and rune literals, iota, names in known,
+        parentheses, conversions such as Weekday(1), and Go's unary and
+        binary integer operators, with Go's truncated division.
+        """
+        text = self._get_node_text(node, source_bytes)
+        if node.type == "int_literal":
+            digits = text.replace("_", "")
+            if len(digits) > 1 and digits.startswith("0") and digits.isdigit():
+                return int(digits, 8)  # Legacy octal, e.g. 0755
+            return int(digits, 0)
+        if node.type == "rune_literal":
+            return _rune_value(text[1:-1])
+        if node.type == "iota":
+            return iota
+        if node.type == "identifier":
+            return known.get(text)
+        if node.type == "parenthesized_expression":
+            inner = node.named_children
+            return self._const_int(inner[0], source_bytes, iota, known) if len(inner) == 1 else None
+        if node.type == "call_expression":
+            arguments = node.child_by_field_name("arguments")
+            args = arguments.named_children if arguments is not None else []
+            function = node.child_by_field_name("function")
+            if len(args) != 1 or function is None or function.type not in _CONVERSION_NODES:
+                return None
+            if self._get_node_text(function, source_bytes) in _BUILTINS:
+                return None  # e.g. len("abc")
+            return self._const_int(args[0], source_bytes, iota, known)  # A conversion, e.g. Weekday(1)
+        if node.type == "unary_expression":
+            operand = node.child_by_field_name("operand")
+            value = self._const_int(operand, source_bytes, iota, known) if operand is not None else None
+            operator = self._get_node_text(node.child_by_field_name("operator"), source_bytes)
+            if value is None:
+                return None
+            return {"-": -value, "+": value, "^": ~value}.get(operator)
+        if node.type == "binary_expression":
+            left = self._const_int(node.child_by_field_name("left"), source_bytes, iota, known)
+            right = self._const_int(node.child_by_field_name("right"), source_bytes, iota, known)
+            if left is None or right is None:
+                return None
+            return _integer_operation(self._get_node_text(node.child_by_field_name("operator"), source_bytes), left, right)
+        return None
+
+    def _struct_fields(self, node: "Node", source_bytes: bytes) -> list[Field]:
+        """Parse the fields of a struct type, one Field per declared name."""
+        field_list = self._find_child(node, "field_declaration_list")
+        if field_list is None:
+            return []
+        fields = []
+        for field_decl in field_list.children:
+            if field_decl.type != "field_declaration":
+                continue
+            type_text = self._get_node_text(field_decl.child_by_field_name("type"), source_bytes)
+            tag = self._tag_value(field_decl.child_by_field_name("tag"), source_bytes)
+            doc = self._doc_comment(field_decl, source_bytes) or self._line_comment(field_decl, source_bytes)
+            notice = deprecation_notice(doc)
+            names = field_decl.children_by_field_name("name")
+            if not names:
+                # Embedded field, e.g. "*Base" or "io.Reader"
+                pointer = "*" if self._find_child(field_decl, "*") else ""
+                fields.append(Field(
+                    name=None, type=pointer + type_text, tag=tag, embedded=True,
+                    lines=(field_decl.start_point[0] + 1, field_decl.end_point[0] + 1),
+                    columns=_columns(field_decl),
+                    deprecated=notice is not None, deprecation=notice or None,
+                ))
+                continue
+            for name_node in names:
+                fields.append(Field(
+                    name=self._get_node_text(name_node, source_bytes), type=type_text, tag=tag,
+                    lines=(name_node.start_point[0] + 1, field_decl.end_point[0] + 1),
+                    columns=_columns(name_node, field_decl),
+                    deprecated=notice is not None, deprecation=notice or None,
+                ))
+        return fields
+
+    def _tag_value(self, node: Optional["Node"], source_bytes: bytes) -> Optional[str]:
+        """Get a struct tag without its quotes, e.g. json:"id" for `json:"id"`."""
+        if node is None:
+            return None
+        text = self._get_node_text(node, source_bytes)
+        if text.startswith("`"):
+            return text[1:-1] or None
+        try:
+            return json.loads(text) or None
+        except ValueError:
+            return text[1:-1] or None
+
+    def _interface_embeds(self, node: "Node", source_bytes: bytes) -> list[str]:
+        """Get the interfaces embedded in an interface, skipping type-set constraints."""
+        embeds = []
+        for elem in self._interface_elements(node):
+            if elem.type == "interface_type_name":
+                embeds.append(self._get_node_text(elem, source_bytes))
+            elif elem.type in ("type_elem", "constraint_elem"):
+                types = elem.named_children
+                if len(types) == 1 and types[0].type in ("type_identifier", "qualified_type", "generic_type"):
+                    embeds.append(self._get_node_text(types[0], source_bytes))
+        return embeds
+
+    def _parse_interface_methods(self, node: "Node", source_bytes: bytes) -> list[Symbol]:
+        """Parse the method elements of an interface type."""
+        methods = []
+        for elem in self._interface_elements(node):
+            if elem.type not in ("method_elem", "method_spec"):
+                continue
+            name = self._get_node_text(elem.child_by_field_name("name"), source_bytes)
+            methods.append(Symbol(
+                name=name,
+                type="method",
+                lines=(elem.start_point[0] + 1, elem.end_point[0] + 1),
+                columns=_columns(elem),
+                signature=self._signature(elem, source_bytes),
+                docstring=self._doc_comment(elem, source_bytes),
+                exported=is_exported(name),
+                params=self._params(elem.child_by_field_name("parameters"), source_bytes),
+                results=self._results(elem.child_by_field_name("result"), source_bytes),
+            ))
+        return methods
+
+    def _interface_elements(self, node: "Node") -> Iterator["Node"]:
+        """Yield interface elements, handling grammars with a method_spec_list wrapper."""
+        for child in node.children:
+            if child.type == "method_spec_list":
+                yield from child.children
+            else:
+                yield child
+
+    def _signature(self, node: "Node", source_bytes: bytes) -> Optional[str]:
+        """Build a signature like "(id int) (*User, error)" from parameters and result."""
+        params = node.child_by_field_name("parameters")
+        if params is None:
+            return None
+        signature = self._get_node_text(params, source_bytes)
+        result = node.child_by_field_name("result")
+        if result is not None:
+            signature += f" {self._get_node_text(result, source_bytes)}"
+        return signature
+
+    def _params(self, node: Optional["Node"], source_bytes: bytes) -> list[Param]:
+        """Parse a parameter_list, one Param per declared name.
+
+        "a, b int" gives two parameters of type int, and "...string" gives a
+        variadic parameter of type string. Unnamed parameters have no name.
+        """
+        if node is None:
+            return []
+        params = []
+        for decl in node.named_children:
+            if decl.type not in ("parameter_declaration", "variadic_parameter_declaration"):
+                continue
+            type_text = self._get_node_text(decl.child_by_field_name("type"), source_bytes)
+            variadic = decl.type == "variadic_parameter_declaration"
+            names = decl.children_by_field_name("name")
+            if not names:
+                params.append(Param(name=None, type=type_text, variadic=variadic))
+            for name_node in names:
+                params.append(Param(name=self._get_node_text(name_node, source_bytes), type=type_text, variadic=variadic))
+        return params
+
+    def _results(self, node: Optional["Node"], source_bytes: bytes) -> list[Param]:
+        """Parse a function result: a parenthesized list or a single bare type."""
+        if node is None:
+            return []
+        if node.type == "parameter_list":
+            return self._params(node, source_bytes)
+        return [Param(name=None, type=self._get_node_text(node, source_bytes))]
+
+    def _doc_comment(self, node: "Node", source_bytes: bytes) -> Optional[str]:
+        """Collect the comment block directly above a declaration.
+
+        Go doc comments are runs of line comments with no blank line between
+        them and the declaration, so every adjacent comment node is gathered.
+        Directive lines such as "//go:generate" are left out, as go doc does.
+        """
+        lines: list[str] = []
+        expected_row = node.start_point[0] - 1
+        prev = node.prev_sibling
+        while prev is not None and prev.type == "comment" and prev.end_point[0] == expected_row:
+            before = prev.prev_sibling
+            if before is not None and before.end_point[0] == prev.start_point[0]:
+                break  # Trailing comment of the previous line, not a doc comment
+            text = self._get_node_text(prev, source_bytes)
+            if not _DIRECTIVE_RE.match(text):
+                lines[:0] = self._comment_lines(text)
+            expected_row = prev.start_point[0] - 1
+            prev = before
+        doc = "\n".join(lines).strip()
+        return doc or None
+
+    def _line_comment(self, node: "Node", source_bytes: bytes) -> Optional[str]:
+        """Get a comment trailing a declaration on its last line, e.g. "Red // primary"."""
+        after = node.next_sibling
+        while after is not None and after.type in (";", "\n"):
+            after = after.next_sibling
+        if after is None or after.type != "comment" or after.start_point[0] != node.end_point[0]:
+            return None
+        doc = "\n".join(self._comment_lines(self._get_node_text(after, source_bytes))).strip()
+        return doc or None
+
+    def _comment_lines(self, comment: str) -> list[str]:
+        """Strip comment markers from a line or block comment."""
+        if comment.startswith("//"):
+            text = comment[2:]
+            return [text[1:] if text.startswith(" ") else text]
+        body = comment[2:-2] if comment.startswith("/*") else comment
+        return [line.strip().lstrip("*").strip() for line in body.splitlines()]
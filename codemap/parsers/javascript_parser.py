@@ -48,6 +48,10 @@ class JavaScriptParser(Parser):
         tree = self._parser.parse(source_bytes)
         return self._extract_symbols(tree.root_node, source_bytes)
 
+    def syntax_tree(self, source: str, filepath: str = ""):
+        """Parse JavaScript source into a tree-sitter tree."""
+        return self._parser.parse(source.encode("utf-8"))
+
     def _extract_symbols(self, node: "Node", source_bytes: bytes) -> list[Symbol]:
         """Extract symbols from tree-sitter AST.
 
@@ -3,7 +3,7 @@
 from __future__ import annotations
 
 import ast
-from typing import Union
+from typing import Optional, Union
 
 from .base import Parser, Symbol
 
@@ -30,6 +30,13 @@ class PythonParser(Parser):
         tree = ast.parse(source, filename=filepath or "<string>")
         return self._extract_symbols(tree.body)
 
+    def syntax_tree(self, source: str, filepath: str = "") -> Optional[ast.Module]:
+        """Parse Python source into an ast.Module, or None if it has syntax errors."""
+        try:
+            return ast.parse(source, filename=filepath or "<string>")
+        except SyntaxError:
+            return None
+
     def _extract_symbols(self, nodes: list[ast.stmt]) -> list[Symbol]:
         """Extract symbols from AST nodes.
 
@@ -79,6 +86,7 @@ class PythonParser(Parser):
             name=node.name,
             type="class",
             lines=(start_line, node.end_lineno or node.lineno),
+            columns=self._columns(node),
             docstring=ast.get_docstring(node),
             children=children,
         )
@@ -104,10 +112,19 @@ class PythonParser(Parser):
             name=node.name,
             type=symbol_type,
             lines=(start_line, node.end_lineno or node.lineno),
+            columns=self._columns(node),
             signature=self._get_signature(node),
             docstring=ast.get_docstring(node),
         )
 
+    def _columns(self, node: Union[ast.ClassDef, ast.FunctionDef, ast.AsyncFunctionDef]) -> tuple[int, int]:
+        """Get 1-based start and end columns, starting at the first decorator's "@" if any."""
+        start = node.col_offset + 1
+        if node.decorator_list:
+            first = min(node.decorator_list, key=lambda d: (d.lineno, d.col_offset))
+            start = first.col_offset  # The "@" sits just before the decorator expression
+        return (start, (node.end_col_offset or 0) + 1)
+
     def _get_signature(self, node: Union[ast.FunctionDef, ast.AsyncFunctionDef]) -> str:
         """Extract function signature.
 
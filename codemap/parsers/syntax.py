@@ -0,0 +1,100 @@
+"""Syntax trees of indexed files, for analyses the symbol model doesn't cover.
+
+An Indexer created with retain_ast=True keeps each file's tree next to its
+entry in the MapStore (see MapStore.syntax_tree). A SyntaxTree pairs the
+parser's own tree, a tree_sitter.Tree or a Python ast.Module, with the
+source it was parsed from, and turns nodes back into positions and text
+the way a go/token FileSet does for go/ast:
+
+    tree = store.syntax_tree("svc/user.go")
+    for node in tree.walk():
+        if node.type == "call_expression":
+            print(tree.position(node), tree.text(node))
+
+Trees hold every node of every file, typically many times the memory of
+the index itself, so they are only kept when asked for and never saved.
+"""
+
+from __future__ import annotations
+
+import ast
+from dataclasses import dataclass
+from typing import Any, Callable, Iterator, Optional
+
+from .base import Position
+
+
+@dataclass
+class SyntaxTree:
+    """A file's syntax tree together with the source it was parsed from."""
+
+    path: str  # Relative to the root, with "/" separators
+    language: str
+    source: bytes  # UTF-8 text that was parsed; tree-sitter byte offsets index into it
+    tree: Any  # tree_sitter.Tree, or ast.Module for Python
+
+    @property
+    def root(self) -> Any:
+        """Root node: the tree-sitter root node, or the ast.Module itself."""
+        return self.tree if isinstance(self.tree, ast.AST) else self.tree.root_node
+
+    def walk(self, node: Any = None) -> Iterator[Any]:
+        """Yield a node and all its descendants in source order, depth first.
+
+        Args:
+            node: Node to start from; defaults to the root.
+        """
+        stack = [self.root if node is None else node]
+        while stack:
+            current = stack.pop()
+            yield current
+            stack.extend(reversed(self._children(current)))
+
+    def inspect(self, visit: Callable[[Any], bool], node: Any = None) -> None:
+        """Call visit for a node and its descendants, like go/ast's Inspect.
+
+        The children of a node are skipped when visit returns False for it.
+
+        Args:
+            visit: Called with each node in source order, depth first.
+            node: Node to start from; defaults to the root.
+        """
+        stack = [self.root if node is None else node]
+        while stack:
+            current = stack.pop()
+            if visit(current):
+                stack.extend(reversed(self._children(current)))
+
+    def position(self, node: Any) -> Optional[Position]:
+        """Start of a node: 1-based line and byte column in this file.
+
+        Returns:
+            Position, or None for Python nodes without one (e.g. ast.Module).
+        """
+        if isinstance(node, ast.AST):
+            if not hasattr(node, "lineno"):
+                return None
+            return Position(self.path, node.lineno, node.col_offset + 1)
+        row, column = node.start_point
+        return Position(self.path, row + 1, column + 1)
+
+    def end_position(self, node: Any) -> Optional[Position]:
+        """End of a node: the column just after its last character, as for Position."""
+        if isinstance(node, ast.AST):
+            if getattr(node, "end_lineno", None) is None:
+                return None
+            return Position(self.path, node.end_lineno, node.end_col_offset + 1)
+        row, column = node.end_point
+        return Position(self.path, row + 1, column + 1)
+
+    def text(self, node: Any) -> Optional[str]:
+        """Source text of a node, or None for Python nodes without a position."""
+        if isinstance(node, ast.AST):
+            return ast.get_source_segment(self.source.decode("utf-8"), node)
+        return self.source[node.start_byte:node.end_byte].decode("utf-8", errors="replace")
+
+    @staticmethod
+    def _children(node: Any) -> list[Any]:
+        if isinstance(node, ast.AST):
+            return list(ast.iter_child_nodes(node))
+        return node.children
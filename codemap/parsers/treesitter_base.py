@@ -2,20 +2,26 @@
 
 from __future__ import annotations
 
+import re
 from abc import abstractmethod
 from dataclasses import dataclass, field
 from typing import Optional, Callable
 
-from .base import Parser, Symbol
+from .base import Note, Parser, Symbol
 
 # Tree-sitter imports - optional dependency
 try:
-    from tree_sitter import Language, Parser as TSParser, Node
+    from tree_sitter import Language, Parser as TSParser, Node, Tree
     TREE_SITTER_AVAILABLE = True
 except ImportError:
     TREE_SITTER_AVAILABLE = False
     TSParser = None
     Node = None
+    Tree = None
+
+
+# A work marker at the start of a comment line: "TODO(alice): text", "FIXME text", "XXX: text"
+NOTE_RE = re.compile(r"(TODO|FIXME|XXX|HACK)\b(?:\(([^)]*)\))?:?\s*(.*)")
 
 
 @dataclass
@@ -82,6 +88,10 @@ class TreeSitterParser(Parser):
         tree = self._parser.parse(source_bytes)
         return self._extract_symbols(tree.root_node, source_bytes)
 
+    def syntax_tree(self, source: str, filepath: str = "") -> "Tree":
+        """Parse source into a tree-sitter tree."""
+        return self._parser.parse(source.encode("utf-8"))
+
     def _extract_symbols(self, node: "Node", source_bytes: bytes) -> list[Symbol]:
         """Extract symbols from AST node."""
         symbols = []
@@ -137,6 +147,7 @@ class TreeSitterParser(Parser):
             signature=signature,
             docstring=docstring,
             children=children if children else None,
+            columns=(node.start_point[1] + 1, node.end_point[1] + 1),
         )
 
     def _extract_name(self, node: "Node", mapping: NodeMapping, source_bytes: bytes) -> str:
@@ -199,6 +210,68 @@ class TreeSitterParser(Parser):
 
         return comment.strip()[:150] if comment.strip() else None
 
+    def _extract_notes(self, root: "Node", source_bytes: bytes) -> list[Note]:
+        """Find TODO/FIXME/XXX/HACK markers in every comment of a tree.
+
+        A marker must start a line of the comment. The lines after it belong
+        to the note until a blank line or another marker, for line comments
+        as long as they directly follow at the same column. Comments are
+        nodes of their own, so markers inside string literals never match.
+        """
+        notes: list[Note] = []
+        current: Optional[Note] = None
+        previous: Optional[tuple[int, int]] = None  # (row, column) of the last line comment
+        for node in self._comment_nodes(root):
+            text = self._get_node_text(node, source_bytes)
+            row, column = node.start_point
+            if text.startswith("/*"):
+                current, previous = None, None
+                for i, line in enumerate(text[2:-2].split("\n")):
+                    body = line.lstrip().lstrip("*").lstrip() if i else line.lstrip()
+                    offset = len(line) - len(body) + (column + 2 if i == 0 else 0)
+                    current = self._note_line(notes, current, body, row + i + 1, offset + 1)
+                current = None
+                continue
+            if text.startswith("//"):
+                body = text[2:].lstrip()
+            elif text.startswith("#"):
+                body = text[1:].lstrip()
+            else:
+                body = text.strip()
+            if previous != (row - 1, column):
+                current = None
+            current = self._note_line(notes, current, body, row + 1, column + len(text) - len(body) + 1)
+            previous = (row, column)
+        return notes
+
+    def _note_line(
+        self, notes: list[Note], current: Optional[Note], body: str, line: int, column: int
+    ) -> Optional[Note]:
+        """Start a note at a marker line or extend the current one; returns the open note."""
+        body = body.rstrip()
+        match = NOTE_RE.match(body)
+        if match:
+            note = Note(kind=match.group(1), text=match.group(3), line=line, column=column,
+                        author=match.group(2) or None)
+            notes.append(note)
+            return note
+        if current is None or not body:
+            return None
+        current.text = f"{current.text} {body}" if current.text else body
+        return current
+
+    def _comment_nodes(self, root: "Node") -> list["Node"]:
+        """All comment nodes of a tree, in source order."""
+        comments = []
+        stack = [root]
+        while stack:
+            node = stack.pop()
+            if node.type in self.config.comment_types:
+                comments.append(node)
+                continue
+            stack.extend(reversed(node.children))
+        return comments
+
     def _extract_children(self, body_node: "Node", source_bytes: bytes) -> list[Symbol]:
         """Extract child symbols from a body node."""
         children = []
@@ -215,6 +288,7 @@ class TreeSitterParser(Parser):
                             signature=symbol.signature,
                             docstring=symbol.docstring,
                             children=symbol.children,
+                            columns=symbol.columns,
                         )
                     elif symbol.type == "async_function":
                         symbol = Symbol(
@@ -224,6 +298,7 @@ class TreeSitterParser(Parser):
                             signature=symbol.signature,
                             docstring=symbol.docstring,
                             children=symbol.children,
+                            columns=symbol.columns,
                         )
                     children.append(symbol)
         return children
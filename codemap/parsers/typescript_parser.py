@@ -53,6 +53,11 @@ class TypeScriptParser(Parser):
         tree = parser.parse(source_bytes)
         return self._extract_symbols(tree.root_node, source_bytes)
 
+    def syntax_tree(self, source: str, filepath: str = ""):
+        """Parse TypeScript source into a tree-sitter tree, TSX for .tsx files."""
+        parser = self._tsx_parser if filepath.endswith(".tsx") else self._ts_parser
+        return parser.parse(source.encode("utf-8"))
+
     def _extract_symbols(self, node: "Node", source_bytes: bytes) -> list[Symbol]:
         """Extract symbols from tree-sitter AST.
 
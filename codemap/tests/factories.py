@@ -0,0 +1,44 @@
+"""Builders for the symbols and stores tests put together by hand."""
+
+from pathlib import Path
+from typing import Iterable
+
+from codemap.analysis import collect_go_packages, link_implementations
+from codemap.core.map_store import MapStore
+from codemap.parsers.base import Symbol
+from codemap.parsers.go_parser import is_exported
+from codemap.utils.file_utils import get_language
+
+
+def make_symbol(name: str, type: str = "function", lines: tuple[int, int] = (1, 1), **kwargs) -> Symbol:
+    """Build a Symbol, one line long unless lines says otherwise."""
+    return Symbol(name=name, type=type, lines=lines, **kwargs)
+
+
+def go_symbol(name: str, type: str = "function", **kwargs) -> Symbol:
+    """Build a Go symbol, exported by the case of its name unless exported is given."""
+    kwargs.setdefault("exported", is_exported(name))
+    return make_symbol(name, type, **kwargs)
+
+
+def add_file(store: MapStore, rel_path: str, symbols: Iterable[Symbol] = (), lines: int = 10, **options) -> None:
+    """Store a file of symbols, its language taken from its extension.
+
+    Options (package, imports, ...) are passed on to MapStore.update_file.
+    """
+    store.update_file(rel_path, "h", get_language(Path(rel_path)), lines, list(symbols), **options)
+
+
+def make_store(root: Path, files: dict[str, list[Symbol]] | None = None, link: bool = False, **options) -> MapStore:
+    """Build an in-memory store holding files, a map of paths to their symbols.
+
+    Every file gets the same options, see add_file. With link, Go
+    interfaces are linked to the types implementing them, as the
+    indexer does.
+    """
+    store = MapStore(root)
+    for rel_path, symbols in (files or {}).items():
+        add_file(store, rel_path, symbols, **options)
+    if link:
+        link_implementations(collect_go_packages(store.get_all_files()))
+    return store
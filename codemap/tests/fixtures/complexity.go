@@ -0,0 +1,57 @@
+// Package retry has functions with known cyclomatic complexities.
+package retry
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrGiveUp = errors.New("giving up")
+
+// Policy decides how often to retry.
+type Policy struct {
+	Attempts int
+	Backoff  time.Duration
+}
+
+// Do runs op until it succeeds, the policy gives up, or stop is closed.
+//
+// Complexity 13: one, plus if (3, one of them in the function literal),
+// for (2), non-default select and switch cases (4), and && or || (3).
+func (p Policy) Do(op func() error, stop <-chan struct{}) error {
+	if p.Attempts <= 0 || op == nil {
+		return ErrGiveUp
+	}
+	wait := func(d time.Duration) bool {
+		if d == 0 {
+			return true
+		}
+		select {
+		case <-time.After(d):
+			return true
+		case <-stop:
+			return false
+		}
+	}
+	var err error
+	for attempt := 0; attempt < p.Attempts; attempt++ {
+		switch err = op(); {
+		case err == nil:
+			return nil
+		case errors.Is(err, ErrGiveUp) && attempt > 0:
+			return err
+		default:
+		}
+		for _, d := range []time.Duration{p.Backoff} {
+			if !wait(d) && p.Backoff > 0 {
+				return err
+			}
+		}
+	}
+	return err
+}
+
+// Attempts is a straight-line function.
+func Attempts(p Policy) int {
+	return p.Attempts
+}
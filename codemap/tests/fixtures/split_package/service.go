@@ -0,0 +1,18 @@
+// Package split declares types in one file and most of their methods in another.
+package split
+
+// DefaultService serves users from memory.
+type DefaultService struct {
+	users map[int]string
+}
+
+// GetUser retrieves a user by ID.
+func (s *DefaultService) GetUser(id int) (string, bool) {
+	name, ok := s.users[id]
+	return name, ok
+}
+
+// Cache is a generic key-value cache.
+type Cache[K comparable, V any] struct {
+	items map[K]V
+}
@@ -0,0 +1,24 @@
+package split
+
+// CreateUser adds a user and returns its ID.
+func (s *DefaultService) CreateUser(name string) int {
+	id := len(s.users) + 1
+	s.users[id] = name
+	return id
+}
+
+// String describes the service.
+func (s DefaultService) String() string {
+	return "DefaultService"
+}
+
+// Get looks up a key.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	v, ok := c.items[key]
+	return v, ok
+}
+
+// Len counts the cached items.
+func (c Cache[K, V]) Len() int {
+	return len(c.items)
+}
@@ -0,0 +1,116 @@
+"""Tests for token estimates and token budgets."""
+
+from pathlib import Path
+
+import pytest
+
+from codemap.core.map_store import MapStore
+from codemap.formatters.budget import DEFAULT_REDUCTIONS, estimate_tokens, fit_to_budget
+from codemap.formatters.json_formatter import format_json
+from codemap.formatters.markdown_formatter import format_markdown
+from codemap.parsers.base import Param
+
+from .factories import go_symbol, make_store
+
+
+def _store(tmp_path: Path) -> MapStore:
+    symbols = [
+        go_symbol(
+            f"Handler{i}", lines=(i, i + 1),
+            signature="(ctx context.Context, req *Request) (*Response, error)",
+            params=[Param("ctx", "context.Context"), Param("req", "*Request")],
+            results=[Param(None, "*Response"), Param(None, "error")],
+            docstring="Handler does a fairly long amount of documented work. " * 4,
+        )
+        for i in range(10)
+    ] + [go_symbol("helper", lines=(30, 31))]
+    return make_store(tmp_path, {"api/api.go": symbols}, lines=40, package="api")
+
+
+class TestEstimateTokens:
+    """Tests for estimate_tokens."""
+
+    def test_empty(self):
+        assert estimate_tokens("") == 0
+        assert estimate_tokens("", "cl100k") == 0
+
+    def test_default_is_four_chars_per_token(self):
+        assert estimate_tokens("a" * 40) == 10
+        assert estimate_tokens("a" * 41) == 11
+
+    def test_cl100k_counts_words_and_punctuation(self):
+        assert estimate_tokens("hello world", "cl100k") == 2
+        assert estimate_tokens("func Greet(name string) string {", "cl100k") == 7
+
+    def test_cl100k_close_to_default_on_code(self):
+        text = "def get_user(self, user_id: int) -> User:\n" * 20
+        default = estimate_tokens(text)
+        cl100k = estimate_tokens(text, "gpt-4")
+        assert 0.5 < cl100k / default < 2
+
+    def test_unknown_tokenizer(self):
+        with pytest.raises(ValueError):
+            estimate_tokens("text", "nope")
+
+
+class TestFitToBudget:
+    """Tests for fit_to_budget."""
+
+    def test_no_reductions_when_within_budget(self, tmp_path: Path):
+        store = _store(tmp_path)
+        full = format_markdown(store)
+
+        rendered, applied = fit_to_budget(store, format_markdown, estimate_tokens(full))
+
+        assert rendered == full
+        assert applied == []
+
+    def test_drops_docs_first(self, tmp_path: Path):
+        store = _store(tmp_path)
+        full = format_markdown(store)
+
+        rendered, applied = fit_to_budget(store, format_markdown, estimate_tokens(full) - 1)
+
+        assert applied == ["docs"]
+        assert "documented work" not in rendered
+        assert "helper" in rendered
+
+    def test_applies_reductions_in_order_until_it_fits(self, tmp_path: Path):
+        rendered, applied = fit_to_budget(_store(tmp_path), format_markdown, 1)
+
+        assert applied == DEFAULT_REDUCTIONS
+        assert "helper" not in rendered
+        assert "context.Context" not in rendered
+
+    def test_custom_order(self, tmp_path: Path):
+        store = _store(tmp_path)
+        full = format_markdown(store)
+
+        rendered, applied = fit_to_budget(store, format_markdown, estimate_tokens(full) - 1, reductions=["signatures"])
+
+        assert applied == ["signatures"]
+        assert "context.Context" not in rendered
+        assert "documented work" in rendered
+
+    def test_signatures_shrink_json(self, tmp_path: Path):
+        store = _store(tmp_path)
+        full = format_json(store)
+
+        rendered, applied = fit_to_budget(store, format_json, estimate_tokens(full) - 1, reductions=["signatures"])
+
+        assert applied == ["signatures"]
+        assert "context.Context" not in rendered and "*Response" not in rendered
+        assert estimate_tokens(rendered) < estimate_tokens(full) * 0.8
+
+    def test_store_is_unchanged(self, tmp_path: Path):
+        store = _store(tmp_path)
+
+        fit_to_budget(store, format_markdown, 1)
+
+        entry = store.get_file("api/api.go")
+        assert entry.symbols[0].docstring
+        assert len(entry.symbols) == 11
+
+    def test_unknown_reduction(self, tmp_path: Path):
+        with pytest.raises(ValueError):
+            fit_to_budget(_store(tmp_path), format_markdown, 1, reductions=["bodies"])
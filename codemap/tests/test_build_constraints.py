@@ -0,0 +1,103 @@
+"""Tests for Go build constraint evaluation."""
+
+from pathlib import Path
+
+import pytest
+
+from codemap.utils.build_constraints import BuildContext, evaluate
+from codemap.utils.config import Config
+
+LINUX = BuildContext(goos="linux", goarch="amd64")
+
+
+def _write(root: Path, name: str, header: str = "") -> Path:
+    path = root / name
+    path.write_text(header + "package p\n")
+    return path
+
+
+class TestEvaluate:
+    """Tests for evaluate."""
+
+    @pytest.mark.parametrize("expression,expected", [
+        ("linux", True),
+        ("!linux", False),
+        ("linux && amd64", True),
+        ("windows || darwin", False),
+        ("linux && (arm64 || amd64)", True),
+        ("!(linux && !cgo)", False),
+        ("unix", True),
+        ("go1.21 && !go1.0", False),
+        ("custom || windows", False),
+    ])
+    def test_expressions(self, expression, expected):
+        assert evaluate(expression, LINUX.match_tag) is expected
+
+    @pytest.mark.parametrize("expression", ["linux &&", "(linux", "linux amd64", "linux & amd64"])
+    def test_malformed(self, expression):
+        with pytest.raises(ValueError):
+            evaluate(expression, LINUX.match_tag)
+
+
+class TestBuildContext:
+    """Tests for BuildContext.skip_reason."""
+
+    @pytest.mark.parametrize("name,reason", [
+        ("file_windows.go", "GOOS/GOARCH suffix _windows"),
+        ("file_arm64.go", "GOOS/GOARCH suffix _arm64"),
+        ("file_linux_arm64_test.go", "GOOS/GOARCH suffix _linux_arm64"),
+        ("file_linux.go", None),
+        ("file_linux_amd64_test.go", None),
+        ("windows.go", None),
+        ("file_unix.go", None),
+    ])
+    def test_filename_suffix(self, tmp_path: Path, name, reason):
+        assert LINUX.skip_reason(_write(tmp_path, name)) == reason
+
+    def test_go_build_line(self, tmp_path: Path):
+        path = _write(tmp_path, "a.go", "// Copyright notice.\n\n//go:build windows && !cgo\n\n")
+
+        assert LINUX.skip_reason(path) == "build constraint: windows && !cgo"
+        assert BuildContext(goos="windows", goarch="amd64").skip_reason(path) is None
+
+    def test_constraint_must_be_followed_by_blank_line(self, tmp_path: Path):
+        path = _write(tmp_path, "a.go", "//go:build windows\n")
+
+        assert LINUX.skip_reason(path) is None
+
+    def test_legacy_plus_build_lines(self, tmp_path: Path):
+        path = _write(tmp_path, "a.go", "// +build linux,arm64 darwin\n// +build !purego\n\n")
+
+        assert LINUX.skip_reason(path) == "build constraint: (linux && arm64 || darwin) && !purego"
+        assert BuildContext(goos="darwin", goarch="arm64").skip_reason(path) is None
+
+    def test_go_build_takes_precedence_over_plus_build(self, tmp_path: Path):
+        path = _write(tmp_path, "a.go", "//go:build linux\n// +build windows\n\n")
+
+        assert LINUX.skip_reason(path) is None
+
+    def test_suffix_and_constraint_both_apply(self, tmp_path: Path):
+        path = _write(tmp_path, "net_linux.go", "//go:build arm64\n\n")
+
+        assert LINUX.skip_reason(path) == "build constraint: arm64"
+        assert BuildContext(goos="linux", goarch="arm64").skip_reason(path) is None
+        assert BuildContext(goos="darwin", goarch="arm64").skip_reason(path) == "GOOS/GOARCH suffix _linux"
+
+    def test_tags(self, tmp_path: Path):
+        path = _write(tmp_path, "a.go", "//go:build integration\n\n")
+
+        assert LINUX.skip_reason(path) == "build constraint: integration"
+        assert BuildContext(goos="linux", goarch="amd64", tags=["integration"]).skip_reason(path) is None
+
+    def test_implied_os_tags(self):
+        assert BuildContext(goos="android", goarch="arm64").match_tag("linux")
+        assert BuildContext(goos="ios", goarch="arm64").match_tag("darwin")
+        assert not LINUX.match_tag("android")
+
+    def test_from_config(self, monkeypatch):
+        monkeypatch.setenv("GOOS", "plan9")
+        monkeypatch.delenv("GOARCH", raising=False)
+
+        context = BuildContext.from_config(Config(goarch="386", build_tags=["purego"]))
+
+        assert (context.goos, context.goarch, context.tags) == ("plan9", "386", ["purego"])
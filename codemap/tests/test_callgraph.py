@@ -0,0 +1,100 @@
+"""Tests for the Go call graph."""
+
+from pathlib import Path
+
+import pytest
+
+# Calls are recorded by the Go parser
+pytest.importorskip("tree_sitter_go")
+
+from codemap.analysis import call_edges
+from codemap.core.indexer import Indexer
+from codemap.core.map_store import MapStore
+from codemap.parsers.go_parser import GoParser
+
+SERVICE = '''package app
+
+import "fmt"
+
+type Store interface {
+    Get(id int) (*User, error)
+}
+
+type Cache struct{ store Store }
+
+func (c *Cache) Flush() {}
+
+type Service struct {
+    *Cache
+    store Store
+}
+
+func NewService(s Store) *Service {
+    return &Service{store: s}
+}
+
+func (s *Service) Lookup(id int) (*User, error) {
+    if err := validate(id); err != nil {
+        fmt.Println(err)
+    }
+    s.Flush()
+    return s.store.Get(id)
+}
+'''
+
+MAIN = '''package app
+
+var defaultService *Service
+
+func Run(ids []int) {
+    svc := NewService(nil)
+    for _, id := range ids {
+        svc.Lookup(id)
+    }
+    defaultService.Cache.Flush()
+    NewService(nil).Lookup(0)
+}
+
+func validate(id int) error {
+    if id > 0 {
+        return validate(id - 1)
+    }
+    return check(id)
+}
+
+func check(id int) error {
+    return validate(id)
+}
+'''
+
+
+def _index(tmp_path: Path) -> MapStore:
+    (tmp_path / "service.go").write_text(SERVICE)
+    (tmp_path / "main.go").write_text(MAIN)
+    Indexer(root=tmp_path, languages=["go"]).index_all()
+    return MapStore.load(tmp_path)
+
+
+class TestCallGraph:
+    def test_parser_records_call_chains(self):
+        lookup = [s for s in GoParser().parse(SERVICE) if s.name == "Lookup"][0]
+
+        assert lookup.calls == ["validate", "fmt.Println", "Service.Flush", "Service.store.Get"]
+
+    def test_graph(self, tmp_path: Path):
+        graph = _index(tmp_path).call_graph()
+
+        assert graph == {
+            "app.Run": ["app.NewService", "app.Service.Lookup", "app.Cache.Flush"],
+            "app.validate": ["app.validate", "app.check"],
+            "app.check": ["app.validate"],
+            "app.Cache.Flush": [],
+            "app.NewService": [],
+            "app.Service.Lookup": ["app.validate", "app.Cache.Flush", "app.Store.Get"],
+        }
+
+    def test_interface_calls_are_marked(self, tmp_path: Path):
+        edges = call_edges(_index(tmp_path))
+
+        marked = [(e.caller, e.callee) for e in edges if e.interface]
+        assert marked == [("app.Service.Lookup", "app.Store.Get")]
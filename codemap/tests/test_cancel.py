@@ -0,0 +1,182 @@
+"""Tests for cancelling indexing with a CancelToken."""
+
+import io
+import os
+import threading
+import time
+from pathlib import Path
+
+import pytest
+
+from codemap.core.indexer import Indexer
+from codemap.core.map_store import MapStore
+from codemap.formatters.jsonl_formatter import stream_jsonl
+from codemap.utils.cancel import CancelToken, Cancelled, DeadlineExceeded, check
+from codemap.utils.config import Config
+
+
+def _slow_parse(files, root):
+    """Stands in for the worker's parse function and never answers in time."""
+    time.sleep(3)
+    return []
+
+
+def _tree(root: Path, directories: int = 10) -> None:
+    for i in range(directories):
+        (root / f"pkg{i:02}").mkdir()
+        (root / f"pkg{i:02}" / "mod.py").write_text(f"def f{i}():\n    pass\n")
+
+
+class _Canceller:
+    """Visitor that cancels a token on the first symbol it sees."""
+
+    def __init__(self, token: CancelToken):
+        self.token = token
+        self.seen: list = []
+
+    def visit_symbol(self, symbol, file):
+        self.seen.append(file)
+        self.token.cancel()
+
+
+class TestCancelToken:
+    """Tests for CancelToken."""
+
+    def test_cancel(self):
+        token = CancelToken()
+        assert not token.cancelled and token.err() is None and token.remaining() is None
+        token.check()
+
+        token.cancel()
+        token.cancel()
+
+        assert token.cancelled
+        assert type(token.err()) is Cancelled
+        with pytest.raises(Cancelled):
+            check(token)
+
+    def test_deadline(self):
+        token = CancelToken(timeout=0)
+
+        assert token.cancelled
+        assert token.remaining() == 0
+        with pytest.raises(DeadlineExceeded):
+            token.check()
+        assert CancelToken(timeout=60).remaining() > 59
+
+    def test_none_is_never_cancelled(self):
+        check(None)
+
+
+class TestCancelIndexing:
+    """Tests for cancelling Indexer and stream_jsonl."""
+
+    def test_cancel_mid_walk(self, tmp_path: Path, monkeypatch):
+        _tree(tmp_path)
+        token = CancelToken()
+        calls = []
+        scandir = os.scandir
+
+        def cancelling_scandir(path):
+            calls.append(path)
+            if len(calls) == 3:
+                token.cancel()
+            return scandir(path)
+
+        monkeypatch.setattr(os, "scandir", cancelling_scandir)
+        start = time.monotonic()
+        with pytest.raises(Cancelled):
+            Indexer(tmp_path, config=Config()).index_all(cancel=token)
+
+        assert time.monotonic() - start < 1
+        assert len(calls) == 3  # The directory being read when cancelled, and no more
+        with pytest.raises(FileNotFoundError):
+            MapStore.load(tmp_path)
+
+    def test_cancelled_reindex_keeps_previous_index(self, tmp_path: Path):
+        _tree(tmp_path, directories=2)
+        Indexer(tmp_path, config=Config(workers=1)).index_all()
+        before = sorted(p for p, _ in MapStore.load(tmp_path).get_all_files())
+        (tmp_path / "pkg00" / "mod.py").write_text("def renamed():\n    pass\n")
+        token = CancelToken()
+
+        indexer = Indexer(tmp_path, config=Config(workers=1), visitors=[_Canceller(token)])
+        with pytest.raises(Cancelled):
+            indexer.index_all(cancel=token)
+
+        store = MapStore.load(tmp_path)
+        assert sorted(p for p, _ in store.get_all_files()) == before == ["pkg00/mod.py", "pkg01/mod.py"]
+        assert [s.name for s in store.get_file("pkg00/mod.py").symbols] == ["f0"]
+        assert sorted(p.name for p in tmp_path.iterdir() if p.name.startswith(".")) == [".codemap"]
+
+    def test_reindex_replaces_previous_index(self, tmp_path: Path):
+        _tree(tmp_path, directories=2)
+        Indexer(tmp_path, config=Config(workers=1)).index_all()
+        (tmp_path / "pkg01" / "mod.py").unlink()
+
+        Indexer(tmp_path, config=Config(workers=1)).index_all()
+
+        assert [p for p, _ in MapStore.load(tmp_path).get_all_files()] == ["pkg00/mod.py"]
+        assert sorted(p.name for p in tmp_path.iterdir() if p.name.startswith(".")) == [".codemap"]
+
+    def test_deadline_mid_walk(self, tmp_path: Path, monkeypatch):
+        _tree(tmp_path, directories=40)
+        scandir = os.scandir
+
+        def slow_scandir(path):
+            time.sleep(0.05)
+            return scandir(path)
+
+        monkeypatch.setattr(os, "scandir", slow_scandir)
+        start = time.monotonic()
+        with pytest.raises(DeadlineExceeded):
+            Indexer(tmp_path, config=Config()).index_all(cancel=CancelToken(timeout=0.2))
+
+        assert time.monotonic() - start < 1
+
+    def test_cancel_between_files(self, tmp_path: Path):
+        _tree(tmp_path)
+        token = CancelToken()
+        canceller = _Canceller(token)
+
+        with pytest.raises(Cancelled):
+            Indexer(tmp_path, config=Config(workers=1), visitors=[canceller]).index_all(cancel=token)
+
+        assert canceller.seen == ["pkg00/mod.py"]
+
+    def test_cancel_parallel_parsing(self, tmp_path: Path, monkeypatch):
+        _tree(tmp_path)
+        monkeypatch.setattr("codemap.core.indexer.PARALLEL_MIN_FILES", 0)
+        monkeypatch.setattr("codemap.core.indexer.PARSE_CHUNK_SIZE", 1)
+        token = CancelToken()
+        canceller = _Canceller(token)
+
+        with pytest.raises(Cancelled):
+            Indexer(tmp_path, config=Config(workers=2), visitors=[canceller]).index_all(cancel=token)
+
+        assert canceller.seen == ["pkg00/mod.py"]
+
+    def test_cancel_while_waiting_for_a_worker(self, tmp_path: Path, monkeypatch):
+        _tree(tmp_path)
+        monkeypatch.setattr("codemap.core.indexer.PARALLEL_MIN_FILES", 0)
+        monkeypatch.setattr("codemap.core.indexer._parse_in_worker", _slow_parse)
+        token = CancelToken()
+        threading.Timer(0.2, token.cancel).start()
+
+        start = time.monotonic()
+        with pytest.raises(Cancelled):
+            Indexer(tmp_path, config=Config(workers=2)).index_all(cancel=token)
+
+        assert time.monotonic() - start < 1.5
+
+    def test_stream_writes_complete_lines(self, tmp_path: Path):
+        _tree(tmp_path)
+        token = CancelToken()
+        out = io.StringIO()
+
+        with pytest.raises(Cancelled):
+            stream_jsonl(out, tmp_path, Config(workers=1), visitors=[_Canceller(token)], cancel=token)
+
+        lines = out.getvalue().splitlines()
+        assert lines and all(line.startswith("{") and line.endswith("}") for line in lines)
+        assert all("pkg00/mod.py" in line for line in lines)
@@ -2,6 +2,7 @@
 
 import json
 import os
+import shutil
 import pytest
 from pathlib import Path
 from click.testing import CliRunner
@@ -83,6 +84,25 @@ def helper(x: int) -> str:
         assert (codemap_dir / "src" / ".codemap.json").exists()
         assert (codemap_dir / "src" / "components" / ".codemap.json").exists()
 
+    def test_init_show_skipped(self, runner, tmp_path, monkeypatch):
+        pytest.importorskip("tree_sitter_go")
+        (tmp_path / "main.go").write_text("package main\n\nfunc main() {}\n")
+        (tmp_path / "term_windows.go").write_text("package main\n\nfunc term() {}\n")
+        (tmp_path / "integration.go").write_text("//go:build integration\n\npackage main\n")
+        monkeypatch.chdir(tmp_path)
+
+        result = runner.invoke(cli, ["init", ".", "--goos", "linux", "--goarch", "amd64", "--show-skipped"])
+
+        assert result.exit_code == 0
+        assert "Skipped 2 Go files excluded by build constraints (linux/amd64)" in result.output
+        assert "  - term_windows.go: GOOS/GOARCH suffix _windows" in result.output
+        assert "  - integration.go: build constraint: integration" in result.output
+
+        result = runner.invoke(cli, ["init", ".", "--goos", "linux", "--tags", "integration"])
+
+        assert "Skipped 1 Go files" in result.output
+        assert "integration.go" not in result.output
+
     def test_find_command(self, runner, sample_project, monkeypatch):
         monkeypatch.chdir(sample_project)
         runner.invoke(cli, ["init", "."])
@@ -190,6 +210,20 @@ def new_function():
         assert "script.py" in file_names
         assert "script.js" not in file_names
 
+    def test_init_with_include_and_exclude(self, runner, tmp_path, monkeypatch):
+        (tmp_path / "src").mkdir()
+        (tmp_path / "src" / "app.py").write_text("def app(): pass")
+        (tmp_path / "src" / "app_gen.py").write_text("def gen(): pass")
+        (tmp_path / "setup.py").write_text("def setup(): pass")
+
+        monkeypatch.chdir(tmp_path)
+        result = runner.invoke(cli, ["init", ".", "-i", "src/**", "-e", "**/*_gen.py"])
+
+        assert result.exit_code == 0
+        from codemap.core.map_store import MapStore
+        store = MapStore.load(tmp_path)
+        assert [f[0] for f in store.get_all_files()] == ["src/app.py"]
+
     def test_lines_command_valid(self, runner, sample_project, monkeypatch):
         monkeypatch.chdir(sample_project)
         runner.invoke(cli, ["init", "."])
@@ -230,6 +264,18 @@ def new_function():
         assert "Total files" in result.output
         assert "Total symbols" in result.output
 
+    def test_stats_package(self, runner, sample_project, monkeypatch):
+        monkeypatch.chdir(sample_project)
+        runner.invoke(cli, ["init", "."])
+
+        result = runner.invoke(cli, ["stats", "--package", "."])
+        missing = runner.invoke(cli, ["stats", "--package", "nowhere"])
+
+        assert result.exit_code == 0
+        assert result.output.startswith("Package .: 5 symbols")
+        assert "Type" in result.output
+        assert "No package indexed in nowhere" in missing.output
+
     def test_stats_shows_directories(self, runner, tmp_path, monkeypatch):
         """Test that stats command shows indexed directories."""
         (tmp_path / "src").mkdir()
@@ -243,3 +289,275 @@ def new_function():
 
         assert result.exit_code == 0
         assert "Indexed directories" in result.output
+
+    def test_export_json(self, runner, sample_project, monkeypatch):
+        monkeypatch.chdir(sample_project)
+        runner.invoke(cli, ["init", "."])
+
+        result = runner.invoke(cli, ["export"])
+
+        assert result.exit_code == 0
+        data = json.loads(result.output)
+        assert {s["name"] for p in data["packages"] for s in p["symbols"]} == {"main", "Application", "helper"}
+
+    def test_export_collapse_over(self, runner, sample_project, monkeypatch):
+        monkeypatch.chdir(sample_project)
+        runner.invoke(cli, ["init", "."])
+
+        result = runner.invoke(cli, ["export", "--collapse-over", "0"])
+
+        assert result.exit_code == 0
+        data = json.loads(result.output)
+        assert [s for p in data["packages"] for s in p["symbols"]] == []
+        assert all(f["collapsed"] for p in data["packages"] for f in p["files"])
+
+    def test_export_sort(self, runner, sample_project, monkeypatch):
+        monkeypatch.chdir(sample_project)
+        runner.invoke(cli, ["init", "."])
+
+        source = json.loads(runner.invoke(cli, ["export"]).output)
+        alpha = json.loads(runner.invoke(cli, ["export", "--sort", "alpha"]).output)
+
+        def names(doc):
+            return [s["name"] for p in doc["packages"] for s in p["symbols"] if s["file"] == "main.py"]
+
+        assert names(source) == ["main", "Application"]
+        assert names(alpha) == ["Application", "main"]
+
+    def test_export_doc_options(self, runner, sample_project, monkeypatch):
+        monkeypatch.chdir(sample_project)
+        runner.invoke(cli, ["init", "."])
+
+        result = runner.invoke(cli, ["export", "--doc-strip-name", "--doc-max-chars", "14"])
+
+        assert result.exit_code == 0
+        docs = {
+            s["name"]: s["docstring"]
+            for p in json.loads(result.output)["packages"]
+            for s in p["symbols"]
+        }
+        assert docs["main"] == "Main entry..."
+        assert docs["helper"] == "Helper..."
+
+    def test_export_min_complexity(self, runner, tmp_path, monkeypatch):
+        pytest.importorskip("tree_sitter_go")
+        (tmp_path / "lib.go").write_text(
+            "package lib\n\n"
+            "func Simple() {}\n\n"
+            "func Branchy(x int) int {\n\tif x > 0 && x < 10 {\n\t\treturn 1\n\t}\n\treturn 0\n}\n\n"
+            "func Loop(xs []int) {\n\tfor range xs {\n\t}\n}\n"
+        )
+        monkeypatch.chdir(tmp_path)
+        runner.invoke(cli, ["init", "."])
+
+        result = runner.invoke(cli, ["export", "--min-complexity", "2", "--sort", "complexity"])
+
+        assert result.exit_code == 0
+        symbols = json.loads(result.output)["packages"][0]["symbols"]
+        assert [(s["name"], s["complexity"]) for s in symbols] == [("Branchy", 3), ("Loop", 2)]
+
+    def test_show_marks_stubs(self, runner, tmp_path, monkeypatch):
+        pytest.importorskip("tree_sitter_go")
+        (tmp_path / "lib.go").write_text(
+            "package lib\n\n"
+            "func Todo() error {\n\tpanic(\"not implemented\")\n}\n\n"
+            "func Done() int {\n\treturn 1\n}\n"
+        )
+        monkeypatch.chdir(tmp_path)
+        runner.invoke(cli, ["init", "."])
+
+        result = runner.invoke(cli, ["show", "lib.go"])
+
+        assert result.exit_code == 0
+        lines = result.output.splitlines()
+        assert "stub" in next(line for line in lines if "- Todo" in line)
+        assert "stub" not in next(line for line in lines if "- Done" in line)
+
+    def test_package_command(self, runner, tmp_path, monkeypatch):
+        pytest.importorskip("tree_sitter_go")
+        if shutil.which("go") is None:
+            pytest.skip("go not installed")
+        (tmp_path / "go.mod").write_text("module example.com/lib\n\ngo 1.21\n")
+        (tmp_path / "lib.go").write_text("package lib\n\n// Hello greets.\nfunc Hello() string { return \"hi\" }\n")
+        monkeypatch.chdir(tmp_path)
+
+        result = runner.invoke(cli, ["package", "example.com/lib"])
+        missing = runner.invoke(cli, ["package", "example.com/lib/missing"])
+
+        assert result.exit_code == 0
+        package = json.loads(result.output)["packages"][0]
+        assert package["import_path"] == "example.com/lib"
+        assert [s["name"] for s in package["symbols"]] == ["Hello"]
+        assert not (tmp_path / ".codemap").exists()
+        assert missing.exit_code == 1
+
+    def test_show_sort(self, runner, sample_project, monkeypatch):
+        monkeypatch.chdir(sample_project)
+        runner.invoke(cli, ["init", "."])
+
+        result = runner.invoke(cli, ["show", "main.py", "--sort", "alpha"])
+
+        assert result.exit_code == 0
+        assert result.output.index("- Application") < result.output.index("- main")
+
+    def test_export_markdown_to_file(self, runner, sample_project, monkeypatch):
+        monkeypatch.chdir(sample_project)
+        runner.invoke(cli, ["init", "."])
+
+        result = runner.invoke(cli, ["export", "-f", "markdown", "-o", "MAP.md"])
+
+        assert result.exit_code == 0
+        assert (sample_project / "MAP.md").read_text().startswith("# Code Map")
+
+    def test_export_estimate(self, runner, sample_project, monkeypatch):
+        monkeypatch.chdir(sample_project)
+        runner.invoke(cli, ["init", "."])
+
+        result = runner.invoke(cli, ["export", "--estimate", "--tokenizer", "cl100k"])
+
+        assert result.exit_code == 0
+        assert "tokens (json, cl100k tokenizer)" in result.output
+
+    def test_export_max_tokens_reports_reductions(self, runner, sample_project, monkeypatch):
+        monkeypatch.chdir(sample_project)
+        runner.invoke(cli, ["init", "."])
+
+        result = runner.invoke(cli, ["export", "-f", "markdown", "--max-tokens", "1"])
+
+        assert result.exit_code == 0
+        assert "by dropping: docs" in result.output
+        assert "Main entry point." not in result.output
+
+    def test_notes(self, runner, tmp_path, monkeypatch):
+        pytest.importorskip("tree_sitter_go")
+        (tmp_path / "main.go").write_text(
+            "package main\n\n// TODO(ann): rename\nfunc main() {\n\t// FIXME: check errors\n}\n"
+        )
+        monkeypatch.chdir(tmp_path)
+        runner.invoke(cli, ["init", ".", "--notes"])
+
+        result = runner.invoke(cli, ["notes"])
+        fixmes = runner.invoke(cli, ["notes", "-k", "fixme"])
+        by_ann = runner.invoke(cli, ["notes", "--author", "ann"])
+
+        assert result.exit_code == 0
+        assert result.output.splitlines() == ["main.go:3:4: TODO(ann): rename", "main.go:5:5: FIXME: check errors"]
+        assert fixmes.output.splitlines() == ["main.go:5:5: FIXME: check errors"]
+        assert by_ann.output.splitlines() == ["main.go:3:4: TODO(ann): rename"]
+
+    def test_methods(self, runner, tmp_path, monkeypatch):
+        pytest.importorskip("tree_sitter_go")
+        (tmp_path / "conn.go").write_text(
+            "package p\n\nimport \"bytes\"\n\ntype base struct{}\n\nfunc (b *base) Close() error { return nil }\n\n"
+            "type Conn struct {\n\tbase\n\t*bytes.Buffer\n}\n\nfunc (c Conn) Name() string { return \"\" }\n"
+        )
+        monkeypatch.chdir(tmp_path)
+        runner.invoke(cli, ["init", ".", "-l", "go"])
+
+        value = runner.invoke(cli, ["methods", "p.Conn"])
+        pointer = runner.invoke(cli, ["methods", "p.Conn", "--pointer"])
+        missing = runner.invoke(cli, ["methods", "p.Nope"])
+
+        assert value.exit_code == 0
+        assert value.output.splitlines() == [
+            "Name() string",
+            "Methods of *bytes.Buffer not included: not in the index",
+        ]
+        assert pointer.output.splitlines()[:2] == ["Close() error  (via base, declared on *base)", "Name() string"]
+        assert missing.exit_code == 1
+
+    def test_missing_methods(self, runner, tmp_path, monkeypatch):
+        pytest.importorskip("tree_sitter_go")
+        (tmp_path / "store.go").write_text(
+            "package p\n\ntype Store interface {\n\tGet(id int) (string, error)\n\tClose() error\n}\n\n"
+            "type Mem struct{}\n\nfunc (m *Mem) Get(id int) (string, error) { return \"\", nil }\n\n"
+            "func (m *Mem) Close() error { return nil }\n\n"
+            "type Disk struct{}\n\nfunc (d Disk) Get(id string) (string, error) { return \"\", nil }\n"
+        )
+        monkeypatch.chdir(tmp_path)
+        runner.invoke(cli, ["init", ".", "-l", "go"])
+
+        value = runner.invoke(cli, ["missing-methods", "p.Mem", "p.Store"])
+        pointer = runner.invoke(cli, ["missing-methods", "*p.Mem", "p.Store"])
+        disk = runner.invoke(cli, ["missing-methods", "p.Disk", "p.Store"])
+        wrong = runner.invoke(cli, ["missing-methods", "p.Store", "p.Mem"])
+
+        assert value.exit_code == 1
+        assert value.output.splitlines()[0] == "p.Mem doesn't implement p.Store; only *p.Mem does"
+        assert (pointer.exit_code, pointer.output) == (0, "*p.Mem implements p.Store\n")
+        assert disk.output.splitlines() == [
+            "p.Disk doesn't implement p.Store:",
+            "  Close: Disk has no method Close",
+            "  Get: Get(id string) (string, error) has the wrong parameter types; want Get(id int) (string, error)",
+        ]
+        assert wrong.exit_code == 1 and "Not a Go type and interface" in wrong.output
+
+    def test_export_dot(self, runner, tmp_path, monkeypatch):
+        pytest.importorskip("tree_sitter_go")
+        (tmp_path / "go.mod").write_text("module example.com/app\n")
+        (tmp_path / "main.go").write_text('package main\n\nimport "example.com/app/util"\n\nfunc main() { util.Run() }\n')
+        (tmp_path / "util").mkdir()
+        (tmp_path / "util" / "util.go").write_text('package util\n\nimport "fmt"\n\nfunc Run() { fmt.Println() }\n')
+        monkeypatch.chdir(tmp_path)
+        runner.invoke(cli, ["init", "."])
+
+        result = runner.invoke(cli, ["export", "-f", "dot", "--internal-only"])
+
+        assert result.exit_code == 0
+        assert '"example.com/app" -> "example.com/app/util";' in result.output
+        assert '"fmt"' not in result.output
+
+    def test_export_mermaid(self, runner, tmp_path, monkeypatch):
+        pytest.importorskip("tree_sitter_go")
+        (tmp_path / "shape.go").write_text(
+            "package shape\n\ntype Shape interface { Area() float64 }\n\n"
+            "type Square struct { side float64 }\n\nfunc (s Square) Area() float64 { return s.side * s.side }\n"
+        )
+        monkeypatch.chdir(tmp_path)
+        runner.invoke(cli, ["init", "."])
+
+        result = runner.invoke(cli, ["export", "-f", "mermaid", "--exported-only"])
+
+        assert result.exit_code == 0
+        assert result.output.startswith("classDiagram")
+        assert "shape_Shape <|.. shape_Square" in result.output
+        assert "side" not in result.output
+
+    def test_stream(self, runner, sample_project):
+        output = sample_project / "out.jsonl"
+
+        result = runner.invoke(cli, ["stream", str(sample_project), "-o", str(output)])
+
+        assert result.exit_code == 0
+        records = [json.loads(line) for line in output.read_text().splitlines()]
+        main = [r for r in records if "main.py" in (r.get("path"), r.get("file"))]
+        assert [(r["kind"], r.get("name", r.get("path"))) for r in main] == [
+            ("file", "main.py"), ("symbol", "main"), ("symbol", "Application"),
+        ]
+        assert not (sample_project / ".codemap").exists()
+
+    def test_diff_against_export(self, runner, sample_project, monkeypatch):
+        monkeypatch.chdir(sample_project)
+        runner.invoke(cli, ["init", "."])
+        runner.invoke(cli, ["export", "-o", "before.json"])
+        (sample_project / "utils.py").write_text('''
+def helper(x: int, y: int) -> str:
+    """Helper function."""
+    return str(x + y)
+''')
+        runner.invoke(cli, ["update", "--all"])
+
+        result = runner.invoke(cli, ["diff", "before.json", "--exit-code"])
+
+        assert result.exit_code == 1
+        assert "~ utils.helper signature: (x: int) -> str -> (x: int, y: int) -> str" in result.output
+
+    def test_diff_no_changes(self, runner, sample_project, monkeypatch):
+        monkeypatch.chdir(sample_project)
+        runner.invoke(cli, ["init", "."])
+        runner.invoke(cli, ["export", "-o", "before.json"])
+
+        result = runner.invoke(cli, ["diff", "before.json", ".", "--exit-code"])
+
+        assert result.exit_code == 0
+        assert "No changes" in result.output
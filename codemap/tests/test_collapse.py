@@ -0,0 +1,83 @@
+"""Tests for collapsing large files into symbol counts."""
+
+import json
+from pathlib import Path
+
+from codemap.analysis import collapse_large_files
+from codemap.core.map_store import MapStore
+from codemap.formatters import format_json, format_markdown
+
+from .factories import add_file, make_store, make_symbol
+
+
+def _store(tmp_path: Path) -> MapStore:
+    reader = make_symbol("Reader", "interface", children=[make_symbol("Read", "method"), make_symbol("Close", "method")])
+    store = make_store(tmp_path, {"big.go": [
+        make_symbol("Service", "struct"), reader, make_symbol("Get", "method"), make_symbol("Put", "method"),
+        make_symbol("New"),
+    ]}, lines=40, package="store")
+    add_file(store, "small.go", [make_symbol("helper")], lines=5, package="store")
+    add_file(store, "doc.go", lines=1, package="store")
+    return store
+
+
+class TestCollapseLargeFiles:
+    """Tests for collapse_large_files."""
+
+    def test_collapses_files_over_the_threshold(self, tmp_path: Path):
+        collapsed = collapse_large_files(_store(tmp_path), 3)
+
+        big = collapsed.get_file("big.go")
+        assert big.symbols == []
+        # Nested interface methods count too
+        assert big.collapsed == {"function": 1, "interface": 1, "method": 4, "struct": 1}
+
+    def test_threshold_applies_per_file(self, tmp_path: Path):
+        collapsed = collapse_large_files(_store(tmp_path), 3)
+
+        small = collapsed.get_file("small.go")
+        assert [s.name for s in small.symbols] == ["helper"]
+        assert small.collapsed is None
+
+    def test_file_at_the_threshold_is_listed(self, tmp_path: Path):
+        collapsed = collapse_large_files(_store(tmp_path), 7)
+
+        assert collapsed.get_file("big.go").collapsed is None
+        assert collapse_large_files(_store(tmp_path), 6).get_file("big.go").collapsed is not None
+
+    def test_empty_file_is_not_collapsed(self, tmp_path: Path):
+        doc = collapse_large_files(_store(tmp_path), 0).get_file("doc.go")
+
+        assert doc.symbols == []
+        assert doc.collapsed is None
+
+    def test_original_store_is_unchanged(self, tmp_path: Path):
+        store = _store(tmp_path)
+
+        collapse_large_files(store, 3)
+
+        assert len(store.get_file("big.go").symbols) == 5
+        assert store.get_file("big.go").collapsed is None
+
+    def test_collapsed_round_trips(self, tmp_path: Path):
+        collapse_large_files(_store(tmp_path), 3).save()
+
+        assert MapStore.load(tmp_path).get_file("big.go").collapsed["method"] == 4
+
+    def test_json_export(self, tmp_path: Path):
+        doc = json.loads(format_json(collapse_large_files(_store(tmp_path), 3)))
+
+        files = {f["path"]: f for f in doc["packages"][0]["files"]}
+        assert files["big.go"]["collapsed"] == {"function": 1, "interface": 1, "method": 4, "struct": 1}
+        assert files["doc.go"]["collapsed"] is None
+        assert [s["name"] for s in doc["packages"][0]["symbols"]] == ["helper"]
+
+    def test_markdown_summary_line(self, tmp_path: Path):
+        md = format_markdown(collapse_large_files(_store(tmp_path), 3))
+
+        assert (
+            "`big.go` (collapsed): 7 symbols (1 function, 1 interface, 4 method, 1 struct)"
+            " · run `codemap show big.go` for the listing"
+        ) in md
+        assert "### helper" in md
+        assert "Service" not in md
@@ -0,0 +1,95 @@
+"""Tests for the compact one-line-per-symbol formatter."""
+
+import shutil
+from pathlib import Path
+
+import pytest
+
+from codemap.core.map_store import MapStore
+from codemap.formatters import format_json, format_markdown
+from codemap.formatters.compact_formatter import compact_line, format_compact
+from codemap.parsers.base import Field, Symbol, TypeParam
+
+FIXTURES = Path(__file__).parent / "fixtures"
+
+
+def _indexed_fixture(tmp_path: Path) -> MapStore:
+    from codemap.core.indexer import Indexer
+
+    shutil.copy(FIXTURES / "sample_module.go", tmp_path / "sample_module.go")
+    Indexer(root=tmp_path, languages=["go"]).index_all()
+    return MapStore.load(tmp_path)
+
+
+class TestCompactFormatter:
+    """Tests for format_compact and compact_line."""
+
+    def test_go_fixture(self, tmp_path: Path):
+        pytest.importorskip("tree_sitter_go")
+
+        assert format_compact(_indexed_fixture(tmp_path)).splitlines() == [
+            "// sample_module.go (package sample)",
+            "type User struct { ID int; Name string }",
+            "type UserService interface { GetUser(id int) (*User, error); CreateUser(name string) (*User, error) }",
+            "type DefaultService struct { users map[int]*User }",
+            "func (*DefaultService) GetUser(id int) (*User, error)",
+            "func (*DefaultService) CreateUser(name string) (*User, error)",
+            "func Greet(name string) string",
+            "func Process(data []byte) ([]byte, error)",
+        ]
+
+    def test_much_shorter_than_other_formats(self, tmp_path: Path):
+        pytest.importorskip("tree_sitter_go")
+        store = _indexed_fixture(tmp_path)
+
+        compact = format_compact(store)
+
+        assert len(compact) * 10 < len(format_json(store))
+        assert len(compact) * 3 < len(format_markdown(store))
+
+    def test_go_types(self):
+        tagged = Symbol(name="User", type="struct", lines=(1, 1), fields=[
+            Field(name="ID", type="int", tag='json:"id"'),
+            Field(name=None, type="*Base", embedded=True),
+        ])
+        generic = Symbol(
+            name="Set", type="struct", lines=(1, 1),
+            type_params=[TypeParam(name="T", constraint="comparable")],
+            fields=[Field(name="m", type="map[T]struct{}")],
+        )
+        reader = Symbol(
+            name="Reader", type="interface", lines=(1, 1), embeds=["io.Closer"],
+            children=[Symbol(name="Read", type="method", lines=(1, 1), signature="(p []byte) (int, error)")],
+        )
+
+        assert compact_line(tagged) == 'type User struct { ID int `json:"id"`; *Base }'
+        assert compact_line(generic) == "type Set[T comparable] struct { m map[T]struct{} }"
+        assert compact_line(reader) == "type Reader interface { io.Closer; Read(p []byte) (int, error) }"
+        assert compact_line(Symbol(name="Empty", type="struct", lines=(1, 1))) == "type Empty struct{}"
+        assert compact_line(Symbol(name="ID", type="type", lines=(1, 1), signature="int")) == "type ID int"
+        assert compact_line(
+            Symbol(name="Name", type="type", lines=(1, 1), signature="string", is_alias=True)
+        ) == "type Name = string"
+
+    def test_go_values_join_lines(self):
+        table = Symbol(name="limits", type="var", lines=(1, 4), value='map[string]int{\n\t"a": 1,\n\t"b": 2,\n}')
+        const = Symbol(name="Max", type="const", lines=(1, 1), signature="int", value="10")
+
+        assert compact_line(table) == 'var limits = map[string]int{ "a": 1, "b": 2, }'
+        assert compact_line(const) == "const Max int = 10"
+
+    def test_other_languages_and_collapsed_files(self, tmp_path: Path):
+        store = MapStore(tmp_path)
+        app = Symbol(name="App", type="class", lines=(1, 9), docstring="The app.", children=[
+            Symbol(name="run", type="method", lines=(2, 4), signature="(self) -> None"),
+            Symbol(name="stop", type="async_method", lines=(5, 9), signature="(self)"),
+        ])
+        store.update_file("app.py", "h1", "python", 9, [app])
+        store.update_file("big.go", "h2", "go", 900, [], package="big")
+        store.get_file("big.go").collapsed = {"function": 12, "method": 40}
+
+        assert format_compact(store).splitlines() == [
+            "// app.py",
+            "class App { run(self) -> None; stop(self) }",
+            "// big.go (package big): collapsed, 52 symbols (12 function, 40 method)",
+        ]
@@ -0,0 +1,37 @@
+"""Tests for filtering an index by cyclomatic complexity."""
+
+from pathlib import Path
+
+from codemap.analysis import filter_complexity
+
+from .factories import make_store, make_symbol
+
+
+class TestFilterComplexity:
+    """Tests for filter_complexity."""
+
+    def test_keeps_functions_at_or_over_the_threshold(self, tmp_path: Path):
+        symbols = [
+            make_symbol("Simple", complexity=1), make_symbol("Config", "struct"),
+            make_symbol("Edge", complexity=5), make_symbol("Gnarly", complexity=12),
+        ]
+        store = make_store(tmp_path, {"lib.go": symbols})
+
+        filtered = filter_complexity(store, 5).get_file("lib.go")
+
+        assert [s.name for s in filtered.symbols] == ["Edge", "Gnarly"]
+
+    def test_complex_children_are_kept(self, tmp_path: Path):
+        children = [make_symbol("run", "method", complexity=8), make_symbol("stop", "method", complexity=1)]
+        store = make_store(tmp_path, {"app.py": [make_symbol("App", "class", children=children)]})
+
+        filtered = filter_complexity(store, 2).get_file("app.py")
+
+        assert [s.name for s in filtered.symbols] == ["run"]
+
+    def test_original_store_is_unchanged(self, tmp_path: Path):
+        store = make_store(tmp_path, {"lib.go": [make_symbol("Simple", complexity=1)]})
+
+        filter_complexity(store, 10)
+
+        assert [s.name for s in store.get_file("lib.go").symbols] == ["Simple"]
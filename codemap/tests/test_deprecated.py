@@ -0,0 +1,38 @@
+"""Tests for dropping deprecated symbols from an index."""
+
+from pathlib import Path
+
+from codemap.analysis import filter_deprecated
+from codemap.parsers.base import Field
+
+from .factories import make_store, make_symbol
+
+
+class TestFilterDeprecated:
+    """Tests for filter_deprecated."""
+
+    def test_drops_deprecated_symbols_members_and_fields(self, tmp_path: Path):
+        config = make_symbol(
+            "Config", "struct", lines=(1, 4),
+            fields=[Field(name="Wait", type="int", deprecated=True), Field(name="Timeout", type="int")],
+        )
+        reader = make_symbol(
+            "Reader", "interface", lines=(5, 8),
+            children=[make_symbol("ReadOld", "method", deprecated=True), make_symbol("Read", "method")],
+        )
+        store = make_store(tmp_path, {
+            "lib.go": [make_symbol("Old", deprecated=True), config, reader, make_symbol("New")],
+        })
+
+        filtered = filter_deprecated(store).get_file("lib.go")
+
+        assert [s.name for s in filtered.symbols] == ["Config", "Reader", "New"]
+        assert [f.name for f in filtered.symbols[0].fields] == ["Timeout"]
+        assert [c.name for c in filtered.symbols[1].children] == ["Read"]
+
+    def test_original_store_is_unchanged(self, tmp_path: Path):
+        store = make_store(tmp_path, {"lib.go": [make_symbol("Old", deprecated=True)]})
+
+        filter_deprecated(store)
+
+        assert [s.name for s in store.get_file("lib.go").symbols] == ["Old"]
@@ -0,0 +1,163 @@
+"""Tests for diffing code maps."""
+
+from pathlib import Path
+
+from codemap.analysis import diff_documents, symbol_records
+from codemap.core.map_store import MapStore
+from codemap.formatters import build_document, format_diff
+from codemap.parsers.base import Field, Param
+
+from .factories import make_store, make_symbol
+
+
+def _document(tmp_path: Path, symbols, package="sample") -> dict:
+    return build_document(make_store(tmp_path, {"sample/sample.go": symbols}, package=package))
+
+
+def _service(**overrides):
+    symbols = {
+        "User": make_symbol("User", "struct", fields=[Field("ID", "int", tag='json:"id"'), Field("Name", "string")]),
+        "DefaultService": make_symbol("DefaultService", "struct"),
+        "GetUser": make_symbol("GetUser", "method", signature="(id int) (*User, error)", receiver="*DefaultService"),
+        "Greet": make_symbol("Greet", "function", signature="(name string) string", docstring="Greet says hello."),
+    }
+    symbols.update(overrides)
+    return [s for s in symbols.values() if s is not None]
+
+
+class TestSymbolRecords:
+    """Tests for symbol paths."""
+
+    def test_go_paths_are_package_qualified(self, tmp_path: Path):
+        records = symbol_records(_document(tmp_path, _service()))
+
+        assert sorted(records) == [
+            "sample.DefaultService", "sample.DefaultService.GetUser", "sample.Greet", "sample.User",
+        ]
+        assert records["sample.DefaultService"].members == ["GetUser"]
+        assert records["sample.User"].fields == ['ID int `json:"id"`', "Name string"]
+
+    def test_python_paths_use_module_and_children(self, tmp_path: Path):
+        store = make_store(tmp_path, {"pkg/app.py": [
+            make_symbol("App", "class", children=[make_symbol("run", "method")]),
+        ]})
+
+        records = symbol_records(build_document(store))
+
+        assert sorted(records) == ["pkg.app.App", "pkg.app.App.run"]
+        assert records["pkg.app.App"].members == ["run"]
+
+    def test_duplicate_names_are_numbered(self, tmp_path: Path):
+        records = symbol_records(_document(tmp_path, [make_symbol("init", "function"), make_symbol("init", "function")]))
+
+        assert sorted(records) == ["sample.init", "sample.init#2"]
+
+
+class TestDiffDocuments:
+    """Tests for diff_documents."""
+
+    def test_no_changes(self, tmp_path: Path):
+        diff = diff_documents(_document(tmp_path, _service()), _document(tmp_path, _service()))
+
+        assert diff.is_empty
+        assert format_diff(diff) == ""
+
+    def test_signature_change(self, tmp_path: Path):
+        old = _document(tmp_path, _service())
+        new = _document(tmp_path, _service(
+            GetUser=make_symbol("GetUser", "method", signature="(id string) (*User, error)", receiver="*DefaultService"),
+        ))
+
+        diff = diff_documents(old, new)
+
+        assert [c.path for c in diff.modified] == ["sample.DefaultService.GetUser"]
+        assert diff.modified[0].changes == ["signature"]
+        assert not diff.added and not diff.removed
+
+    def test_change_past_a_cut_signature(self, tmp_path: Path):
+        def document(root: Path, timeout: str) -> dict:
+            params = [Param(n, "string") for n in ("tenant", "region", "bucket", "prefix", "owner", "token")] + [
+                Param("timeout", timeout),
+            ]
+            signature = "(" + ", ".join(str(p) for p in params) + ") (*Result, error)"
+            fetch = make_symbol(
+                "Fetch", signature=signature, params=params, results=[Param(None, "*Result"), Param(None, "error")],
+            )
+            make_store(root, {"sample/sample.go": [fetch]}, package="sample").save()
+            return build_document(MapStore.load(root))
+
+        (tmp_path / "old").mkdir()
+        (tmp_path / "new").mkdir()
+        old, new = document(tmp_path / "old", "time.Duration"), document(tmp_path / "new", "int")
+        diff = diff_documents(old, new)
+
+        assert old["packages"][0]["symbols"][0]["signature"] == new["packages"][0]["symbols"][0]["signature"]
+        assert [(c.path, c.changes) for c in diff.modified] == [("sample.Fetch", ["signature"])]
+        assert format_diff(diff) == (
+            "~ sample.Fetch signature: "
+            "(tenant string, region string, bucket string, prefix string, owner string, token string, timeout time.Duration) (*Result, error)"
+            " -> (tenant string, region string, bucket string, prefix string, owner string, token string, timeout int) (*Result, error)"
+        )
+
+    def test_rename_is_remove_and_add(self, tmp_path: Path):
+        old = _document(tmp_path, _service())
+        new = _document(tmp_path, _service(
+            GetUser=None,
+            FetchUser=make_symbol("FetchUser", "method", signature="(id int) (*User, error)", receiver="*DefaultService"),
+        ))
+
+        diff = diff_documents(old, new)
+
+        assert [r.path for r in diff.added] == ["sample.DefaultService.FetchUser"]
+        assert [r.path for r in diff.removed] == ["sample.DefaultService.GetUser"]
+        service = diff.modified[0]
+        assert service.path == "sample.DefaultService"
+        assert service.changes == ["members"]
+        assert service.added_members == ["FetchUser"]
+        assert service.removed_members == ["GetUser"]
+
+    def test_field_changes(self, tmp_path: Path):
+        old = _document(tmp_path, _service())
+        new = _document(tmp_path, _service(
+            User=make_symbol("User", "struct", fields=[Field("ID", "int", tag='json:"user_id"'), Field("Email", "string")]),
+        ))
+
+        change = diff_documents(old, new).modified[0]
+
+        assert change.changes == ["fields"]
+        assert change.added_fields == ['ID int `json:"user_id"`', "Email string"]
+        assert change.removed_fields == ['ID int `json:"id"`', "Name string"]
+
+    def test_doc_changes_are_separate(self, tmp_path: Path):
+        old = _document(tmp_path, _service())
+        new = _document(tmp_path, _service(
+            Greet=make_symbol("Greet", "function", signature="(name string) string", docstring="Greet greets someone."),
+        ))
+
+        diff = diff_documents(old, new)
+
+        assert diff.modified == []
+        assert [c.path for c in diff.doc_changed] == ["sample.Greet"]
+        assert not diff.has_api_changes
+        assert format_diff(diff) == "~ sample.Greet doc"
+        assert format_diff(diff, include_docs=False) == ""
+
+
+class TestFormatDiff:
+    """Tests for format_diff."""
+
+    def test_lines(self, tmp_path: Path):
+        old = _document(tmp_path, _service())
+        new = _document(tmp_path, _service(
+            Greet=None,
+            GetUser=make_symbol("GetUser", "method", signature="(id string) (*User, error)", receiver="*DefaultService"),
+            NewService=make_symbol("NewService", "function", signature="() *DefaultService"),
+        ))
+
+        output = format_diff(diff_documents(old, new))
+
+        assert output.splitlines() == [
+            "~ sample.DefaultService.GetUser signature: (id int) (*User, error) -> (id string) (*User, error)",
+            "- sample.Greet (function) (name string) string",
+            "+ sample.NewService (function) () *DefaultService",
+        ]
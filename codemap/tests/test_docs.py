@@ -0,0 +1,76 @@
+"""Tests for trimming doc comments."""
+
+from codemap.analysis import DocOptions, first_sentence, strip_symbol_name, trim_doc, trim_docs
+from codemap.core.map_store import MapStore
+from codemap.parsers.base import Symbol
+
+
+class TestFirstSentence:
+    """Tests for first_sentence."""
+
+    def test_ends_at_first_period_before_whitespace(self):
+        assert first_sentence("Get returns the value. It never blocks.") == "Get returns the value."
+        assert first_sentence("Get returns the value.\nIt never blocks.") == "Get returns the value."
+
+    def test_abbreviations_and_initials_do_not_end_it(self):
+        doc = "Parse reads formats, e.g. JSON or YAML, etc. and more. Then it stops."
+        assert first_sentence(doc) == "Parse reads formats, e.g. JSON or YAML, etc. and more."
+        assert first_sentence("Cite follows J. Smith's rules. Done.") == "Cite follows J. Smith's rules."
+
+    def test_periods_inside_words_do_not_end_it(self):
+        assert first_sentence("Open wraps os.Open for v1.2 files. More.") == "Open wraps os.Open for v1.2 files."
+
+    def test_stops_at_paragraph_and_joins_lines(self):
+        assert first_sentence("Run starts the\nserver without a period\n\nSecond paragraph.") == (
+            "Run starts the server without a period"
+        )
+
+
+class TestStripSymbolName:
+    """Tests for strip_symbol_name."""
+
+    def test_drops_leading_name(self):
+        assert strip_symbol_name("GetUser retrieves a user by ID.", "GetUser") == "retrieves a user by ID."
+
+    def test_keeps_other_docs(self):
+        assert strip_symbol_name("GetUsers lists users.", "GetUser") == "GetUsers lists users."
+        assert strip_symbol_name("Retrieves a user.", "GetUser") == "Retrieves a user."
+        assert strip_symbol_name("GetUser", "GetUser") == "GetUser"
+
+
+class TestTrimDoc:
+    """Tests for trim_doc and trim_docs."""
+
+    DOC = "GetUser retrieves a user by ID. It returns ErrNotFound\nwhen there is no such user.\nSafe for concurrent use."
+
+    def test_defaults_leave_doc_unchanged(self):
+        assert not DocOptions().active
+        assert trim_doc(self.DOC, "GetUser", DocOptions()) == self.DOC
+
+    def test_options_combine(self):
+        options = DocOptions(first_sentence=True, strip_name=True)
+        assert trim_doc(self.DOC, "GetUser", options) == "retrieves a user by ID."
+
+    def test_max_lines_and_chars(self):
+        assert trim_doc(self.DOC, "GetUser", DocOptions(max_lines=1)) == "GetUser retrieves a user by ID. It returns ErrNotFound"
+        assert trim_doc(self.DOC, "GetUser", DocOptions(max_chars=24)) == "GetUser retrieves a..."
+        assert trim_doc("Short.", "x", DocOptions(max_chars=24)) == "Short."
+
+    def test_nothing_left_is_none(self):
+        assert trim_doc(None, "GetUser", DocOptions(first_sentence=True)) is None
+        assert trim_doc("  ", "GetUser", DocOptions(first_sentence=True)) is None
+
+    def test_trim_docs_copies_store(self, tmp_path):
+        store = MapStore(tmp_path)
+        child = Symbol(name="Close", type="method", lines=(4, 5), docstring="Close closes it. Idempotent.")
+        symbol = Symbol(
+            name="Conn", type="class", lines=(1, 5), docstring="Conn is a connection. More.", children=[child]
+        )
+        store.update_file("conn.py", hash="h", language="python", lines=5, symbols=[symbol])
+
+        trimmed = trim_docs(store, DocOptions(first_sentence=True, strip_name=True))
+
+        conn = trimmed.get_file("conn.py").symbols[0]
+        assert conn.docstring == "is a connection."
+        assert conn.children[0].docstring == "closes it."
+        assert store.get_file("conn.py").symbols[0].docstring == "Conn is a connection. More."
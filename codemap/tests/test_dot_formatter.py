@@ -0,0 +1,156 @@
+"""Tests for the package import graph and its DOT export."""
+
+from pathlib import Path
+
+from codemap.analysis import build_import_graph, collect_go_packages
+from codemap.core.map_store import MapStore
+from codemap.formatters.dot_formatter import format_dot
+from codemap.parsers.base import Import
+
+from .factories import add_file, make_store
+
+
+def _store(tmp_path: Path, imports: dict[str, list[str]]) -> MapStore:
+    """Index one Go file per package directory with the given imports."""
+    store = make_store(tmp_path)
+    for directory, paths in imports.items():
+        name = directory.rsplit("/", 1)[-1] if directory != "." else "main"
+        rel_path = f"{directory}/{name}.go" if directory != "." else "main.go"
+        add_file(store, rel_path, lines=1, package=name, imports=[Import(p) for p in paths])
+    return store
+
+
+class TestImportGraph:
+    """Tests for build_import_graph."""
+
+    def test_module_paths_and_externals(self, tmp_path: Path):
+        store = _store(tmp_path, {
+            ".": ["example.com/app/api", "fmt"],
+            "api": ["example.com/app/store", "net/http"],
+            "store": ["database/sql"],
+        })
+
+        graph = build_import_graph(collect_go_packages(store.get_all_files()), "example.com/app")
+
+        assert graph.packages == ["example.com/app", "example.com/app/api", "example.com/app/store"]
+        assert graph.external == ["database/sql", "fmt", "net/http"]
+        assert ("example.com/app/api", "example.com/app/store") in graph.edges
+        assert graph.cycle_edges == set()
+
+    def test_cycles(self, tmp_path: Path):
+        store = _store(tmp_path, {
+            "a": ["example.com/app/b"],
+            "b": ["example.com/app/c"],
+            "c": ["example.com/app/a", "example.com/app/d"],
+            "d": [],
+        })
+
+        graph = build_import_graph(collect_go_packages(store.get_all_files()), "example.com/app")
+
+        assert graph.cycle_edges == {
+            ("example.com/app/a", "example.com/app/b"),
+            ("example.com/app/b", "example.com/app/c"),
+            ("example.com/app/c", "example.com/app/a"),
+        }
+
+    def test_without_module_matches_directory_suffix(self, tmp_path: Path):
+        store = _store(tmp_path, {"cmd/tool": ["github.com/x/proj/internal/util"], "internal/util": []})
+
+        graph = build_import_graph(collect_go_packages(store.get_all_files()))
+
+        assert graph.edges == [("cmd/tool", "internal/util")]
+        assert graph.external == []
+
+    def test_nested_module_import_paths(self, tmp_path: Path):
+        (tmp_path / "go.mod").write_text("module example.com/app\n")
+        (tmp_path / "tools").mkdir()
+        (tmp_path / "tools" / "go.mod").write_text("module example.com/tools\n")
+        store = _store(tmp_path, {"api": ["example.com/tools/gen"], "tools/gen": ["example.com/app/api"]})
+
+        graph = build_import_graph(collect_go_packages(store.get_all_files(), tmp_path))
+
+        assert graph.packages == ["example.com/app/api", "example.com/tools/gen"]
+        assert graph.edges == [
+            ("example.com/app/api", "example.com/tools/gen"),
+            ("example.com/tools/gen", "example.com/app/api"),
+        ]
+        assert graph.external == []
+
+    def test_opaque_prefixes_become_boundary_nodes(self, tmp_path: Path):
+        store = _store(tmp_path, {
+            ".": ["example.com/app/api", "example.com/app/internal/vendorized/a", "github.com/aws/sdk/s3"],
+            "api": ["example.com/app/internal/vendorized/b", "github.com/aws/sdk/ec2", "fmt"],
+            "internal/vendorized/a": ["os"],
+            "internal/vendorized/b": [],
+        })
+
+        graph = build_import_graph(
+            collect_go_packages(store.get_all_files()), "example.com/app", ["internal/vendorized/", "github.com/aws"]
+        )
+
+        assert graph.packages == ["example.com/app", "example.com/app/api"]
+        assert graph.external == ["fmt"]
+        assert graph.boundaries == ["github.com/aws/...", "internal/vendorized/..."]
+        assert graph.edges == [
+            ("example.com/app", "example.com/app/api"),
+            ("example.com/app", "github.com/aws/..."),
+            ("example.com/app", "internal/vendorized/..."),
+            ("example.com/app/api", "fmt"),
+            ("example.com/app/api", "github.com/aws/..."),
+            ("example.com/app/api", "internal/vendorized/..."),
+        ]
+
+
+class TestFormatDot:
+    """Tests for format_dot."""
+
+    def test_output(self, tmp_path: Path):
+        (tmp_path / "go.mod").write_text("module example.com/app\n\ngo 1.22\n")
+        store = _store(tmp_path, {
+            "a": ["example.com/app/b", "fmt"],
+            "b": ["example.com/app/a"],
+        })
+
+        assert format_dot(store) == "\n".join([
+            "digraph packages {",
+            "  rankdir=LR;",
+            '  node [shape=box, fontname="Helvetica"];',
+            '  "example.com/app/a";',
+            '  "example.com/app/b";',
+            '  "fmt" [style=dashed, color=gray50, fontcolor=gray50];',
+            '  "example.com/app/a" -> "example.com/app/b" [color=red, penwidth=2];',
+            '  "example.com/app/a" -> "fmt";',
+            '  "example.com/app/b" -> "example.com/app/a" [color=red, penwidth=2];',
+            "}",
+        ])
+
+    def test_internal_only_and_collapsed_externals(self, tmp_path: Path):
+        (tmp_path / "go.mod").write_text("module example.com/app\n")
+        store = _store(tmp_path, {"a": ["example.com/app/b", "fmt", "os"], "b": []})
+
+        internal = format_dot(store, internal_only=True)
+        collapsed = format_dot(store, collapse_external=True)
+
+        assert '"fmt"' not in internal
+        assert '"example.com/app/a" -> "example.com/app/b";' in internal
+        assert '"example.com/app/a" -> "external";' in collapsed
+        assert collapsed.count('-> "external"') == 1
+        assert '"fmt"' not in collapsed
+
+    def test_deterministic(self, tmp_path: Path):
+        first = _store(tmp_path / "one", {"b": ["os", "x/a", "fmt"], "a": ["os"]})
+        second = _store(tmp_path / "two", {"a": ["os"], "b": ["fmt", "x/a", "os"]})
+
+        assert format_dot(first) == format_dot(second)
+
+    def test_opaque_boundaries(self, tmp_path: Path):
+        (tmp_path / "go.mod").write_text("module example.com/app\n")
+        store = _store(tmp_path, {"a": ["example.com/app/third_party/x", "fmt"], "third_party/x": []})
+        store.set_metadata(root=str(tmp_path), config={"opaque": ["third_party"]})
+
+        output = format_dot(store, internal_only=True)
+
+        assert '  "third_party/..." [shape=box3d, color=gray40, fontcolor=gray40];' in output
+        assert '  "example.com/app/a" -> "third_party/...";' in output
+        assert '"example.com/app/third_party/x"' not in output
+        assert '"third_party/..."' not in format_dot(store, opaque=[])
@@ -0,0 +1,138 @@
+"""Tests for grouping Go constants into enum types."""
+
+import pytest
+
+pytest.importorskip("tree_sitter_go")
+
+from codemap.analysis.go_packages import collect_go_packages, link_enums
+from codemap.core.map_store import FileEntry
+from codemap.parsers.base import EnumValue
+from codemap.parsers.go_parser import GoParser
+
+TYPES = '''package status
+
+// Status is the state of a job.
+type Status int
+
+type Color string
+
+type Alias = int
+
+type Job struct{}
+
+//go:generate stringer -type=Status
+func (s Status) String() string { return "" }
+'''
+
+VALUES = '''package status
+
+const (
+	Pending Status = iota + 1
+	Running
+	Done
+	Other = 10
+)
+
+const (
+	Red   Color = "red"
+	Green Color = "green"
+)
+
+const Lonely Status = 99
+
+const (
+	X Alias = 1
+	Y Alias = 2
+)
+'''
+
+
+def _packages(*files):
+    parser = GoParser()
+    entries = [
+        (path, FileEntry(hash="h", indexed_at="", language="go", lines=1, symbols=parser.parse(source), package="status"))
+        for path, source in files
+    ]
+    packages = collect_go_packages(entries)
+    link_enums(packages)
+    return packages
+
+
+class TestLinkEnums:
+    def test_iota_block_in_another_file(self):
+        [package] = _packages(("status/types.go", TYPES), ("status/values.go", VALUES))
+        status = package.types["Status"]
+
+        assert status.is_enum
+        assert status.enum_values == [
+            EnumValue("Pending", "iota + 1", 1),
+            EnumValue("Running", None, 2),
+            EnumValue("Done", None, 3),
+        ]
+
+    def test_explicitly_typed_values(self):
+        [package] = _packages(("status/types.go", TYPES), ("status/values.go", VALUES))
+
+        assert package.types["Color"].enum_values == [
+            EnumValue("Red", '"red"', None),
+            EnumValue("Green", '"green"', None),
+        ]
+
+    def test_not_enums(self):
+        [package] = _packages(("status/types.go", TYPES), ("status/values.go", VALUES))
+
+        # Aliases and structs aren't enums, and neither is a type without a const block
+        assert not package.types["Alias"].is_enum
+        assert not package.types["Job"].is_enum
+        [package] = _packages(("status/types.go", TYPES + "\nconst Single Status = 1\n"))
+        assert not package.types["Status"].is_enum
+
+    def test_relinking_drops_removed_values(self):
+        [package] = _packages(("status/types.go", TYPES), ("status/values.go", VALUES))
+        status = package.types["Status"]
+        assert status.enum_values
+
+        link_enums(collect_go_packages([("status/types.go", package.files[0][1])]))
+        assert status.enum_values == []
+
+    def test_round_trip(self):
+        [package] = _packages(("status/types.go", TYPES), ("status/values.go", VALUES))
+        status = package.types["Status"]
+        data = status.to_dict()
+
+        assert data["enum_values"][1] == {"name": "Running", "int_value": 2}
+        assert type(status).from_dict(data).enum_values == status.enum_values
+
+
+class TestOutput:
+    @pytest.fixture
+    def store(self, tmp_path):
+        from codemap.core.indexer import Indexer
+        from codemap.core.map_store import MapStore
+
+        (tmp_path / "types.go").write_text(TYPES)
+        (tmp_path / "values.go").write_text(VALUES)
+        Indexer(tmp_path).index_all()
+        return MapStore.load(tmp_path)
+
+    def test_json(self, store):
+        from codemap.formatters import build_document
+
+        symbols = {s["name"]: s for s in build_document(store)["packages"][0]["symbols"]}
+        assert symbols["Status"]["enum"] is True
+        assert symbols["Status"]["enum_values"][0] == {"name": "Pending", "value": "iota + 1", "int_value": 1}
+        assert symbols["Running"]["int_value"] == 2
+        assert (symbols["Job"]["enum"], symbols["Job"]["enum_values"]) == (False, [])
+
+    def test_xml_round_trip(self, store):
+        from codemap.formatters import build_document
+        from codemap.formatters.xml_formatter import format_xml, load_xml
+
+        assert load_xml(format_xml(store)) == build_document(store)
+
+    def test_markdown(self, store):
+        from codemap.formatters.markdown_formatter import format_markdown
+
+        text = format_markdown(store)
+        assert "Values: `Pending` = 1, `Running` = 2, `Done` = 3" in text
+        assert 'Values: `Red` = `"red"`, `Green` = `"green"`' in text
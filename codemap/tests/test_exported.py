@@ -0,0 +1,104 @@
+"""Tests for filtering an index to exported symbols."""
+
+import shutil
+from pathlib import Path
+
+import pytest
+
+from codemap.analysis import filter_exported
+from codemap.core.map_store import MapStore
+from codemap.parsers.base import Field
+
+from .factories import go_symbol, make_store, make_symbol
+
+FIXTURES = Path(__file__).parent / "fixtures"
+
+
+def _store(tmp_path: Path, symbols) -> MapStore:
+    return make_store(tmp_path, {"lib/lib.go": symbols}, link=True, package="lib")
+
+
+def _names(store: MapStore) -> list[str]:
+    return [s.name for _, entry in store.get_all_files() for s in entry.symbols]
+
+
+class TestFilterExported:
+    """Tests for filter_exported."""
+
+    def test_go_fixture(self, tmp_path: Path):
+        pytest.importorskip("tree_sitter_go")
+        from codemap.core.indexer import Indexer
+
+        shutil.copy(FIXTURES / "sample_module.go", tmp_path / "sample_module.go")
+        Indexer(root=tmp_path, languages=["go"]).index_all()
+
+        filtered = filter_exported(MapStore.load(tmp_path))
+
+        symbols = filtered.get_file("sample_module.go").symbols
+        assert [s.name for s in symbols if s.type != "method"] == [
+            "User", "UserService", "DefaultService", "Greet", "Process",
+        ]
+        assert [s.name for s in symbols if s.type == "method"] == ["GetUser", "CreateUser"]
+        service = next(s for s in symbols if s.name == "DefaultService")
+        assert service.fields == []  # users is unexported
+
+    def test_drops_unexported_and_keeps_signatures(self, tmp_path: Path):
+        store = _store(tmp_path, [
+            go_symbol("Client", "struct", fields=[Field("Name", "string"), Field("conn", "*conn"), Field(None, "*base", embedded=True)]),
+            go_symbol("conn", "struct"),
+            go_symbol("Do", "method", receiver="*Client"),
+            go_symbol("retry", "method", receiver="*Client"),
+            go_symbol("Open", "function", signature="(c *conn) *Client"),
+            go_symbol("dial", "function"),
+        ])
+
+        filtered = filter_exported(store)
+
+        assert _names(filtered) == ["Client", "Do", "Open"]
+        client = filtered.get_file("lib/lib.go").symbols[0]
+        assert [f.name for f in client.fields] == ["Name"]
+        assert filtered.get_file("lib/lib.go").symbols[2].signature == "(c *conn) *Client"
+
+    def test_keeps_interface_methods_of_unexported_type(self, tmp_path: Path):
+        iface = go_symbol("Handler", "interface", children=[go_symbol("Serve", "method", signature="() error")])
+        store = _store(tmp_path, [
+            iface,
+            go_symbol("handler", "struct"),
+            go_symbol("Serve", "method", receiver="handler", signature="() error"),
+            go_symbol("Extra", "method", receiver="handler"),
+        ])
+
+        filtered = filter_exported(store)
+
+        assert _names(filtered) == ["Handler", "Serve"]
+        assert filtered.get_file("lib/lib.go").symbols[0].implemented_by == ["handler"]
+
+    def test_keeps_unexported_method_of_exported_interface(self, tmp_path: Path):
+        store = _store(tmp_path, [
+            go_symbol("Sealed", "interface", children=[go_symbol("sealed", "method", signature="()")]),
+            go_symbol("Token", "struct"),
+            go_symbol("sealed", "method", receiver="Token", signature="()"),
+        ])
+
+        filtered = filter_exported(store)
+
+        assert _names(filtered) == ["Sealed", "Token", "sealed"]
+        # Unexported interface methods are still dropped from the interface itself
+        assert filtered.get_file("lib/lib.go").symbols[0].children == []
+
+    def test_languages_without_exports_are_kept(self, tmp_path: Path):
+        store = make_store(tmp_path, {"app.py": [make_symbol("_helper", lines=(1, 2))]}, lines=3)
+
+        assert _names(filter_exported(store)) == ["_helper"]
+
+    def test_test_file_symbols_are_dropped(self, tmp_path: Path):
+        store = _store(tmp_path, [go_symbol("Shown", "function"), go_symbol("TestShown", "test", in_test=True)])
+
+        assert _names(filter_exported(store)) == ["Shown"]
+
+    def test_original_store_is_unchanged(self, tmp_path: Path):
+        store = _store(tmp_path, [go_symbol("hidden", "function"), go_symbol("Shown", "function")])
+
+        filter_exported(store)
+
+        assert _names(store) == ["hidden", "Shown"]
@@ -0,0 +1,205 @@
+"""Tests for file discovery."""
+
+import os
+import shutil
+from pathlib import Path
+
+from codemap.utils import file_utils
+from codemap.utils.config import Config
+from codemap.utils.file_utils import discover_files, glob_match, is_generated, within_depth
+
+FIXTURES = Path(__file__).parent / "fixtures"
+
+
+def _touch(root: Path, *paths: str) -> None:
+    for rel in paths:
+        path = root / rel
+        path.parent.mkdir(parents=True, exist_ok=True)
+        path.write_text("x = 1\n")
+
+
+def _discovered(root: Path, config: Config | None = None) -> list[str]:
+    return sorted(p.relative_to(root).as_posix() for p in discover_files(root, config or Config()))
+
+
+class TestDiscoverFiles:
+    """Tests for discover_files."""
+
+    def test_respects_gitignore(self, tmp_path: Path):
+        _touch(tmp_path, "main.py", "out/gen.py", "debug.py", "src/app.py")
+        (tmp_path / ".gitignore").write_text("out/\ndebug.py\n")
+
+        assert _discovered(tmp_path) == ["main.py", "src/app.py"]
+
+    def test_nested_gitignore(self, tmp_path: Path):
+        _touch(tmp_path, "a_gen.py", "pkg/b_gen.py", "pkg/keep_gen.py", "other/c_gen.py")
+        (tmp_path / ".gitignore").write_text("*_gen.py\n")
+        (tmp_path / "pkg" / ".gitignore").write_text("!keep_gen.py\n")
+
+        assert _discovered(tmp_path) == ["pkg/keep_gen.py"]
+
+    def test_gitignore_can_be_disabled(self, tmp_path: Path):
+        _touch(tmp_path, "main.py", "debug.py")
+        (tmp_path / ".gitignore").write_text("debug.py\n")
+
+        assert _discovered(tmp_path, Config(respect_gitignore=False)) == ["debug.py", "main.py"]
+
+    def test_custom_ignore_file(self, tmp_path: Path):
+        _touch(tmp_path, "main.py", "scratch/try.py")
+        (tmp_path / ".codemapignore").write_text("scratch/\n")
+
+        assert _discovered(tmp_path, Config(ignore_file=".codemapignore")) == ["main.py"]
+
+    def test_skips_vendor_unless_opted_in(self, tmp_path: Path):
+        _touch(tmp_path, "main.py", "vendor/lib/dep.py", "web/node_modules/pkg/index.py")
+
+        assert _discovered(tmp_path) == ["main.py"]
+        assert _discovered(tmp_path, Config(include_vendor=True)) == [
+            "main.py",
+            "vendor/lib/dep.py",
+            "web/node_modules/pkg/index.py",
+        ]
+
+    def test_skips_go_test_files_unless_opted_in(self, tmp_path: Path):
+        _touch(tmp_path, "main.go", "main_test.go", "testdata.go")
+
+        assert _discovered(tmp_path) == ["main.go", "testdata.go"]
+        assert _discovered(tmp_path, Config(include_tests=True)) == ["main.go", "main_test.go", "testdata.go"]
+
+    def test_ignored_directories_are_not_walked(self, tmp_path: Path, monkeypatch):
+        _touch(tmp_path, "main.py", "out/deep/gen.py", "vendor/dep.py")
+        (tmp_path / ".gitignore").write_text("out/\n")
+
+        scanned = []
+        real_scandir = os.scandir
+
+        def recording_scandir(path):
+            scanned.append(Path(path).relative_to(tmp_path).as_posix())
+            return real_scandir(path)
+
+        monkeypatch.setattr(file_utils.os, "scandir", recording_scandir)
+        list(discover_files(tmp_path, Config()))
+
+        assert scanned == ["."]
+
+    def test_max_depth(self, tmp_path: Path):
+        _touch(tmp_path, "main.go", "cmd/app/main.go", "internal/db/db.go", "internal/db/sql/sql.go", "api/api.go")
+
+        assert _discovered(tmp_path, Config(max_depth=0)) == ["main.go"]
+        assert _discovered(tmp_path, Config(max_depth=1)) == ["api/api.go", "main.go"]
+        assert _discovered(tmp_path, Config(max_depth=2)) == [
+            "api/api.go", "cmd/app/main.go", "internal/db/db.go", "main.go",
+        ]
+
+    def test_directories_past_max_depth_are_not_walked(self, tmp_path: Path, monkeypatch):
+        _touch(tmp_path, "main.go", "a/a.go", "a/b/b.go", "a/b/c/c.go")
+
+        scanned = []
+        real_scandir = os.scandir
+
+        def recording_scandir(path):
+            scanned.append(Path(path).relative_to(tmp_path).as_posix())
+            return real_scandir(path)
+
+        monkeypatch.setattr(file_utils.os, "scandir", recording_scandir)
+        list(discover_files(tmp_path, Config(max_depth=1)))
+
+        assert scanned == [".", "a"]
+
+    def test_max_depth_with_include_patterns(self, tmp_path: Path):
+        _touch(tmp_path, "main.go", "internal/db/db.go", "internal/api/api.go", "internal/api/v2/v2.go")
+        config = Config(max_depth=2, include_patterns=["internal/**"], exclude_patterns=["internal/db/**"])
+
+        assert _discovered(tmp_path, config) == ["internal/api/api.go"]
+
+    def test_include_and_exclude_overlap(self, tmp_path: Path):
+        shutil.copytree(FIXTURES, tmp_path / "fixtures")
+        _touch(tmp_path, "fixtures/mocks/mock_store.go", "fixtures/api/mock_client.go", "cmd/main.go")
+        config = Config(
+            include_patterns=["fixtures/**/*.go", "fixtures/*.py"],
+            exclude_patterns=["**/*_gen.go", "**/mock_*.go", "**/*.pb.go"],
+        )
+
+        assert _discovered(tmp_path, config) == [
+            "fixtures/complexity.go", "fixtures/sample_module.go", "fixtures/sample_module.py",
+            "fixtures/split_package/service.go", "fixtures/split_package/service_methods.go",
+        ]
+
+    def test_include_matches_directories(self, tmp_path: Path):
+        _touch(tmp_path, "main.py", "src/app.py", "src/core/db.py", "lib/util.py")
+
+        assert _discovered(tmp_path, Config(include_patterns=["src"])) == ["src/app.py", "src/core/db.py"]
+        assert _discovered(tmp_path, Config(include_patterns=["src/**", "*.py"])) == [
+            "main.py",
+            "src/app.py",
+            "src/core/db.py",
+        ]
+
+    def test_excluded_directories_are_not_walked(self, tmp_path: Path, monkeypatch):
+        _touch(tmp_path, "main.py", "gen/deep/out.py", "src/app.py")
+
+        scanned = []
+        real_scandir = os.scandir
+
+        def recording_scandir(path):
+            scanned.append(Path(path).relative_to(tmp_path).as_posix())
+            return real_scandir(path)
+
+        monkeypatch.setattr(file_utils.os, "scandir", recording_scandir)
+        found = list(discover_files(tmp_path, Config(exclude_patterns=["gen/**"])))
+
+        assert sorted(p.name for p in found) == ["app.py", "main.py"]
+        assert scanned == [".", "src"]
+
+
+class TestWithinDepth:
+    """Tests for within_depth."""
+
+    def test_depth_counts_directories(self):
+        assert within_depth("main.go", 0)
+        assert not within_depth("cmd/main.go", 0)
+        assert within_depth("cmd/app/main.go", 2)
+        assert not within_depth("cmd/app/main.go", 1)
+
+    def test_no_limit(self):
+        assert within_depth("a/b/c/d/e.go", None)
+
+
+class TestGlobMatch:
+    """Tests for glob_match."""
+
+    def test_single_star_stays_in_one_directory(self):
+        assert glob_match("main.go", "*.go")
+        assert not glob_match("cmd/main.go", "*.go")
+        assert glob_match("cmd/main.go", "cmd/*.go")
+
+    def test_double_star_spans_directories(self):
+        assert glob_match("x_gen.go", "**/*_gen.go")
+        assert glob_match("a/b/x_gen.go", "**/*_gen.go")
+        assert glob_match("a/b", "a/**/b")
+        assert glob_match("a/x/y/b", "a/**/b")
+        assert not glob_match("ab", "a/**/b")
+
+    def test_trailing_double_star_matches_directory(self):
+        assert glob_match("src", "src/**")
+        assert glob_match("src/a/b.py", "src/**")
+        assert not glob_match("srcs/a.py", "src/**")
+
+    def test_classes_and_alternatives(self):
+        assert glob_match("pkg/mock_db.go", "**/{mock,fake}_*.go")
+        assert glob_match("pkg/fake_db.go", "**/{mock,fake}_*.go")
+        assert not glob_match("pkg/real_db.go", "**/{mock,fake}_*.go")
+        assert glob_match("v1.go", "v[0-9].go")
+        assert not glob_match("va.go", "v[!a].go")
+        assert glob_match("a.go", "?.go")
+
+
+class TestIsGenerated:
+    def test_marker_from_any_tool(self):
+        assert is_generated("// Code generated by protoc-gen-go. DO NOT EDIT.\n\npackage pb\n")
+        assert is_generated("// Copyright 2024 Acme\n\n// Code generated by mockgen. DO NOT EDIT.\npackage mocks\n")
+
+    def test_marker_must_match_exactly_before_package(self):
+        assert not is_generated("package pb\n\n// Code generated by protoc-gen-go. DO NOT EDIT.\n")
+        assert not is_generated("// Code generated by protoc-gen-go. Do not edit.\npackage pb\n")
+        assert not is_generated("// Code generated by hand, then edited. DO NOT EDIT. Really.\npackage pb\n")
@@ -0,0 +1,72 @@
+"""Tests for gitignore-style rule matching."""
+
+import pytest
+
+from codemap.utils.gitignore import IgnoreRules, is_ignored
+
+
+def _rules(*lines, base=""):
+    return IgnoreRules.from_lines(list(lines), base)
+
+
+class TestIgnoreRules:
+    """Tests for IgnoreRules.match."""
+
+    @pytest.mark.parametrize("path,is_dir,expected", [
+        ("app.log", False, True),
+        ("logs/app.log", False, True),
+        ("app.py", False, None),
+    ])
+    def test_extension_pattern_matches_at_any_depth(self, path, is_dir, expected):
+        assert _rules("*.log").match(path, is_dir) is expected
+
+    def test_directory_pattern_only_matches_directories(self):
+        rules = _rules("out/")
+
+        assert rules.match("out", is_dir=True) is True
+        assert rules.match("src/out", is_dir=True) is True
+        assert rules.match("out", is_dir=False) is None
+
+    def test_anchored_pattern(self):
+        rules = _rules("/build", "docs/*.md")
+
+        assert rules.match("build", is_dir=True) is True
+        assert rules.match("src/build", is_dir=True) is None
+        assert rules.match("docs/a.md", is_dir=False) is True
+        assert rules.match("docs/sub/a.md", is_dir=False) is None
+
+    def test_double_star(self):
+        rules = _rules("**/gen/**", "a/**/z.go")
+
+        assert rules.match("x/gen/y.go", is_dir=False) is True
+        assert rules.match("a/z.go", is_dir=False) is True
+        assert rules.match("a/b/c/z.go", is_dir=False) is True
+
+    def test_negation_last_match_wins(self):
+        rules = _rules("*.go", "!keep.go")
+
+        assert rules.match("drop.go", is_dir=False) is True
+        assert rules.match("keep.go", is_dir=False) is False
+
+    def test_comments_and_blank_lines(self):
+        rules = _rules("# comment", "", "   ", r"\#literal")
+
+        assert len(rules.rules) == 1
+        assert rules.match("#literal", is_dir=False) is True
+
+    def test_rules_scoped_to_base(self):
+        rules = _rules("*.tmp", base="pkg")
+
+        assert rules.match("pkg/a.tmp", is_dir=False) is True
+        assert rules.match("a.tmp", is_dir=False) is None
+
+
+class TestIsIgnored:
+    """Tests for precedence across nested ignore files."""
+
+    def test_deeper_file_takes_precedence(self):
+        rule_sets = [_rules("*.gen.go"), _rules("!api.gen.go", base="api")]
+
+        assert is_ignored(rule_sets, "x.gen.go", is_dir=False)
+        assert not is_ignored(rule_sets, "api/api.gen.go", is_dir=False)
+        assert is_ignored(rule_sets, "api/other.gen.go", is_dir=False)
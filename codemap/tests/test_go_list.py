@@ -0,0 +1,101 @@
+"""Tests for mapping a Go package by import path."""
+
+import shutil
+import subprocess
+from pathlib import Path
+
+import pytest
+
+from codemap.core.go_list import GoListError, go_list, load_package
+from codemap.formatters import build_document
+from codemap.utils.config import Config
+
+requires_go = pytest.mark.skipif(shutil.which("go") is None, reason="go not installed")
+
+
+def _module(root: Path) -> Path:
+    (root / "go.mod").write_text("module example.com/variants\n\ngo 1.21\n")
+    (root / "common.go").write_text("package variants\n\nfunc Common() {}\n")
+    (root / "os_linux.go").write_text("package variants\n\nfunc Linux() {}\n")
+    (root / "os_windows.go").write_text("package variants\n\nfunc Windows() {}\n")
+    (root / "slow.go").write_text("//go:build integration\n\npackage variants\n\nfunc Slow() {}\n")
+    (root / "common_test.go").write_text("package variants\n\nimport \"testing\"\n\nfunc TestCommon(t *testing.T) {}\n")
+    return root
+
+
+def _names(store) -> list[str]:
+    return sorted(s.name for _, entry in store.get_all_files() for s in entry.symbols)
+
+
+@requires_go
+class TestLoadPackage:
+    def test_standard_library(self):
+        pytest.importorskip("tree_sitter_go")
+        store, errors = load_package("encoding/csv")
+
+        assert errors == []
+        assert {"NewReader", "NewWriter", "Reader", "Writer"} <= set(_names(store))
+        package = build_document(store)["packages"][0]
+        assert (package["name"], package["import_path"], package["import_path_resolved"]) == (
+            "csv", "encoding/csv", True,
+        )
+
+    def test_build_context_picks_variants(self, tmp_path: Path):
+        pytest.importorskip("tree_sitter_go")
+        _module(tmp_path)
+
+        linux, _ = load_package(".", Config(goos="linux"), cwd=tmp_path)
+        windows, _ = load_package(".", Config(goos="windows", build_tags=["integration"]), cwd=tmp_path)
+
+        assert _names(linux) == ["Common", "Linux"]
+        assert _names(windows) == ["Common", "Slow", "Windows"]
+        assert linux.root == tmp_path.resolve()
+
+    def test_tests_and_nothing_written(self, tmp_path: Path):
+        pytest.importorskip("tree_sitter_go")
+        _module(tmp_path)
+
+        store, _ = load_package("example.com/variants", Config(goos="linux", include_tests=True), cwd=tmp_path)
+
+        assert "TestCommon" in _names(store)
+        assert not (tmp_path / ".codemap").exists()
+
+    def test_go_list(self, tmp_path: Path):
+        _module(tmp_path)
+
+        package = go_list(".", Config(goos="windows"), cwd=tmp_path)
+
+        assert (package.import_path, package.name, package.module) == (
+            "example.com/variants", "variants", "example.com/variants",
+        )
+        assert package.directory == tmp_path.resolve()
+        assert package.go_files == ["common.go", "os_windows.go"]
+        assert package.test_files == ["common_test.go"]
+        assert not package.standard
+
+    def test_unknown_package(self, tmp_path: Path):
+        _module(tmp_path)
+
+        with pytest.raises(GoListError):
+            go_list("example.com/variants/missing", cwd=tmp_path)
+        with pytest.raises(GoListError, match="more than one package"):
+            go_list("std", cwd=tmp_path)
+
+
+class TestGoListOutput:
+    """Tests for go list output that isn't one package, without running go."""
+
+    @pytest.mark.parametrize("returncode, stdout, message", [
+        (0, "", "printed nothing"),
+        (0, "go: warning: something odd\n", "Unreadable go list output"),
+        (0, "[]", "didn't report a package directory"),
+        (1, "go: example.com/x: module lookup disabled\n", "module lookup disabled"),
+    ])
+    def test_errors(self, monkeypatch, returncode: int, stdout: str, message: str):
+        def run(command, **kwargs):
+            return subprocess.CompletedProcess(command, returncode, stdout=stdout, stderr="")
+
+        monkeypatch.setattr(subprocess, "run", run)
+
+        with pytest.raises(GoListError, match=message):
+            go_list("example.com/x")
@@ -0,0 +1,56 @@
+"""Tests for go.mod lookup and import path resolution."""
+
+from pathlib import Path
+
+from codemap.analysis.go_module import ModuleResolver, read_module_path
+
+
+class TestReadModulePath:
+    def test_reads_module_directive(self, tmp_path: Path):
+        (tmp_path / "go.mod").write_text("// comment\nmodule github.com/me/proj\n\ngo 1.22\n")
+
+        assert read_module_path(tmp_path) == "github.com/me/proj"
+
+    def test_missing_go_mod(self, tmp_path: Path):
+        assert read_module_path(tmp_path) is None
+
+
+class TestModuleResolver:
+    def test_root_and_subdirectories(self, tmp_path: Path):
+        (tmp_path / "go.mod").write_text("module github.com/me/proj\n")
+        resolver = ModuleResolver(tmp_path)
+
+        assert resolver.import_path(".") == ("github.com/me/proj", True)
+        assert resolver.import_path("internal/util") == ("github.com/me/proj/internal/util", True)
+
+    def test_nearest_go_mod_wins(self, tmp_path: Path):
+        (tmp_path / "go.mod").write_text("module github.com/me/proj\n")
+        (tmp_path / "tools").mkdir()
+        (tmp_path / "tools" / "go.mod").write_text("module github.com/me/tools\n")
+        resolver = ModuleResolver(tmp_path)
+
+        assert resolver.import_path("tools") == ("github.com/me/tools", True)
+        assert resolver.import_path("tools/gen/util") == ("github.com/me/tools/gen/util", True)
+        assert resolver.import_path("util") == ("github.com/me/proj/util", True)
+
+    def test_standard_library_paths_are_unprefixed(self, tmp_path: Path):
+        (tmp_path / "go.mod").write_text("module std\n")
+        (tmp_path / "encoding" / "json").mkdir(parents=True)
+        resolver = ModuleResolver(tmp_path / "encoding" / "json")
+
+        assert resolver.import_path(".") == ("encoding/json", True)
+
+    def test_root_inside_module(self, tmp_path: Path):
+        (tmp_path / "go.mod").write_text("module github.com/me/proj\n")
+        (tmp_path / "internal").mkdir()
+        resolver = ModuleResolver(tmp_path / "internal")
+
+        assert resolver.import_path("util") == ("github.com/me/proj/internal/util", True)
+
+    def test_unresolved_falls_back_to_directory(self, tmp_path: Path):
+        (tmp_path / "broken").mkdir()
+        (tmp_path / "broken" / "go.mod").write_text("go 1.22\n")
+        resolver = ModuleResolver(tmp_path)
+
+        assert resolver.import_path("internal/util") == ("internal/util", False)
+        assert resolver.import_path("broken/x") == ("broken/x", False)
@@ -5,6 +5,7 @@ import pytest
 # Skip all tests if tree-sitter-go is not installed
 pytest.importorskip("tree_sitter_go")
 
+from codemap.parsers.base import Symbol
 from codemap.parsers.go_parser import GoParser
 
 
@@ -111,3 +112,550 @@ func Multiply(a, b int) int {
         # Check for expected function
         names = [s.name for s in symbols]
         assert "Greet" in names or any("Greet" in str(s) for s in symbols)
+
+    def test_complexity(self, parser):
+        """Decision points are counted, function literals included in their enclosing function."""
+        import os
+        fixture_path = os.path.join(os.path.dirname(__file__), "fixtures", "complexity.go")
+        with open(fixture_path, "r") as f:
+            source = f.read()
+
+        symbols = {s.name: s for s in parser.parse(source, fixture_path)}
+
+        assert symbols["Do"].complexity == 13
+        assert symbols["Attempts"].complexity == 1
+        assert symbols["Policy"].complexity is None
+        assert symbols["ErrGiveUp"].complexity is None
+        assert Symbol.from_dict(symbols["Do"].to_dict()).complexity == 13
+
+    def test_complexity_of_each_decision_point(self, parser):
+        source = '''package main
+
+func If(x int) { if x > 0 {} else if x < 0 {} else {} }
+func Loops(xs []int) { for range xs {}; for {} }
+func Cases(x any) { switch x.(type) { case int, string: case bool: default: } }
+func Logic(a, b, c bool) bool { return a && b || !c }
+func Stub()
+'''
+        symbols = {s.name: s.complexity for s in parser.parse(source)}
+
+        assert symbols == {"If": 3, "Loops": 3, "Cases": 3, "Logic": 3, "Stub": None}
+
+    def test_body_lines_and_stubs(self, parser):
+        source = '''package main
+
+type Store interface {
+	Get(key string) (string, error)
+}
+
+func Empty() {}
+func Commented() {
+	// TODO: write this
+}
+func Unimplemented() { panic("not implemented") }
+func Sentinel() error { panic(ErrUnimplemented) }
+func NilReturn() error {
+	return nil
+}
+func NilResults() (*Store, error) { return nil, nil }
+func Bare() (n int) { return }
+func Passthrough(data []byte) ([]byte, error) {
+	return data, nil
+}
+func Unreachable() { panic("unreachable") }
+func (s *impl) Get(key string) (string, error) {
+	v, ok := s.m[key]
+	if !ok {
+		return "", ErrMissing
+	}
+	return v, nil
+}
+func Asm(x int) int
+'''
+        symbols = {s.name: s for s in parser.parse(source) if s.receiver is None}
+        get = next(s for s in parser.parse(source) if s.receiver == "*impl")
+
+        assert {name: (s.body_lines, s.is_stub) for name, s in symbols.items() if s.type == "function"} == {
+            "Empty": (0, True),
+            "Commented": (1, True),
+            "Unimplemented": (1, True),
+            "Sentinel": (1, True),
+            "NilReturn": (1, True),
+            "NilResults": (1, True),
+            "Bare": (1, True),
+            "Passthrough": (1, False),
+            "Unreachable": (1, False),
+            "Asm": (None, False),
+        }
+        assert (get.body_lines, get.is_stub) == (5, False)
+        # Interface methods have no body to count
+        assert [(m.body_lines, m.is_stub) for m in symbols["Store"].children] == [(None, False)]
+        assert Symbol.from_dict(symbols["Empty"].to_dict()).body_lines == 0
+        assert Symbol.from_dict(symbols["Empty"].to_dict()).is_stub
+        assert "body_lines" not in symbols["Asm"].to_dict()
+
+    @pytest.mark.parametrize("body, lines", [
+        ("{}", 0),
+        ("{\n}", 0),
+        ("{\n\n}", 0),
+        ("{ return }", 1),
+        ("{\n\treturn\n}", 1),
+        ("{\n\n\treturn\n\n}", 1),
+        ("{\n\tx := 1\n\n\t_ = x\n}", 3),
+        ("{ x := 1\n\t_ = x\n}", 2),
+        ("{\n\tx := 1\n\t_ = x }", 2),
+        ("{\n\t// first\n\treturn\n\t// last\n}", 3),
+    ])
+    def test_body_lines(self, parser, body, lines):
+        symbol, = parser.parse(f"package main\n\nfunc F() {body}\n")
+
+        assert symbol.body_lines == lines
+
+    def test_parse_file_reports_package(self, parser):
+        source = '''package sample
+
+func Greet() {}
+'''
+        result = parser.parse_file(source)
+
+        assert result.package == "sample"
+        assert [s.name for s in result.symbols] == ["Greet"]
+
+    def test_exported_flag(self, parser):
+        source = '''package main
+
+func Public() {}
+
+func private() {}
+'''
+        symbols = parser.parse(source)
+
+        assert symbols[0].exported is True
+        assert symbols[1].exported is False
+
+    def test_method_receiver_and_signature(self, parser):
+        source = '''package main
+
+type Service struct{}
+
+// Get fetches an item.
+func (s *Service) Get(id int) (*Item, error) {
+    return nil, nil
+}
+'''
+        symbols = parser.parse(source)
+        method = next(s for s in symbols if s.type == "method")
+
+        assert method.receiver == "*Service"
+        assert method.signature == "(id int) (*Item, error)"
+        assert method.docstring == "Get fetches an item."
+
+    def test_struct_and_interface_types(self, parser):
+        source = '''package main
+
+// User represents a user.
+type User struct {
+    ID int
+}
+
+type Store interface {
+    Load(id int) (*User, error)
+}
+
+type ID int
+'''
+        symbols = parser.parse(source)
+
+        assert [(s.name, s.type) for s in symbols] == [
+            ("User", "struct"),
+            ("Store", "interface"),
+            ("ID", "type"),
+        ]
+        assert symbols[0].docstring == "User represents a user."
+        assert [m.name for m in symbols[1].children] == ["Load"]
+        assert symbols[2].signature == "int"
+
+    def test_embedded_types(self, parser):
+        source = '''package main
+
+type Conn struct {
+    *base
+    io.Reader
+    name string
+}
+
+type ReadCloser interface {
+    Reader
+    Close() error
+}
+'''
+        symbols = parser.parse(source)
+
+        assert symbols[0].embeds == ["*base", "io.Reader"]
+        assert symbols[1].embeds == ["Reader"]
+        assert [m.name for m in symbols[1].children] == ["Close"]
+
+    def test_generic_function(self, parser):
+        source = '''package main
+
+func Map[T any, U any](s []T, f func(T) U) []U {
+    return nil
+}
+'''
+        func = parser.parse(source)[0]
+
+        assert func.signature == "[T any, U any](s []T, f func(T) U) []U"
+        assert [(p.name, p.constraint) for p in func.type_params] == [("T", "any"), ("U", "any")]
+
+    def test_generic_type_and_method(self, parser):
+        source = '''package main
+
+type Set[K comparable, V int | string] map[K]V
+
+func (s *Set[K, V]) Add(k K) {}
+'''
+        set_type, add = parser.parse(source)
+
+        assert [(p.name, p.constraint) for p in set_type.type_params] == [
+            ("K", "comparable"),
+            ("V", "int | string"),
+        ]
+        assert add.receiver == "*Set[K, V]"
+        assert [(p.name, p.constraint) for p in add.type_params] == [("K", None), ("V", None)]
+        assert add.to_dict()["type_params"] == [{"name": "K"}, {"name": "V"}]
+
+    def test_struct_fields(self, parser):
+        source = '''package main
+
+type Conn struct {
+    io.Reader
+    *base
+    ID      int               `json:"id"`
+    X, Y    float64           "xml:\\"pos\\""
+    users   map[int]*User
+}
+'''
+        conn = parser.parse(source)[0]
+
+        assert [(f.name, f.type, f.tag, f.embedded) for f in conn.fields] == [
+            (None, "io.Reader", None, True),
+            (None, "*base", None, True),
+            ("ID", "int", 'json:"id"', False),
+            ("X", "float64", 'xml:"pos"', False),
+            ("Y", "float64", 'xml:"pos"', False),
+            ("users", "map[int]*User", None, False),
+        ]
+        assert conn.embeds == ["io.Reader", "*base"]
+
+    def test_multiline_doc_comment(self, parser):
+        source = '''package main
+
+// Greet says hello.
+// It is polite.
+func Greet() {}
+'''
+        symbols = parser.parse(source)
+
+        assert symbols[0].docstring == "Greet says hello.\nIt is polite."
+
+    def test_const_group(self, parser):
+        source = '''package main
+
+// Colors supported by the renderer.
+const (
+    Red Color = iota // primary
+    Green
+    Blue
+)
+
+// MaxSize limits the buffer.
+const MaxSize = 1 << 10
+'''
+        red, green, blue, max_size = parser.parse(source)
+
+        assert [(s.name, s.type, s.signature, s.value, s.group) for s in (red, green, blue)] == [
+            ("Red", "const", "Color", "iota", "Red"),
+            ("Green", "const", "Color", None, "Red"),
+            ("Blue", "const", "Color", None, "Red"),
+        ]
+        assert red.docstring == "primary"
+        assert green.lines == (6, 6)
+        assert (max_size.signature, max_size.value, max_size.group) == (None, "1 << 10", None)
+        assert max_size.docstring == "MaxSize limits the buffer."
+        assert max_size.lines == (11, 11)
+
+    def test_const_int_values(self, parser):
+        source = '''package main
+
+const (
+    _  = iota
+    KB = 1 << (10 * iota)
+    MB
+)
+
+const (
+    A, B = iota, iota * 10
+    C, D
+    Mode = 0755 &^ 022
+    Half = -7 / 2
+    Rest = -7 % 2
+    Day  = Weekday(5) + A
+    Tab  = '\\t'
+    Hex  = '\\x41'
+    E    = 'é'
+    Size = len("abc")
+    Name = "x"
+)
+'''
+        values = {s.name: s.int_value for s in parser.parse(source)}
+
+        assert values == {
+            "KB": 1024, "MB": 1 << 20,
+            "A": 0, "B": 0, "C": 1, "D": 10,
+            "Mode": 0o755 & ~0o22, "Half": -3, "Rest": -1, "Day": 5,
+            "Tab": 9, "Hex": 65, "E": 233, "Size": None, "Name": None,
+        }
+
+    def test_var_declarations(self, parser):
+        source = '''package main
+
+var (
+    Version = "1.0"
+    client  = &Client{}
+    _       Stringer = Color(0)
+)
+
+var a, b int
+'''
+        symbols = parser.parse(source)
+
+        assert [(s.name, s.type, s.signature, s.value, s.exported) for s in symbols] == [
+            ("Version", "var", "string", '"1.0"', True),
+            ("client", "var", "*Client", "&Client{}", False),
+            ("a", "var", "int", None, False),
+            ("b", "var", "int", None, False),
+        ]
+        assert symbols[0].group == "Version"
+        assert symbols[2].group is None
+
+    def test_type_alias(self, parser):
+        source = '''package main
+
+type (
+    ID = int
+    Name string
+)
+'''
+        alias, defined = parser.parse(source)
+
+        assert (alias.name, alias.signature, alias.is_alias) == ("ID", "int", True)
+        assert (defined.name, defined.signature, defined.is_alias) == ("Name", "string", False)
+        assert alias.to_dict()["is_alias"] is True
+        assert "is_alias" not in defined.to_dict()
+
+    def test_imports(self, parser):
+        source = '''package main
+
+import "fmt"
+
+import (
+    h "net/http"
+    . "strings"
+    _ "embed"
+)
+'''
+        result = parser.parse_file(source)
+
+        assert [(i.path, i.name) for i in result.imports] == [
+            ("fmt", None), ("net/http", "h"), ("strings", "."), ("embed", "_"),
+        ]
+
+    def test_positions(self, parser):
+        import os
+        fixture_path = os.path.join(os.path.dirname(__file__), "fixtures", "sample_module.go")
+        with open(fixture_path, "r") as f:
+            symbols = parser.parse(f.read())
+
+        get_user = next(s for s in symbols if s.name == "GetUser")
+        user = next(s for s in symbols if s.name == "User")
+        service = next(s for s in symbols if s.name == "UserService")
+
+        assert str(get_user.pos("sample_module.go")) == "sample_module.go:24:1"
+        assert str(get_user.end_pos("sample_module.go")) == "sample_module.go:29:2"
+        assert str(user.fields[1].pos("sample_module.go")) == "sample_module.go:9:2"
+        assert str(service.children[0].pos("sample_module.go")) == "sample_module.go:14:2"
+
+    def test_test_file_symbols(self, parser):
+        source = '''package main_test
+
+type fixture struct{}
+
+func TestParse(t *testing.T) {}
+
+func Test_parse(t *testing.T) {}
+
+func BenchmarkParse(b *testing.B) {}
+
+func ExampleParse() {}
+
+func FuzzParse(f *testing.F) {}
+
+func Testify() {}
+'''
+        result = parser.parse_file(source, "parse_test.go")
+
+        assert result.package == "main_test"
+        assert [(s.name, s.type) for s in result.symbols] == [
+            ("fixture", "struct"),
+            ("TestParse", "test"),
+            ("Test_parse", "test"),
+            ("BenchmarkParse", "benchmark"),
+            ("ExampleParse", "example"),
+            ("FuzzParse", "fuzz"),
+            ("Testify", "function"),
+        ]
+        assert all(s.in_test for s in result.symbols)
+        assert result.symbols[1].to_dict()["in_test"] is True
+
+    def test_regular_file_is_not_test(self, parser):
+        symbols = parser.parse("package main\n\nfunc TestParse() {}\n", "parse.go")
+
+        assert (symbols[0].type, symbols[0].in_test) == ("function", False)
+        assert "in_test" not in symbols[0].to_dict()
+
+    def test_params_and_named_results(self, parser):
+        source = '''package main
+
+func (s *DefaultService) GetUser(id int) (*User, error) {}
+
+func Split(s string) (before, after string, found bool) {}
+
+func Printf(_ context.Context, format string, args ...any) {}
+
+func handler(http.ResponseWriter, *http.Request) {}
+'''
+        get_user, split, printf, handler = parser.parse(source)
+
+        assert [str(p) for p in get_user.params] == ["id int"]
+        assert [str(r) for r in get_user.results] == ["*User", "error"]
+        assert [(r.name, r.type) for r in split.results] == [
+            ("before", "string"), ("after", "string"), ("found", "bool"),
+        ]
+        assert [(p.name, p.type, p.variadic) for p in printf.params] == [
+            ("_", "context.Context", False), ("format", "string", False), ("args", "any", True),
+        ]
+        assert str(printf.params[2]) == "args ...any"
+        assert [(p.name, p.type) for p in handler.params] == [
+            (None, "http.ResponseWriter"), (None, "*http.Request"),
+        ]
+        assert printf.to_dict()["params"][2] == {"name": "args", "type": "any", "variadic": True}
+        assert Symbol.from_dict(printf.to_dict()).params == printf.params
+
+    def test_deprecated(self, parser):
+        source = '''package main
+
+// Old does things.
+//
+// Deprecated: Use New instead.
+// It will be removed in v2.
+//
+// More details.
+func Old() {}
+
+// Config holds settings.
+type Config struct {
+    // Deprecated: use Timeout.
+    Wait    int
+    Timeout int // Deprecated: set on the client.
+    Name    string
+}
+
+// Reader reads.
+type Reader interface {
+    // Deprecated:
+    ReadOld() error
+}
+
+// Deprecated: MaxSize is unused.
+const MaxSize = 10
+
+// Mentions Deprecated: mid-paragraph, which doesn't count.
+var current = 1
+'''
+        old, config, reader, max_size, current = parser.parse(source)
+
+        assert (old.deprecated, old.deprecation) == (True, "Use New instead. It will be removed in v2.")
+        assert [(f.name, f.deprecated, f.deprecation) for f in config.fields] == [
+            ("Wait", True, "use Timeout."),
+            ("Timeout", True, "set on the client."),
+            ("Name", False, None),
+        ]
+        assert (reader.children[0].deprecated, reader.children[0].deprecation) == (True, None)
+        assert max_size.deprecation == "MaxSize is unused."
+        assert current.deprecated is False
+        assert old.to_dict()["deprecation"] == "Use New instead. It will be removed in v2."
+        assert "deprecated" not in current.to_dict()
+
+    def test_generated_file(self, parser):
+        source = "// Code generated by stringer; DO NOT EDIT.\n\npackage main\n\ntype Color int\n\nfunc (c Color) String() string {}\n"
+        color, string = parser.parse(source)
+
+        assert color.generated and string.generated
+        assert color.to_dict()["generated"] is True
+        assert parser.parse("package main\n\n// Code generated by hand. DO NOT EDIT.\nfunc F() {}\n")[0].generated is False
+
+    def test_notes(self, parser):
+        source = (
+            "package main\n"
+            "\n"
+            "// TODO(alice): drop the v1 API once\n"
+            "// clients have migrated.\n"
+            "//\n"
+            "// Run starts things.\n"
+            "func Run() {\n"
+            '\ts := "TODO: not a note"\n'
+            "\tprint(s) // FIXME handle the error\n"
+            "\t/* HACK: work around\n"
+            "\t   the scheduler bug\n"
+            "\n"
+            "\t   unrelated */\n"
+            "\tx := `// XXX inside a raw string`\n"
+            "\t_ = x\n"
+            "}\n"
+            "\n"
+            "/*\n"
+            " * XXX(bob) star style\n"
+            " */\n"
+            "var y = 1 // TODOS are not notes\n"
+        )
+        notes = parser.parse_file(source, "main.go").notes
+
+        assert [(n.kind, n.author, n.text, n.line, n.column) for n in notes] == [
+            ("TODO", "alice", "drop the v1 API once clients have migrated.", 3, 4),
+            ("FIXME", None, "handle the error", 9, 14),
+            ("HACK", None, "work around the scheduler bug", 10, 5),
+            ("XXX", "bob", "star style", 19, 4),
+        ]
+        assert str(notes[0].pos("main.go")) == "main.go:3:4"
+
+    def test_adjacent_notes_stay_separate(self, parser):
+        source = "package main\n\n// TODO: one\n// FIXME: two\n// more on two\nfunc F() {}\n"
+        notes = parser.parse_file(source, "f.go").notes
+
+        assert [(n.kind, n.text) for n in notes] == [("TODO", "one"), ("FIXME", "two more on two")]
+
+    def test_package_doc(self, parser):
+        source = (
+            "// Copyright 2024 The Authors.\n"
+            "\n"
+            "// Package cache provides an LRU cache.\n"
+            "//\n"
+            "// Entries expire after a TTL.\n"
+            "//go:generate stringer -type=Mode\n"
+            "package cache\n"
+        )
+        result = parser.parse_file(source, "doc.go")
+
+        assert result.package_doc == "Package cache provides an LRU cache.\n\nEntries expire after a TTL."
+        assert parser.parse_file("/*\nPackage cache caches.\n*/\npackage cache\n").package_doc == "Package cache caches."
+        assert parser.parse_file("package cache\n").package_doc is None
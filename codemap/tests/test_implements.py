@@ -0,0 +1,417 @@
+"""Tests for Go interface satisfaction analysis."""
+
+from codemap.analysis.go_packages import (
+    collect_go_packages,
+    link_methods,
+    method_key,
+    parameter_types,
+    receiver_base,
+    signature_key,
+)
+from codemap.analysis.implements import PackageIndex, link_implementations
+from codemap.core.map_store import FileEntry, MapStore
+from codemap.parsers.base import Import, Param, Symbol
+
+
+def _entry(package, symbols):
+    """Build a Go file entry."""
+    return FileEntry(hash="h", indexed_at="", language="go", lines=1, symbols=symbols, package=package)
+
+
+def _method(name, receiver, signature="()"):
+    return Symbol(name=name, type="method", lines=(1, 1), signature=signature, receiver=receiver)
+
+
+def _iface(name, *methods, embeds=None):
+    children = [Symbol(name=m, type="method", lines=(1, 1), signature=sig) for m, sig in methods]
+    return Symbol(name=name, type="interface", lines=(1, 1), children=children, embeds=embeds or [])
+
+
+def _struct(name, embeds=None):
+    return Symbol(name=name, type="struct", lines=(1, 1), embeds=embeds or [])
+
+
+def _link(*files):
+    packages = collect_go_packages(files)
+    link_implementations(packages)
+    return packages
+
+
+class TestSignatureKey:
+    """Tests for signature normalization."""
+
+    def test_drops_parameter_names(self):
+        assert signature_key("(id int) (*User, error)") == signature_key("(userID int) (*User, error)")
+
+    def test_grouped_and_variadic_parameters(self):
+        assert parameter_types("(a, b int, rest ...string)") == ["int", "int", "...string"]
+
+    def test_unnamed_parameters(self):
+        assert parameter_types("(int, chan int)") == ["int", "chan int"]
+
+    def test_truncated_signature_is_not_comparable(self):
+        assert signature_key("(a int, b int, ...") is None
+
+    def test_method_key_from_params_and_results(self):
+        method = Symbol(
+            name="Find", type="method", lines=(1, 1), signature="(id int, opts ...Option) (*User, error)",
+            params=[Param("id", "int"), Param("opts", "Option", variadic=True)],
+            results=[Param(None, "*User"), Param(None, "error")],
+        )
+
+        assert method_key(method) == signature_key(method.signature) == "(int,...Option)(*User,error)"
+        assert method_key(_method("Close", "F", "() error")) == "()(error)"
+
+
+class TestReceiverBase:
+    """Tests for receiver_base."""
+
+    def test_plain_and_pointer(self):
+        assert receiver_base("User") == ("User", False)
+        assert receiver_base("*User") == ("User", True)
+
+    def test_generic(self):
+        assert receiver_base("*Cache[K, V]") == ("Cache", True)
+        assert receiver_base("Set[T]") == ("Set", False)
+
+    def test_parentheses_and_spacing(self):
+        assert receiver_base("(*T)") == ("T", True)
+        assert receiver_base("*(T)") == ("T", True)
+        assert receiver_base("* Map [ K ]") == ("Map", True)
+
+
+class TestLinkMethods:
+    """Tests for link_methods."""
+
+    def test_methods_from_every_file_of_the_package(self):
+        cache = Symbol(name="Cache", type="struct", lines=(1, 1))
+        service = _struct("Service")
+        other = _struct("Service")
+        packages = collect_go_packages([
+            ("svc/a.go", _entry("svc", [service, _method("Get", "*Service"), cache])),
+            ("svc/b.go", _entry("svc", [_method("Put", "Service"), _method("Len", "(*Cache[K, V])")])),
+            ("svc/c.go", _entry("svc", [_method("Close", "*Conn")])),
+            ("other/a.go", _entry("svc", [other])),
+        ])
+
+        link_methods(packages)
+
+        assert service.methods == ["Get", "Put"]
+        assert cache.methods == ["Len"]
+        assert other.methods == []
+
+
+class TestLinkImplementations:
+    """Tests for link_implementations."""
+
+    def test_pointer_receivers_satisfy_only_pointer(self):
+        iface = _iface("UserService", ("GetUser", "(id int) (*User, error)"))
+        service = _struct("DefaultService")
+        packages = _link(("svc.go", _entry("sample", [
+            _struct("User"),
+            iface,
+            service,
+            _method("GetUser", "*DefaultService", "(userID int) (*User, error)"),
+        ])))
+
+        assert iface.implemented_by == ["*DefaultService"]
+        assert service.implements == ["UserService"]
+        index = PackageIndex(packages)
+        assert "GetUser" not in index.method_set(packages[0], "DefaultService", pointer=False)
+        assert "GetUser" in index.method_set(packages[0], "DefaultService", pointer=True)
+
+    def test_value_receivers_satisfy_value_type(self):
+        iface = _iface("Stringer", ("String", "() string"))
+        _link(("a.go", _entry("p", [iface, _struct("Name"), _method("String", "Name", "() string")])))
+
+        assert iface.implemented_by == ["Name"]
+
+    def test_signature_mismatch_does_not_satisfy(self):
+        iface = _iface("Stringer", ("String", "() string"))
+        thing = _struct("Thing")
+        _link(("a.go", _entry("p", [iface, thing, _method("String", "Thing", "() int")])))
+
+        assert iface.implemented_by == []
+        assert thing.implements == []
+
+    def test_methods_promoted_from_embedded_struct(self):
+        iface = _iface("Closer", ("Close", "() error"))
+        outer = _struct("Conn", embeds=["*base"])
+        _link(("a.go", _entry("p", [
+            iface, _struct("base"), outer, _method("Close", "*base", "() error"),
+        ])))
+
+        # Embedding *base promotes its pointer methods into the value type Conn
+        assert "Conn" in iface.implemented_by
+        assert outer.implements == ["Closer"]
+
+    def test_embedded_value_struct_promotes_pointer_methods_to_pointer_only(self):
+        iface = _iface("Closer", ("Close", "() error"))
+        _link(("a.go", _entry("p", [
+            iface, _struct("base"), _struct("Conn", embeds=["base"]), _method("Close", "*base", "() error"),
+        ])))
+
+        assert "*Conn" in iface.implemented_by
+        assert "Conn" not in iface.implemented_by
+
+    def test_embedded_interfaces_are_expanded(self):
+        reader = _iface("Reader", ("Read", "(p []byte) (int, error)"))
+        read_closer = _iface("ReadCloser", ("Close", "() error"), embeds=["Reader"])
+        _link(("a.go", _entry("p", [
+            reader, read_closer, _struct("File"),
+            _method("Read", "File", "(buf []byte) (n int, err error)"),
+            _method("Close", "File", "() error"),
+        ])))
+
+        assert read_closer.implemented_by == ["File"]
+        assert reader.implemented_by == ["File"]
+
+    def test_cross_package_implementation(self):
+        iface = _iface("Store", ("Get", "(id int) *Item"))
+        impl = _struct("MemStore")
+        _link(
+            ("store/store.go", _entry("store", [iface, _struct("Item")])),
+            ("mem/mem.go", _entry("mem", [impl, _method("Get", "*MemStore", "(id int) *store.Item")])),
+        )
+
+        assert iface.implemented_by == ["*mem.MemStore"]
+        assert impl.implements == ["store.Store"]
+
+    def test_embedded_interface_from_another_package(self):
+        read_closer = _iface("ReadCloser", ("Close", "() error"), embeds=["store.Reader"])
+        unknown = _iface("ReadCloser", ("Close", "() error"), embeds=["io.Reader"])
+        _link(
+            ("store/store.go", _entry("store", [_iface("Reader", ("Read", "(p []byte) (int, error)"))])),
+            ("p/p.go", _entry("p", [read_closer, _struct("File"), _method("Close", "*File", "() error")])),
+            ("q/q.go", _entry("q", [unknown, _struct("File"), _method("Close", "*File", "() error")])),
+        )
+
+        # File lacks Read; io.Reader isn't indexed, so its methods are unknown
+        assert read_closer.implemented_by == []
+        assert unknown.implemented_by == []
+
+    def test_long_signatures_after_save_and_load(self, tmp_path):
+        params = [Param(n, "string") for n in ("tenant", "region", "bucket", "prefix", "owner", "token", "cursor")]
+        signature = "(" + ", ".join(str(p) for p in params) + ") ([]*Item, error)"
+
+        def fetch(receiver=None):
+            return Symbol(
+                name="Fetch", type="method", lines=(1, 1), signature=signature, receiver=receiver,
+                params=params, results=[Param(None, "[]*Item"), Param(None, "error")],
+            )
+
+        store = MapStore(tmp_path)
+        store.update_file("p/p.go", "h", "go", 1, [
+            Symbol(name="Fetcher", type="interface", lines=(1, 1), children=[fetch()]),
+            _struct("Client"), fetch("*Client"), _struct("Item"),
+        ], package="p")
+        store.save()
+        loaded = MapStore.load(tmp_path)
+        fetcher, client, method, _ = loaded.get_file("p/p.go").symbols
+
+        assert method.signature.endswith("...")
+        link_implementations(collect_go_packages(loaded.get_all_files()))
+        assert client.implements == ["Fetcher"]
+        assert fetcher.implemented_by == ["*Client"]
+
+    def test_empty_interface_is_skipped(self):
+        empty = _iface("Any")
+        _link(("a.go", _entry("p", [empty, _struct("T")])))
+
+        assert empty.implemented_by == []
+
+
+class TestMethodSet:
+    """Tests for PackageIndex.full_method_set and method_set."""
+
+    def _package(self):
+        files = [("a.go", _entry("p", [
+            _iface("Closer", ("Close", "() error")),
+            _struct("Conn", embeds=["base", "*bytes.Buffer"]),
+            _struct("base", embeds=["Cache"]),
+            _struct("Cache"),
+            _method("Name", "Conn", "() string"),
+            _method("Reset", "*Conn", "()"),
+            _method("Close", "*base", "() error"),
+            _method("Get", "Cache", "(key string) string"),
+        ]))]
+        packages = collect_go_packages(files)
+        return PackageIndex(packages), packages[0]
+
+    def test_value_and_pointer_sets_differ(self):
+        index, package = self._package()
+
+        value = index.full_method_set(package, "Conn")
+        pointer = index.full_method_set(package, "Conn", pointer=True)
+
+        assert (value.type_name, value.names()) == ("Conn", ["Get", "Name"])
+        assert (pointer.type_name, pointer.names()) == ("*Conn", ["Close", "Get", "Name", "Reset"])
+
+    def test_entries_record_origin(self):
+        index, package = self._package()
+
+        methods = index.full_method_set(package, "Conn", pointer=True)
+
+        name, close_, get = methods.get("Name"), methods.get("Close"), methods.get("Get")
+        assert (name.promoted, name.declared_by, name.via) == (False, "Conn", ())
+        assert (close_.declared_by, close_.pointer_receiver, close_.via) == ("base", True, ("base",))
+        assert (get.declared_by, get.via, get.signature) == ("Cache", ("base", "Cache"), "(key string) string")
+        assert methods.unresolved == ["*bytes.Buffer"]
+
+    def test_embedded_pointer_promotes_pointer_methods_to_value(self):
+        files = [("a.go", _entry("p", [
+            _struct("Conn", embeds=["*base"]), _struct("base"), _method("Close", "*base", "() error"),
+        ]))]
+        packages = collect_go_packages(files)
+
+        value = PackageIndex(packages).full_method_set(packages[0], "Conn")
+
+        assert value.names() == ["Close"]
+        assert value.get("Close").via == ("base",)
+
+    def test_interface_with_embedded_interface(self):
+        files = [("a.go", _entry("p", [
+            _iface("Reader", ("Read", "(p []byte) (int, error)")),
+            _iface("ReadCloser", ("Close", "() error"), embeds=["Reader", "io.Writer"]),
+        ]))]
+        packages = collect_go_packages(files)
+
+        methods = PackageIndex(packages).full_method_set(packages[0], "ReadCloser")
+
+        assert [(m.name, m.via) for m in methods.methods] == [("Close", ()), ("Read", ("Reader",))]
+        assert methods.unresolved == ["io.Writer"]
+
+    def test_qualified_embed_resolves_through_imports(self, tmp_path):
+        (tmp_path / "go.mod").write_text("module example.com/app\n")
+        files = [
+            ("a/util/util.go", _entry("util", [_struct("Base"), _method("A", "Base")])),
+            ("b/util/util.go", _entry("util", [_struct("Base"), _method("B", "Base")])),
+            ("svc/svc.go", FileEntry(
+                hash="h", indexed_at="", language="go", lines=1, package="svc",
+                symbols=[_struct("Server", embeds=["util.Base"])],
+                imports=[Import("example.com/app/b/util")],
+            )),
+        ]
+        packages = collect_go_packages(files, tmp_path)
+
+        methods = PackageIndex(packages).full_method_set(packages[-1], "Server")
+
+        assert methods.names() == ["B"]
+
+    def test_from_store(self, tmp_path):
+        store = MapStore(tmp_path)
+        store.update_file("p/a.go", "h", "go", 1, [
+            _struct("Conn", embeds=["base"]), _struct("base"), _method("Close", "*base", "() error"),
+        ], package="p")
+
+        assert store.method_set("p.Conn").names() == []
+        assert store.method_set("p.Conn", pointer=True).names() == ["Close"]
+        assert store.method_set("p.base.Close") is None
+        assert store.method_set("p.missing") is None
+
+
+class TestMissingMethods:
+    """Tests for PackageIndex.missing_methods and MapStore.missing_methods."""
+
+    def _check(self, files, type_name, iface_name, pointer=False, type_package="p"):
+        packages = collect_go_packages(files)
+        by_name = {p.name: p for p in packages}
+        return PackageIndex(packages).missing_methods(
+            by_name[type_package], type_name, by_name["p"], iface_name, pointer
+        )
+
+    def test_implementing_type_has_no_diffs(self):
+        files = [("a.go", _entry("p", [
+            _iface("Closer", ("Close", "() error")), _struct("F"), _method("Close", "F", "() error"),
+        ]))]
+
+        assert self._check(files, "F", "Closer") == []
+
+    def test_missing_and_case_near_miss(self):
+        files = [("a.go", _entry("p", [
+            _iface("RW", ("Read", "() error"), ("Write", "() error")),
+            _struct("F", embeds=["io.Reader"]), _method("write", "F", "() error"),
+        ]))]
+
+        read, write = self._check(files, "F", "RW")
+
+        assert (read.kind, read.got, read.candidate) == ("missing", None, None)
+        assert read.detail == "F has no method Read; it may be promoted from io.Reader, which isn't indexed"
+        assert (write.kind, write.candidate) == ("missing", "write")
+        assert write.detail == "F has no method Write; it has write, which differs in case"
+
+    def test_signature_mismatch(self):
+        files = [("a.go", _entry("p", [
+            _iface("Store", ("Get", "(id int) (string, error)"), ("Put", "(id int) error"), ("Len", "() int")),
+            _struct("M"),
+            _method("Get", "M", "(key string) string"),
+            _method("Put", "M", "(key int)"),
+            _method("Len", "M", "() int"),
+        ]))]
+
+        get, put = self._check(files, "M", "Store")
+
+        assert (get.name, get.kind, get.got, get.want) == ("Get", "signature", "(key string) string", "(id int) (string, error)")
+        assert get.detail == "Get(key string) string has the wrong parameter and result types; want Get(id int) (string, error)"
+        assert (put.name, put.kind) == ("Put", "signature")
+        assert "the wrong result types" in put.detail
+
+    def test_pointer_receiver(self):
+        files = [("a.go", _entry("p", [
+            _iface("Closer", ("Close", "() error")), _struct("F"), _method("Close", "*F", "() error"),
+        ]))]
+
+        (diff,) = self._check(files, "F", "Closer")
+
+        assert (diff.kind, diff.declared_by) == ("pointer_receiver", "*F")
+        assert diff.detail == "Close has a pointer receiver, so it is in the method set of *F, not F"
+        assert self._check(files, "F", "Closer", pointer=True) == []
+
+    def test_promoted_methods_count(self):
+        files = [("a.go", _entry("p", [
+            _iface("Closer", ("Close", "() error")),
+            _struct("Conn", embeds=["base"]), _struct("base"), _method("Close", "base", "() error"),
+        ]))]
+
+        assert self._check(files, "Conn", "Closer") == []
+
+    def test_unexported_method_of_another_package(self):
+        files = [
+            ("q/a.go", _entry("q", [_struct("F"), _method("close", "F", "()")])),
+            ("p/a.go", _entry("p", [_iface("closer", ("close", "()"))])),
+        ]
+
+        (diff,) = self._check(files, "F", "closer", type_package="q")
+
+        assert (diff.kind, diff.got) == ("unexported", "()")
+        assert diff.detail == "close is unexported, so only types in package p can implement it"
+
+    def test_truncated_signature(self):
+        files = [("a.go", _entry("p", [
+            _iface("I", ("Do", "(a int, b int, ...")), _struct("F"), _method("Do", "F", "(a int, b int, ..."),
+        ]))]
+
+        (diff,) = self._check(files, "F", "I")
+
+        assert diff.kind == "uncomparable"
+
+    def test_unresolved_embedded_interface(self):
+        files = [("a.go", _entry("p", [
+            _iface("ReadCloser", ("Close", "() error"), embeds=["io.Reader"]), _struct("F"), _method("Close", "F", "() error"),
+        ]))]
+
+        (diff,) = self._check(files, "F", "ReadCloser")
+
+        assert (diff.name, diff.kind) == ("io.Reader", "uncomparable")
+        assert diff.detail == "ReadCloser embeds io.Reader, which isn't indexed, so its methods can't be checked"
+
+    def test_from_store(self, tmp_path):
+        store = MapStore(tmp_path)
+        store.update_file("p/a.go", "h", "go", 1, [
+            _iface("Closer", ("Close", "() error")), _struct("F"), _method("Close", "*F", "() error"),
+        ], package="p")
+
+        assert [d.kind for d in store.missing_methods("p.F", "p.Closer")] == ["pointer_receiver"]
+        assert store.missing_methods("*p.F", "p.Closer") == []
+        assert store.missing_methods("p.Closer", "p.F") is None
+        assert store.missing_methods("p.F", "p.missing") is None
@@ -1,11 +1,15 @@
 """Tests for the indexer module."""
 
+import ast
 import json
 import pytest
 from pathlib import Path
 
-from codemap.core.indexer import Indexer
+from codemap.core.indexer import FileError, Indexer
 from codemap.core.map_store import MapStore
+from codemap.utils.config import Config
+
+FIXTURES = Path(__file__).parent / "fixtures"
 
 
 class TestIndexer:
@@ -259,6 +263,39 @@ def broken(
         assert valid_entry is not None
         assert len(valid_entry.symbols) == 1
 
+        # The error is recorded on the file entry
+        assert broken_entry.error
+        assert valid_entry.error is None
+
+
+    def test_collects_file_errors_and_keeps_the_rest(self, tmp_path: Path, monkeypatch):
+        (tmp_path / "broken.py").write_text("def broken(\n")
+        (tmp_path / "crash.py").write_text("def crash(): pass\n")
+        (tmp_path / "valid.py").write_text("def valid(): pass\n")
+
+        indexer = Indexer(root=tmp_path)
+        real_parse = indexer._parsers["python"].parse_file
+
+        def parse_file(content, filepath=None):
+            if filepath and filepath.endswith("crash.py"):
+                raise RuntimeError("parser crashed")
+            return real_parse(content, filepath)
+
+        monkeypatch.setattr(indexer._parsers["python"], "parse_file", parse_file)
+        result = indexer.index_all()
+
+        errors = result["errors"]
+        assert [(e.path, e.partial) for e in errors] == [("broken.py", True), ("crash.py", False)]
+        assert all(isinstance(e, FileError) for e in errors)
+        assert errors[1].error == "parser crashed"
+        assert errors[0].error == indexer.map_store.get_file("broken.py").error
+        assert result["total_files"] == 2
+        assert sorted(path for path, _ in indexer.map_store.get_all_files()) == ["broken.py", "valid.py"]
+
+    def test_clean_run_has_no_errors(self, tmp_path: Path):
+        (tmp_path / "valid.py").write_text("def valid(): pass\n")
+
+        assert Indexer(root=tmp_path).index_all()["errors"] == []
     def test_handles_encoding_error(self, tmp_path: Path):
         # Create a file with invalid UTF-8
         test_file = tmp_path / "binary.py"
@@ -304,3 +341,252 @@ def broken(
         # Should only have file2.py
         assert len(files) == 1
         assert files[0][0] == "file2.py"
+
+    def test_parallel_index_matches_serial(self, tmp_path: Path, monkeypatch):
+        """Parsing in a worker pool stores the same index as the serial path."""
+        for i in range(12):
+            pkg = tmp_path / f"pkg{i % 3}"
+            pkg.mkdir(exist_ok=True)
+            (pkg / f"mod{i}.py").write_text(f"class C{i}:\n    def m(self): pass\n\ndef f{i}(): pass\n")
+        (tmp_path / "broken.py").write_text("def broken(:\n")
+
+        def snapshot():
+            store = MapStore.load(tmp_path)
+            return [
+                (path, {k: v for k, v in entry.to_dict().items() if k != "indexed_at"})
+                for path, entry in store.get_all_files()
+            ]
+
+        serial = Indexer(root=tmp_path)
+        serial.config.workers = 1
+        serial_result = serial.index_all()
+        expected = snapshot()
+
+        monkeypatch.setattr("codemap.core.indexer.PARALLEL_MIN_FILES", 0)
+        parallel = Indexer(root=tmp_path)
+        parallel.config.workers = 2
+        parallel_result = parallel.index_all()
+
+        assert parallel_result["total_files"] == serial_result["total_files"] == 13
+        assert parallel_result["total_symbols"] == serial_result["total_symbols"]
+        assert snapshot() == expected
+        assert MapStore.load(tmp_path).get_file("broken.py").error
+
+    def test_build_constraints_exclude_go_files(self, tmp_path: Path):
+        pytest.importorskip("tree_sitter_go")
+        (tmp_path / "main.go").write_text("package main\n\nfunc Shared() {}\n")
+        (tmp_path / "os_windows.go").write_text("package main\n\nfunc open() {}\n")
+        (tmp_path / "os_other.go").write_text("//go:build !windows\n\npackage main\n\nfunc open() {}\n")
+
+        result = Indexer(root=tmp_path, config=Config(goos="linux", goarch="amd64")).index_all()
+
+        store = MapStore.load(tmp_path)
+        assert sorted(path for path, _ in store.get_all_files()) == ["main.go", "os_other.go"]
+        assert result["skipped"] == [("os_windows.go", "GOOS/GOARCH suffix _windows")]
+
+    def test_update_file_removes_newly_excluded_file(self, tmp_path: Path):
+        pytest.importorskip("tree_sitter_go")
+        (tmp_path / "main.go").write_text("package main\n\nfunc Main() {}\n")
+        indexer = Indexer(root=tmp_path)
+        indexer.index_all()
+
+        (tmp_path / "main.go").write_text("//go:build ignore\n\npackage main\n\nfunc Main() {}\n")
+        result = indexer.update_file(tmp_path / "main.go")
+
+        assert result["removed"] is True
+        assert result["skipped"] == "build constraint: ignore"
+        assert MapStore.load(tmp_path).get_file("main.go") is None
+
+    def test_generated_files(self, tmp_path: Path):
+        pytest.importorskip("tree_sitter_go")
+        (tmp_path / "main.go").write_text("package main\n\nfunc Main() {}\n")
+        (tmp_path / "user.pb.go").write_text((FIXTURES / "generated.pb.go").read_text())
+
+        Indexer(root=tmp_path).index_all()
+        entry = MapStore.load(tmp_path).get_file("user.pb.go")
+        assert [(s.name, s.generated) for s in entry.symbols] == [("User", True), ("GetName", True)]
+
+        result = Indexer(root=tmp_path, config=Config(exclude_generated=True)).index_all()
+        store = MapStore.load(tmp_path)
+        assert [path for path, _ in store.get_all_files()] == ["main.go"]
+        assert result["skipped"] == [("user.pb.go", "generated file")]
+
+    def test_methods_attached_to_types_across_files(self, tmp_path: Path):
+        pytest.importorskip("tree_sitter_go")
+        import shutil
+        shutil.copytree(FIXTURES / "split_package", tmp_path / "split")
+
+        indexer = Indexer(root=tmp_path)
+        indexer.index_all()
+        store = MapStore.load(tmp_path)
+        types = {s.name: s for s in store.get_file("split/service.go").symbols if s.type == "struct"}
+        assert types["DefaultService"].methods == ["GetUser", "CreateUser", "String"]
+        assert types["Cache"].methods == ["Get", "Len"]
+
+        # Moving a method out of the package detaches it on update
+        methods_file = tmp_path / "split" / "service_methods.go"
+        methods_file.write_text(methods_file.read_text().replace("func (c Cache[K, V]) Len", "func Len"))
+        indexer.update_file(methods_file)
+        cache = next(s for s in MapStore.load(tmp_path).get_file("split/service.go").symbols if s.name == "Cache")
+        assert cache.methods == ["Get"]
+
+    def test_max_depth_limits_packages(self, tmp_path: Path):
+        pytest.importorskip("tree_sitter_go")
+        from codemap.analysis import collect_go_packages
+
+        for directory, package in [
+            (".", "main"), ("cmd/server", "main"), ("internal", "internal"),
+            ("internal/store", "store"), ("internal/store/sqlite", "sqlite"),
+        ]:
+            (tmp_path / directory).mkdir(parents=True, exist_ok=True)
+            (tmp_path / directory / "x.go").write_text(f"package {package}\n\nfunc F() {{}}\n")
+
+        def packages(depth: int) -> list[str]:
+            Indexer(root=tmp_path, config=Config(languages=["go"]), max_depth=depth).index_all()
+            return [p.directory for p in collect_go_packages(MapStore.load(tmp_path).get_all_files())]
+
+        assert packages(1) == [".", "internal"]
+        assert packages(2) == [".", "cmd/server", "internal", "internal/store"]
+
+    def test_notes_are_recorded_when_enabled(self, tmp_path: Path):
+        pytest.importorskip("tree_sitter_go")
+        (tmp_path / "main.go").write_text("package main\n\n// TODO(ann): rename\nfunc Main() {}\n")
+
+        Indexer(root=tmp_path).index_all()
+        assert MapStore.load(tmp_path).notes() == []
+
+        Indexer(root=tmp_path, config=Config(notes=True)).index_all()
+        [(path, note)] = MapStore.load(tmp_path).notes()
+        assert (path, note.kind, note.author, note.text, note.line) == ("main.go", "TODO", "ann", "rename", 3)
+
+
+class _Recorder:
+    """Visitor that records the order it sees symbols in and tags each one."""
+
+    def __init__(self, tag: str, seen: list):
+        self.tag = tag
+        self.seen = seen
+
+    def visit_symbol(self, symbol, file):
+        self.seen.append((self.tag, file, symbol.name))
+        symbol.metadata[self.tag] = file
+
+
+class TestVisitors:
+    """Tests for symbol visitors."""
+
+    def _tree(self, root: Path) -> None:
+        (root / "pkg").mkdir()
+        (root / "pkg" / "a.py").write_text("class A:\n    def run(self):\n        pass\n\ndef f():\n    pass\n")
+        (root / "b.py").write_text("def g():\n    pass\n")
+
+    def test_order_and_metadata(self, tmp_path: Path):
+        self._tree(tmp_path)
+        seen: list = []
+
+        Indexer(tmp_path, config=Config(), visitors=[_Recorder("x", seen), _Recorder("y", seen)]).index_all()
+
+        assert seen == [
+            ("x", "b.py", "g"), ("y", "b.py", "g"),
+            ("x", "pkg/a.py", "A"), ("y", "pkg/a.py", "A"),
+            ("x", "pkg/a.py", "run"), ("y", "pkg/a.py", "run"),
+            ("x", "pkg/a.py", "f"), ("y", "pkg/a.py", "f"),
+        ]
+        cls = MapStore.load(tmp_path).get_file("pkg/a.py").symbols[0]
+        assert cls.metadata == {"x": "pkg/a.py", "y": "pkg/a.py"}
+        assert cls.children[0].metadata == {"x": "pkg/a.py", "y": "pkg/a.py"}
+
+    def test_parallel_parsing_keeps_order(self, tmp_path: Path, monkeypatch):
+        for i in range(12):
+            (tmp_path / f"mod{i:02}.py").write_text(f"def f{i}(): pass\n")
+        serial: list = []
+        Indexer(tmp_path, config=Config(), visitors=[_Recorder("x", serial)]).index_all()
+
+        monkeypatch.setattr("codemap.core.indexer.PARALLEL_MIN_FILES", 0)
+        monkeypatch.setattr("codemap.core.indexer.PARSE_CHUNK_SIZE", 2)
+        parallel: list = []
+        Indexer(tmp_path, config=Config(workers=3), visitors=[_Recorder("x", parallel)]).index_all()
+
+        assert parallel == serial
+
+    def test_metadata_is_not_cached(self, tmp_path: Path):
+        self._tree(tmp_path)
+        config = Config(cache_dir=".cache")
+        Indexer(tmp_path, config=config, visitors=[_Recorder("x", [])]).index_all()
+
+        Indexer(tmp_path, config=Config(cache_dir=".cache")).index_all()
+
+        assert MapStore.load(tmp_path).get_file("b.py").symbols[0].metadata == {}
+
+    def test_update_file_visits(self, tmp_path: Path):
+        self._tree(tmp_path)
+        indexer = Indexer(tmp_path, config=Config())
+        indexer.index_all()
+        seen: list = []
+        indexer.visitors.append(_Recorder("x", seen))
+
+        indexer.update_file(tmp_path / "b.py")
+
+        assert seen == [("x", "b.py", "g")]
+
+
+class TestRetainAST:
+    """Tests for syntax trees kept with retain_ast."""
+
+    def test_off_by_default(self, tmp_path: Path):
+        (tmp_path / "a.py").write_text("def f():\n    pass\n")
+        indexer = Indexer(tmp_path, config=Config())
+        indexer.index_all()
+        assert indexer.map_store.syntax_tree("a.py") is None
+        assert list(indexer.map_store.syntax_trees()) == []
+
+    def test_trees_of_indexed_files(self, tmp_path: Path):
+        (tmp_path / "pkg").mkdir()
+        (tmp_path / "pkg" / "a.py").write_text("def f():\n    return g()\n")
+        (tmp_path / "README.md").write_text("# Title\n")
+        indexer = Indexer(tmp_path, config=Config(), retain_ast=True)
+        indexer.index_all()
+
+        tree = indexer.map_store.syntax_tree("pkg/a.py")
+        assert (tree.path, tree.language) == ("pkg/a.py", "python")
+        [call] = [n for n in tree.walk() if isinstance(n, ast.Call)]
+        assert (str(tree.position(call)), tree.text(call)) == ("pkg/a.py:2:12", "g()")
+        # Markdown has no tree to offer; trees aren't saved
+        assert [t.path for t in indexer.map_store.syntax_trees()] == ["pkg/a.py"]
+        assert MapStore.load(tmp_path).syntax_tree("pkg/a.py") is None
+
+    def test_parallel_parsing_and_cache(self, tmp_path: Path, monkeypatch):
+        for i in range(6):
+            (tmp_path / f"mod{i}.py").write_text(f"def f{i}(): pass\n")
+        monkeypatch.setattr("codemap.core.indexer.PARALLEL_MIN_FILES", 0)
+        for _ in range(2):  # The second run is served from the cache
+            indexer = Indexer(tmp_path, config=Config(workers=2, cache_dir=".cache"), retain_ast=True)
+            indexer.index_all()
+            assert [t.path for t in indexer.map_store.syntax_trees()] == [f"mod{i}.py" for i in range(6)]
+
+    def test_update_and_remove(self, tmp_path: Path):
+        path = tmp_path / "a.py"
+        path.write_text("def f():\n    pass\n")
+        indexer = Indexer(tmp_path, config=Config(), retain_ast=True)
+        indexer.index_all()
+        copy = indexer.map_store.copy()
+        assert copy.syntax_tree("a.py") is indexer.map_store.syntax_tree("a.py")
+
+        path.write_text("def g():\n    pass\n")
+        indexer.update_file(path)
+        tree = indexer.map_store.syntax_tree("a.py")
+        assert tree.text(tree.root.body[0]).startswith("def g")
+
+        path.unlink()
+        indexer.update_file(path)
+        assert indexer.map_store.syntax_tree("a.py") is None
+
+    def test_go_tree(self, tmp_path: Path):
+        pytest.importorskip("tree_sitter_go")
+        (tmp_path / "main.go").write_text("package main\n\nfunc main() {\n\tgo run()\n}\n")
+        indexer = Indexer(tmp_path, config=Config(), retain_ast=True)
+        indexer.index_all()
+
+        tree = indexer.map_store.syntax_tree("main.go")
+        [statement] = [n for n in tree.walk() if n.type == "go_statement"]
+        assert (str(tree.position(statement)), tree.text(statement)) == ("main.go:4:2", "go run()")
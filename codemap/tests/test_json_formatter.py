@@ -0,0 +1,339 @@
+"""Tests for the JSON export formatter."""
+
+import json
+import re
+import shutil
+from pathlib import Path
+
+import pytest
+
+from codemap.core.hasher import hash_file
+from codemap.core.map_store import MapStore
+from codemap.formatters.json_formatter import SCHEMA_VERSION, build_document, format_json
+from codemap.parsers.base import Symbol
+
+FIXTURES = Path(__file__).parent / "fixtures"
+
+
+class _AnyHash:
+    """Matches any symbol content hash."""
+
+    def __eq__(self, other):
+        return isinstance(other, str) and re.fullmatch(r"[0-9a-f]{12}", other) is not None
+
+
+def _pos(line, column):
+    return {"file": "sample_module.go", "line": line, "column": column}
+
+
+def _field(name, type, line, columns):
+    """Build an expected struct field for the Go fixture."""
+    return {
+        "name": name, "type": type, "resolved_type": None, "tag": None, "embedded": False,
+        "pos": _pos(line, columns[0]), "end": _pos(line, columns[1]),
+        "deprecated": False, "deprecation": None,
+    }
+
+
+def _param(name, type):
+    return {"name": name, "type": type, "resolved_type": None, "variadic": False}
+
+
+def _symbol(
+    name, type, lines, signature=None, docstring=None, receiver=None, children=None,
+    implements=None, implemented_by=None, fields=None, columns=(1, 2), params=None, results=None,
+    complexity=None, methods=None, id=None, body_lines=None,
+):
+    """Build an expected exported symbol for the Go fixture."""
+    return {
+        "id": id or f"sample.{receiver.lstrip('*') + '.' if receiver else ''}{name}",
+        "hash": _AnyHash(),
+        "name": name,
+        "type": type,
+        "file": "sample_module.go",
+        "lines": lines,
+        "pos": _pos(lines[0], columns[0]),
+        "end": _pos(lines[1], columns[1]),
+        "signature": signature,
+        "docstring": docstring,
+        "exported": True,
+        "receiver": receiver,
+        "embeds": [],
+        "resolved_embeds": [],
+        "method_set": None,
+        "implements": implements or [],
+        "implemented_by": implemented_by or [],
+        "methods": methods or [],
+        "type_params": [],
+        "params": params or [],
+        "results": results or [],
+        "fields": fields or [],
+        "value": None,
+        "int_value": None,
+        "enum": False,
+        "enum_values": [],
+        "group": None,
+        "is_alias": False,
+        "in_test": False,
+        "generated": False,
+        "deprecated": False,
+        "deprecation": None,
+        "complexity": complexity,
+        "body_lines": body_lines,
+        "is_stub": False,
+        "metadata": {},
+        "children": children or [],
+    }
+
+
+class TestJsonFormatter:
+    """Tests for build_document and format_json."""
+
+    def test_document_has_version(self, tmp_path: Path):
+        store = MapStore(tmp_path)
+        store.set_metadata(str(tmp_path), {})
+
+        doc = build_document(store)
+
+        assert doc["version"] == SCHEMA_VERSION
+        assert doc["root"] == str(tmp_path)
+        assert doc["packages"] == []
+
+    def test_symbols_have_stable_keys(self, tmp_path: Path):
+        store = MapStore(tmp_path)
+        store.update_file(
+            rel_path="src/app.py",
+            hash="abc123def456",
+            language="python",
+            lines=10,
+            symbols=[Symbol(name="main", type="function", lines=(1, 5))],
+        )
+
+        doc = build_document(store)
+
+        package = doc["packages"][0]
+        assert package["name"] is None
+        assert package["path"] == "src"
+        assert package["symbols"] == [{
+            "id": None,
+            "hash": Symbol(name="main", type="function", lines=(1, 5)).content_hash(),
+            "name": "main",
+            "type": "function",
+            "file": "src/app.py",
+            "lines": [1, 5],
+            "pos": {"file": "src/app.py", "line": 1, "column": 1},
+            "end": None,
+            "signature": None,
+            "docstring": None,
+            "exported": None,
+            "receiver": None,
+            "embeds": [],
+            "resolved_embeds": [],
+            "method_set": None,
+            "implements": [],
+            "implemented_by": [],
+            "methods": [],
+            "type_params": [],
+            "params": [],
+            "results": [],
+            "fields": [],
+            "value": None,
+            "int_value": None,
+            "enum": False,
+            "enum_values": [],
+            "group": None,
+            "is_alias": False,
+            "in_test": False,
+            "generated": False,
+            "deprecated": False,
+            "deprecation": None,
+            "complexity": None,
+            "body_lines": None,
+            "is_stub": False,
+            "metadata": {},
+            "children": [],
+        }]
+
+    def test_interface_method_sets(self, tmp_path: Path):
+        pytest.importorskip("tree_sitter_go")
+        from codemap.core.indexer import Indexer
+
+        (tmp_path / "go.mod").write_text("module example.com/app\n")
+        (tmp_path / "store").mkdir()
+        (tmp_path / "store" / "store.go").write_text(
+            "package store\n\ntype Getter interface {\n\tGet(key string) string\n}\n"
+        )
+        (tmp_path / "main.go").write_text(
+            "package main\n\n"
+            'import (\n\t"io"\n\tdb "example.com/app/store"\n)\n\n'
+            "type Store interface {\n\tio.Closer\n\tdb.Getter\n\tFlush() error\n}\n"
+        )
+        Indexer(root=tmp_path, languages=["go"]).index_all()
+        store = MapStore.load(tmp_path)
+
+        def interface(doc):
+            return next(s for p in doc["packages"] for s in p["symbols"] if s["name"] == "Store")
+
+        assert interface(build_document(store))["method_set"] is None
+        symbol = interface(build_document(store, method_sets=True))
+        assert symbol["resolved_embeds"] == [None, "example.com/app/store.Getter"]
+        assert symbol["method_set"] == {
+            "methods": [
+                {"name": "Flush", "signature": "() error", "declared_by": "Store", "package": ".",
+                 "via": [], "promoted": False},
+                {"name": "Get", "signature": "(key string) string", "declared_by": "Getter", "package": "store",
+                 "via": ["Getter"], "promoted": True},
+            ],
+            "unresolved": ["io.Closer"],
+        }
+
+    def test_package_doc_merged_across_files(self, tmp_path: Path):
+        store = MapStore(tmp_path)
+        store.update_file("svc/a.go", "a", "go", 3, [], package="svc", package_doc="Extra notes on svc.")
+        store.update_file("svc/doc.go", "b", "go", 3, [], package="svc", package_doc="Package svc serves users.")
+        store.update_file("svc/z.go", "c", "go", 3, [], package="svc", package_doc="Package svc serves users.")
+        store.update_file("svc/svc_test.go", "d", "go", 3, [], package="svc_test")
+
+        packages = build_document(store)["packages"]
+
+        assert [(p["name"], p["doc"]) for p in packages] == [
+            ("svc", "Package svc serves users.\n\nExtra notes on svc."),
+            ("svc_test", None),
+        ]
+        assert packages[0]["files"][0]["package_doc"] == "Extra notes on svc."
+
+    def test_import_paths_from_nearest_go_mod(self, tmp_path: Path):
+        (tmp_path / "go.mod").write_text("module github.com/me/proj\n")
+        (tmp_path / "tools" / "gen").mkdir(parents=True)
+        (tmp_path / "tools" / "go.mod").write_text("module github.com/me/proj/tools\n")
+        store = MapStore(tmp_path)
+        store.update_file("internal/util/util.go", "a", "go", 3, [], package="util")
+        store.update_file("tools/gen/util/util.go", "b", "go", 3, [], package="util")
+        store.update_file("scripts/run.py", "c", "python", 3, [])
+
+        packages = build_document(store)["packages"]
+
+        assert [(p["path"], p["import_path"], p["import_path_resolved"]) for p in packages] == [
+            ("internal/util", "github.com/me/proj/internal/util", True),
+            ("scripts", None, False),
+            ("tools/gen/util", "github.com/me/proj/tools/gen/util", True),
+        ]
+
+    def test_import_path_falls_back_to_directory(self, tmp_path: Path):
+        store = MapStore(tmp_path)
+        store.update_file("internal/util/util.go", "a", "go", 3, [], package="util")
+
+        package = build_document(store)["packages"][0]
+
+        assert (package["import_path"], package["import_path_resolved"]) == ("internal/util", False)
+
+    def test_format_json_is_valid(self, tmp_path: Path):
+        store = MapStore(tmp_path)
+        store.update_file("a.py", "abc", "python", 1, [Symbol(name="f", type="function", lines=(1, 1))])
+
+        data = json.loads(format_json(store))
+
+        assert data["version"] == SCHEMA_VERSION
+        assert data["packages"][0]["path"] == "."
+
+    def test_methods_declared_in_other_files(self, tmp_path: Path):
+        pytest.importorskip("tree_sitter_go")
+        from codemap.core.indexer import Indexer
+
+        shutil.copytree(FIXTURES / "split_package", tmp_path / "split")
+        Indexer(root=tmp_path, languages=["go"]).index_all()
+
+        [package] = build_document(MapStore.load(tmp_path))["packages"]
+
+        symbols = {(s["name"], s["receiver"]): s for s in package["symbols"]}
+        assert symbols[("DefaultService", None)]["methods"] == ["GetUser", "CreateUser", "String"]
+        assert symbols[("Cache", None)]["methods"] == ["Get", "Len"]
+        assert symbols[("Get", "*Cache[K, V]")]["file"] == "split/service_methods.go"
+        assert symbols[("Get", "*Cache[K, V]")]["methods"] == []
+
+    def test_go_fixture_round_trip(self, tmp_path: Path):
+        """The Go fixture exports to an exact, documented structure."""
+        pytest.importorskip("tree_sitter_go")
+        from codemap.core.indexer import Indexer
+
+        shutil.copy(FIXTURES / "sample_module.go", tmp_path / "sample_module.go")
+        Indexer(root=tmp_path, languages=["go"]).index_all()
+
+        doc = json.loads(format_json(MapStore.load(tmp_path)))
+
+        assert doc["version"] == SCHEMA_VERSION
+        assert doc["packages"] == [{
+            "name": "sample",
+            "path": ".",
+            "import_path": ".",
+            "import_path_resolved": False,
+            "external_test": False,
+            "opaque": False,
+            "doc": "Package sample provides sample Go code for testing.",
+            "files": [{
+                "path": "sample_module.go",
+                "language": "go",
+                "hash": hash_file(tmp_path / "sample_module.go"),
+                "lines": 46,
+                "package_doc": "Package sample provides sample Go code for testing.",
+                "imports": [{"path": "fmt", "name": None}],
+                "collapsed": None,
+                "notes": [],
+            }],
+            "symbols": [
+                _symbol(
+                    "User", "struct", [7, 10],
+                    docstring="User represents a user in the system.",
+                    fields=[
+                        _field("ID", "int", 8, (2, 10)),
+                        _field("Name", "string", 9, (2, 13)),
+                    ],
+                ),
+                _symbol(
+                    "UserService", "interface", [13, 16],
+                    docstring="UserService handles user operations.",
+                    implemented_by=["*DefaultService"],
+                    children=[
+                        _symbol(
+                            "GetUser", "method", [14, 14], "(id int) (*User, error)", columns=(2, 32),
+                            id="sample.UserService.GetUser",
+                            params=[_param("id", "int")], results=[_param(None, "*User"), _param(None, "error")],
+                        ),
+                        _symbol(
+                            "CreateUser", "method", [15, 15], "(name string) (*User, error)", columns=(2, 40),
+                            id="sample.UserService.CreateUser",
+                            params=[_param("name", "string")], results=[_param(None, "*User"), _param(None, "error")],
+                        ),
+                    ],
+                ),
+                _symbol(
+                    "DefaultService", "struct", [19, 21],
+                    docstring="DefaultService is the default implementation.",
+                    implements=["UserService"],
+                    methods=["GetUser", "CreateUser"],
+                    fields=[_field("users", "map[int]*User", 20, (2, 21))],
+                ),
+                _symbol(
+                    "GetUser", "method", [24, 29], "(id int) (*User, error)",
+                    "GetUser retrieves a user by ID.", receiver="*DefaultService",
+                    params=[_param("id", "int")], results=[_param(None, "*User"), _param(None, "error")],
+                    complexity=2, body_lines=4,
+                ),
+                _symbol(
+                    "CreateUser", "method", [32, 36], "(name string) (*User, error)",
+                    "CreateUser creates a new user.", receiver="*DefaultService",
+                    params=[_param("name", "string")], results=[_param(None, "*User"), _param(None, "error")],
+                    complexity=1, body_lines=3,
+                ),
+                _symbol(
+                    "Greet", "function", [39, 41], "(name string) string", "Helper function for greeting.",
+                    params=[_param("name", "string")], results=[_param(None, "string")], complexity=1, body_lines=1,
+                ),
+                _symbol(
+                    "Process", "function", [44, 46], "(data []byte) ([]byte, error)",
+                    "Process handles async-like operations.",
+                    params=[_param("data", "[]byte")], results=[_param(None, "[]byte"), _param(None, "error")],
+                    complexity=1, body_lines=1,
+                ),
+            ],
+        }]
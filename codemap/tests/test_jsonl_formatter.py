@@ -0,0 +1,94 @@
+"""Tests for the JSON Lines export and streaming."""
+
+import io
+import json
+from pathlib import Path
+
+from codemap.core.indexer import Indexer
+from codemap.core.map_store import MapStore
+from codemap.formatters.json_formatter import SCHEMA_VERSION
+from codemap.formatters.jsonl_formatter import format_jsonl, stream_jsonl
+from codemap.utils.config import Config
+
+
+def _tree(root: Path) -> None:
+    (root / "app").mkdir()
+    (root / "app" / "main.py").write_text('def main():\n    """Entry point."""\n\nclass App:\n    def run(self): pass\n')
+    (root / "app" / "util.py").write_text("def helper(): pass\n")
+    (root / "broken.py").write_text("def broken(:\n")
+
+
+def _stream(root: Path, **kwargs) -> tuple[list[dict], dict]:
+    out = io.StringIO()
+    result = stream_jsonl(out, root, Config(), **kwargs)
+    return [json.loads(line) for line in out.getvalue().splitlines()], result
+
+
+class TestStreamJsonl:
+    def test_lines_are_self_contained(self, tmp_path: Path):
+        _tree(tmp_path)
+
+        records, result = _stream(tmp_path)
+
+        assert [(r["kind"], r.get("name", r.get("path"))) for r in records] == [
+            ("file", "app/main.py"), ("symbol", "main"), ("symbol", "App"),
+            ("file", "app/util.py"), ("symbol", "helper"),
+            ("file", "broken.py"),
+        ]
+        app = records[2]
+        assert (app["version"], app["package_path"], app["file"]) == (SCHEMA_VERSION, "app", "app/main.py")
+        assert [c["name"] for c in app["children"]] == ["run"]
+        assert records[0]["error"] is None and records[5]["error"]
+        assert (result["total_files"], result["total_symbols"]) == (3, 3)
+        assert not (tmp_path / ".codemap").exists()
+
+    def test_per_file(self, tmp_path: Path):
+        _tree(tmp_path)
+
+        records, _ = _stream(tmp_path, per_file=True)
+
+        assert [r["kind"] for r in records] == ["file", "file", "file"]
+        assert [s["name"] for s in records[0]["symbols"]] == ["main", "App"]
+
+    def test_visitors_set_metadata(self, tmp_path: Path):
+        _tree(tmp_path)
+
+        class Owner:
+            def visit_symbol(self, symbol, file):
+                symbol.metadata["owner"] = "@app-team" if file.startswith("app/") else None
+
+        records, _ = _stream(tmp_path, visitors=[Owner()])
+
+        symbols = [r for r in records if r["kind"] == "symbol"]
+        assert {s["metadata"]["owner"] for s in symbols} == {"@app-team"}
+        assert symbols[1]["children"][0]["metadata"] == {"owner": "@app-team"}
+
+    def test_parallel_output_matches_serial(self, tmp_path: Path, monkeypatch):
+        for i in range(40):
+            (tmp_path / f"mod{i:02}.py").write_text(f"def f{i}(): pass\n")
+        serial, _ = _stream(tmp_path)
+
+        monkeypatch.setattr("codemap.core.indexer.PARALLEL_MIN_FILES", 0)
+        monkeypatch.setattr("codemap.core.indexer.PARSE_CHUNK_SIZE", 3)
+        out = io.StringIO()
+        stream_jsonl(out, tmp_path, Config(workers=2))
+
+        assert [json.loads(line) for line in out.getvalue().splitlines()] == serial
+
+    def test_matches_export_of_index(self, tmp_path: Path):
+        _tree(tmp_path)
+        Indexer(root=tmp_path, config=Config()).index_all()
+        exported = [json.loads(line) for line in format_jsonl(MapStore.load(tmp_path)).splitlines()]
+
+        records, _ = _stream(tmp_path)
+
+        assert records == exported
+
+    def test_go_import_path(self, tmp_path: Path):
+        (tmp_path / "go.mod").write_text("module example.com/app\n")
+        store = MapStore(tmp_path)
+        store.update_file("api/api.go", "a", "go", 3, [], package="api")
+
+        record = json.loads(format_jsonl(store))
+
+        assert (record["import_path"], record["import_path_resolved"]) == ("example.com/app/api", True)
@@ -5,7 +5,7 @@ import pytest
 from pathlib import Path
 
 from codemap.core.map_store import MapStore, RootManifest, DirectoryMap, FileEntry
-from codemap.parsers.base import Symbol
+from codemap.parsers.base import Import, Note, Symbol
 
 
 class TestMapStore:
@@ -323,6 +323,43 @@ class TestDirectoryMap:
         assert "Button.py" in restored.files
         assert restored.files["Button.py"].hash == "abc123"
 
+    def test_file_imports_round_trip(self):
+        entry = FileEntry(
+            hash="abc123",
+            indexed_at="2025-01-01T00:00:00Z",
+            language="go",
+            lines=5,
+            symbols=[],
+            package="main",
+            imports=[Import("fmt"), Import("net/http", name="h")],
+        )
+
+        data = entry.to_dict()
+        restored = FileEntry.from_dict(data)
+
+        assert data["imports"] == [{"path": "fmt"}, {"path": "net/http", "name": "h"}]
+        assert restored.imports == entry.imports
+        assert "imports" not in FileEntry("h", "t", "python", 1, []).to_dict()
+
+    def test_file_notes_round_trip(self):
+        entry = FileEntry(
+            hash="abc123",
+            indexed_at="2025-01-01T00:00:00Z",
+            language="go",
+            lines=5,
+            symbols=[],
+            notes=[Note("TODO", "rename", 3, 4, author="ann"), Note("FIXME", "", 5, 2)],
+        )
+
+        data = entry.to_dict()
+
+        assert data["notes"] == [
+            {"kind": "TODO", "text": "rename", "line": 3, "column": 4, "author": "ann"},
+            {"kind": "FIXME", "text": "", "line": 5, "column": 2},
+        ]
+        assert FileEntry.from_dict(data).notes == entry.notes
+        assert "notes" not in FileEntry("h", "t", "go", 1, []).to_dict()
+
 
 class TestSymbol:
     """Tests for Symbol class."""
@@ -0,0 +1,103 @@
+"""Tests for the Markdown export formatter."""
+
+from pathlib import Path
+
+from codemap.core.map_store import MapStore
+from codemap.formatters.markdown_formatter import format_markdown
+from codemap.parsers.base import Field, Symbol
+
+
+def _go_store(tmp_path: Path) -> MapStore:
+    """A Go package whose methods live in a different file from their type."""
+    store = MapStore(tmp_path)
+    store.update_file("svc/types.go", "a1", "go", 20, [
+        Symbol(
+            name="UserService", type="interface", lines=(3, 6),
+            docstring="UserService handles user operations.",
+            children=[Symbol(name="GetUser", type="method", lines=(4, 4), signature="(id int) (*User, error)")],
+        ),
+        Symbol(
+            name="DefaultService", type="struct", lines=(8, 10),
+            fields=[Field(name="users", type="map[int]*User")],
+        ),
+    ], package="svc")
+    store.update_file("svc/methods.go", "b2", "go", 10, [
+        Symbol(
+            name="GetUser", type="method", lines=(3, 8), signature="(id int) (*User, error)",
+            docstring="GetUser retrieves a user by ID.", receiver="*DefaultService",
+        ),
+        Symbol(name="Greet", type="function", lines=(10, 10), signature="(name string) string"),
+    ], package="svc")
+    return store
+
+
+class TestMarkdownFormatter:
+    """Tests for format_markdown."""
+
+    def test_table_of_contents_links_anchors(self, tmp_path: Path):
+        output = format_markdown(_go_store(tmp_path))
+
+        toc = output.split("## Contents", 1)[1].split("<a id=", 1)[0]
+        assert "- [svc](#svc)" in toc
+        assert "  - [UserService](#userservice)" in toc
+        assert "    - [GetUser](#userservice-getuser)" in toc
+        assert "    - [GetUser](#defaultservice-getuser)" in toc
+        for anchor in ("svc", "userservice", "userservice-getuser", "defaultservice-getuser", "greet"):
+            assert f'<a id="{anchor}"></a>' in output
+
+    def test_methods_grouped_under_receiver(self, tmp_path: Path):
+        output = format_markdown(_go_store(tmp_path))
+
+        type_pos = output.index("### DefaultService")
+        method_pos = output.index('<a id="defaultservice-getuser"></a>\n#### GetUser')
+        assert type_pos < method_pos
+        assert "\n### GetUser" not in output
+
+    def test_doc_comment_name_prefix_trimmed(self, tmp_path: Path):
+        output = format_markdown(_go_store(tmp_path))
+
+        assert "Retrieves a user by ID." in output
+        assert "Handles user operations." in output
+        assert "GetUser retrieves" not in output
+
+    def test_fields_table(self, tmp_path: Path):
+        output = format_markdown(_go_store(tmp_path))
+
+        assert "| `users` | `map[int]*User` |  |" in output
+
+    def test_duplicate_anchors_are_suffixed(self, tmp_path: Path):
+        store = MapStore(tmp_path)
+        store.update_file("a.py", "a", "python", 1, [Symbol(name="run", type="function", lines=(1, 1))])
+        store.update_file("b/c.py", "b", "python", 1, [Symbol(name="run", type="function", lines=(1, 1))])
+
+        output = format_markdown(store)
+
+        assert '<a id="run"></a>' in output
+        assert '<a id="run-1"></a>' in output
+        assert "- [b](#b)" in output
+
+    def test_const_group_rendered_together(self, tmp_path: Path):
+        store = MapStore(tmp_path)
+        store.update_file("colors.go", "h", "go", 10, [
+            Symbol(name="Red", type="const", lines=(4, 4), signature="Color", value="iota", group="Red", docstring="Red is primary."),
+            Symbol(name="Green", type="const", lines=(5, 5), signature="Color", group="Red"),
+            Symbol(name="MaxSize", type="const", lines=(8, 8), value="1 << 10"),
+        ], package="colors")
+
+        output = format_markdown(store)
+
+        assert '<a id="const-red"></a>\n### const (Red, Green)' in output
+        assert "| `Red` | `Color` | `iota` | Is primary. |" in output
+        assert "| `Green` | `Color` |  |  |" in output
+        assert "`MaxSize = 1 << 10`" in output
+
+    def test_package_doc(self, tmp_path: Path):
+        store = _go_store(tmp_path)
+        store.update_file("svc/doc.go", "c3", "go", 2, [], package="svc", package_doc="Package svc serves users.")
+
+        output = format_markdown(store)
+
+        assert "Path: `svc` · Files: `svc/doc.go`, `svc/methods.go`, `svc/types.go`\n\nPackage svc serves users.\n" in output
+
+    def test_empty_index(self, tmp_path: Path):
+        assert format_markdown(MapStore(tmp_path)) == "# Code Map"
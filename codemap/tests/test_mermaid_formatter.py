@@ -0,0 +1,84 @@
+"""Tests for the Mermaid class diagram export."""
+
+from pathlib import Path
+
+from codemap.analysis import filter_exported
+from codemap.core.map_store import MapStore
+from codemap.formatters.mermaid_formatter import escape, format_mermaid
+from codemap.parsers.base import Field, Param, TypeParam
+
+from .factories import add_file, go_symbol, make_store, make_symbol
+
+
+def _method(name, receiver=None, params=(), results=()):
+    return go_symbol(
+        name, "method", receiver=receiver, signature="(" + ", ".join(str(p) for p in params) + ")",
+        params=list(params), results=list(results),
+    )
+
+
+def _store(tmp_path: Path) -> MapStore:
+    return make_store(tmp_path, {"store/store.go": [
+        go_symbol(
+            "Reader", "interface", lines=(1, 3),
+            children=[_method("Read", params=[Param("p", "[]byte")], results=[Param("n", "int"), Param("err", "error")])],
+        ),
+        go_symbol("ReadCloser", "interface", lines=(4, 6), embeds=["Reader"]),
+        go_symbol("base", "struct", lines=(7, 9), fields=[Field("id", "int")]),
+        go_symbol(
+            "File", "struct", lines=(10, 14), embeds=["*base"],
+            fields=[Field(None, "*base", embedded=True), Field("Meta", "map[string]*Set[int]")],
+        ),
+        _method("Read", "*File", [Param("p", "[]byte")], [Param("n", "int"), Param("err", "error")]),
+        _method("close", "*File", results=[Param(None, "error")]),
+        go_symbol("Set", "type", lines=(15, 15), signature="map[T]struct{}", type_params=[TypeParam("T", "comparable")]),
+        go_symbol("ID", "type", lines=(16, 16), signature="= int", is_alias=True),
+    ]}, link=True, lines=30, package="store")
+
+
+class TestFormatMermaid:
+    """Tests for format_mermaid."""
+
+    def test_output(self, tmp_path: Path):
+        assert format_mermaid(_store(tmp_path)) == "\n".join([
+            "classDiagram",
+            '  class store_Reader["Reader"]',
+            "  <<interface>> store_Reader",
+            "  store_Reader : +Read(p #91;#93;byte) #40;n int, err error#41;",
+            '  class store_ReadCloser["ReadCloser"]',
+            "  <<interface>> store_ReadCloser",
+            '  class store_base["base"]',
+            "  store_base : -id int",
+            '  class store_File["File"]',
+            "  store_File : -#42;base",
+            "  store_File : +Meta map#91;string#93;#42;Set#91;int#93;",
+            "  store_File : +Read(p #91;#93;byte) #40;n int, err error#41;",
+            "  store_File : -close() error",
+            '  class store_Set["Set#91;T#93;"]',
+            "  store_Reader <|-- store_ReadCloser",
+            "  store_ReadCloser <|.. store_File",
+            "  store_Reader <|.. store_File",
+            "  store_File o-- store_base",
+        ])
+
+    def test_exported_only(self, tmp_path: Path):
+        output = format_mermaid(filter_exported(_store(tmp_path)))
+
+        assert "store_base" not in output
+        assert "close" not in output
+        assert "  store_Reader <|.. store_File" in output
+
+    def test_packages_are_qualified(self, tmp_path: Path):
+        store = make_store(tmp_path, {"a/a.go": [make_symbol("T", "struct")]}, lines=1, package="a")
+        add_file(store, "b/util/b.go", [make_symbol("U", "struct", embeds=["a.T"])], lines=1, package="util")
+        add_file(store, "c/util/c.go", [make_symbol("U", "struct")], lines=1, package="util")
+
+        output = format_mermaid(store)
+
+        assert '  class a_T["a.T"]' in output
+        assert '  class b_util_U["util.U"]' in output
+        assert '  class c_util_U["util.U"]' in output
+        assert "  b_util_U *-- a_T" in output
+
+    def test_escape(self):
+        assert escape('func(x) <-chan *T "q" #') == "func#40;x#41; #60;-chan #42;T #34;q#34; #35;"
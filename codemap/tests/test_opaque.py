@@ -0,0 +1,129 @@
+"""Tests for indexing Go packages as opaque boundaries."""
+
+import io
+import json
+from pathlib import Path
+
+import pytest
+
+from codemap.analysis.opaque import boundary_node, opaque_prefix
+from codemap.core.indexer import Indexer
+from codemap.core.map_store import MapStore
+from codemap.formatters.json_formatter import build_document
+from codemap.formatters.jsonl_formatter import stream_jsonl
+from codemap.utils.config import Config
+
+LIB = '''// Package lib is vendored.
+package lib
+
+import "fmt"
+
+// TODO: upstream this
+type Base struct{}
+
+func (Base) Close() error { fmt.Println(); return nil }
+'''
+
+APP = '''package app
+
+import "example.com/app/internal/vendorized/lib"
+
+type Closer interface {
+	Close() error
+}
+
+type Conn struct {
+	lib.Base
+}
+
+func Use(b *lib.Base) {}
+'''
+
+
+def _tree(root: Path) -> None:
+    (root / "go.mod").write_text("module example.com/app\n")
+    (root / "internal" / "vendorized" / "lib").mkdir(parents=True)
+    (root / "internal" / "vendorized" / "lib" / "lib.go").write_text(LIB)
+    (root / "app.go").write_text(APP)
+
+
+class TestOpaquePrefix:
+    """Tests for opaque_prefix."""
+
+    def test_directory_and_import_path(self):
+        prefixes = ["internal/vendorized/", "github.com/aws"]
+
+        assert opaque_prefix(prefixes, "internal/vendorized") == "internal/vendorized"
+        assert opaque_prefix(prefixes, "internal/vendorized/lib") == "internal/vendorized"
+        assert opaque_prefix(prefixes, import_path="github.com/aws/sdk/s3") == "github.com/aws"
+        assert opaque_prefix(prefixes, "internal/vendorizedx", "github.com/awsx") is None
+        assert opaque_prefix(["", "/"], ".", "example.com/app") is None
+        assert boundary_node("github.com/aws") == "github.com/aws/..."
+
+
+class TestOpaqueIndexing:
+    """Tests for Go packages under Config.opaque prefixes."""
+
+    @pytest.fixture(autouse=True)
+    def _go(self):
+        pytest.importorskip("tree_sitter_go")
+
+    def _config(self, opaque):
+        return Config(languages=["go"], notes=True, opaque=opaque)
+
+    def test_opaque_package_is_a_leaf(self, tmp_path: Path):
+        _tree(tmp_path)
+
+        Indexer(tmp_path, config=self._config(["internal/vendorized"])).index_all()
+
+        store = MapStore.load(tmp_path)
+        lib = store.get_file("internal/vendorized/lib/lib.go")
+        assert (lib.opaque, lib.package, lib.symbols, lib.imports, lib.notes) == (True, "lib", [], [], [])
+        assert lib.package_doc == "Package lib is vendored."
+        app = store.get_file("app.go")
+        assert not app.opaque
+        closer, conn, use = app.symbols
+        assert conn.implements == [] and closer.implemented_by == []
+        assert use.params[0].resolved_type == "*example.com/app/internal/vendorized/lib.Base"
+        assert store.method_set("app.Conn").unresolved == ["lib.Base"]
+
+    def test_import_path_prefix(self, tmp_path: Path):
+        _tree(tmp_path)
+
+        Indexer(tmp_path, config=self._config(["example.com/app/internal"])).index_all()
+
+        assert MapStore.load(tmp_path).get_file("internal/vendorized/lib/lib.go").opaque
+
+    def test_without_prefixes_the_package_is_mapped(self, tmp_path: Path):
+        _tree(tmp_path)
+
+        Indexer(tmp_path, config=self._config([])).index_all()
+
+        store = MapStore.load(tmp_path)
+        assert [s.name for s in store.get_file("internal/vendorized/lib/lib.go").symbols] == ["Base", "Close"]
+        assert store.get_file("app.go").symbols[0].implemented_by == ["Conn", "lib.Base"]
+
+    def test_update_keeps_package_opaque(self, tmp_path: Path):
+        _tree(tmp_path)
+        indexer = Indexer(tmp_path, config=self._config(["internal/vendorized"]))
+        indexer.index_all()
+
+        (tmp_path / "internal" / "vendorized" / "lib" / "lib.go").write_text(LIB + "\nfunc New() Base { return Base{} }\n")
+        indexer.update_file(tmp_path / "internal" / "vendorized" / "lib" / "lib.go")
+
+        assert indexer.map_store.get_file("internal/vendorized/lib/lib.go").symbols == []
+
+    def test_exports(self, tmp_path: Path):
+        _tree(tmp_path)
+        config = self._config(["internal/vendorized"])
+        Indexer(tmp_path, config=config).index_all()
+        out = io.StringIO()
+
+        document = build_document(MapStore.load(tmp_path))
+        stream_jsonl(out, tmp_path, self._config(["internal/vendorized"]))
+
+        opaque = {p["path"]: p["opaque"] for p in document["packages"]}
+        assert opaque == {".": False, "internal/vendorized/lib": True}
+        lines = [json.loads(line) for line in out.getvalue().splitlines()]
+        lib = [r for r in lines if r["package_path"] == "internal/vendorized/lib"]
+        assert [(r["kind"], r["opaque"], r["imports"]) for r in lib] == [("file", True, [])]
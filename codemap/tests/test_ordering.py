@@ -0,0 +1,133 @@
+"""Tests for ordering the symbols of each file."""
+
+import shutil
+from pathlib import Path
+
+import pytest
+
+from codemap.analysis import order_symbols, sort_symbols
+from codemap.core.map_store import MapStore
+from codemap.parsers.base import Symbol
+
+from .factories import make_store, make_symbol
+
+FIXTURES = Path(__file__).parent / "fixtures"
+
+
+def _names(symbols: list[Symbol]) -> list[str]:
+    return [s.name for s in symbols]
+
+
+# Methods before and apart from their types, an orphan method, a const and a var
+SCATTERED = [
+    ("Close", "method", 3, "*Store"),
+    ("limit", "const", 1, None),
+    ("Open", "function", 5, None),
+    ("Store", "struct", 8, None),
+    ("Get", "method", 12, "*Store"),
+    ("ID", "type", 15, None),
+    ("String", "method", 17, "ID"),
+    ("apply", "method", 20, "Batch"),
+    ("cache", "var", 22, None),
+    ("Key", "method", 24, "*Cache[K]"),
+    ("Cache", "struct", 26, None),
+]
+
+
+def _scattered() -> list[Symbol]:
+    return [make_symbol(name, type, (line, line), receiver=receiver) for name, type, line, receiver in SCATTERED]
+
+
+class TestOrderSymbols:
+    """Tests for order_symbols."""
+
+    def test_source(self):
+        assert _names(order_symbols(_scattered(), "source")) == [
+            "limit", "Close", "Open", "Store", "Get", "ID", "String", "apply", "cache", "Key", "Cache",
+        ]
+
+    def test_alpha(self):
+        assert _names(order_symbols(_scattered(), "alpha")) == [
+            "apply", "Cache", "cache", "Close", "Get", "ID", "Key", "limit", "Open", "Store", "String",
+        ]
+
+    def test_grouped_methods_follow_their_type(self):
+        assert _names(order_symbols(_scattered(), "grouped")) == [
+            "Store", "Close", "Get",
+            "ID", "String",
+            "Cache", "Key",
+            "apply",  # Receiver declared in another file
+            "Open",
+            "limit", "cache",
+        ]
+
+    def test_same_line_uses_column(self):
+        symbols = [
+            make_symbol("b", "const", (2, 2), columns=(9, 10)),
+            make_symbol("a", "const", (2, 2), columns=(2, 3)),
+        ]
+
+        assert _names(order_symbols(symbols, "source")) == ["a", "b"]
+
+    def test_children_are_ordered(self):
+        children = [make_symbol("run", "method", (5, 5)), make_symbol("close", "method", (3, 3))]
+        cls = make_symbol("App", "class", (1, 1), children=children)
+
+        ordered = order_symbols([cls], "alpha")
+
+        assert _names(ordered[0].children) == ["close", "run"]
+
+    def test_complexity(self):
+        symbols = [
+            make_symbol("Config", "struct", (1, 1)),
+            make_symbol("simple", "function", (5, 5), complexity=1),
+            make_symbol("Do", "method", (9, 9), receiver="Policy", complexity=13),
+            make_symbol("limit", "const", (20, 20)),
+            make_symbol("parse", "function", (22, 22), complexity=4),
+            make_symbol("check", "function", (30, 30), complexity=4),
+        ]
+
+        assert _names(order_symbols(symbols, "complexity")) == [
+            "Do", "parse", "check", "simple", "Config", "limit",
+        ]
+
+    def test_deterministic_for_any_input_order(self):
+        for mode in ("source", "alpha", "grouped", "complexity"):
+            assert _names(order_symbols(_scattered(), mode)) == _names(order_symbols(_scattered()[::-1], mode))
+
+    def test_unknown_mode(self):
+        with pytest.raises(ValueError, match="Unknown sort mode"):
+            order_symbols([], "random")
+
+
+class TestSortSymbols:
+    """Tests for sort_symbols."""
+
+    def test_go_fixture(self, tmp_path: Path):
+        pytest.importorskip("tree_sitter_go")
+        from codemap.core.indexer import Indexer
+
+        shutil.copy(FIXTURES / "sample_module.go", tmp_path / "sample_module.go")
+        Indexer(root=tmp_path, languages=["go"]).index_all()
+        store = MapStore.load(tmp_path)
+
+        def order(mode: str) -> list[str]:
+            return _names(sort_symbols(store, mode).get_file("sample_module.go").symbols)
+
+        assert order("source") == [
+            "User", "UserService", "DefaultService", "GetUser", "CreateUser", "Greet", "Process",
+        ]
+        assert order("alpha") == [
+            "CreateUser", "DefaultService", "GetUser", "Greet", "Process", "User", "UserService",
+        ]
+        assert order("grouped") == [
+            "User", "UserService", "DefaultService", "GetUser", "CreateUser", "Greet", "Process",
+        ]
+
+    def test_does_not_modify_store(self, tmp_path: Path):
+        store = make_store(tmp_path, {"lib/lib.go": _scattered()}, lines=30, package="lib")
+
+        sorted_store = sort_symbols(store, "grouped")
+
+        assert _names(sorted_store.get_file("lib/lib.go").symbols)[:3] == ["Store", "Close", "Get"]
+        assert _names(store.get_file("lib/lib.go").symbols)[:2] == ["Close", "limit"]
@@ -0,0 +1,71 @@
+"""Tests for the on-disk parse cache."""
+
+from pathlib import Path
+
+from codemap.core.indexer import Indexer
+from codemap.core.map_store import MapStore
+from codemap.core.parse_cache import ParseCache
+from codemap.parsers.base import Note, ParseResult, Symbol
+from codemap.parsers.python_parser import PythonParser
+from codemap.utils.config import Config
+
+
+def _index(root: Path, cache: Path) -> dict:
+    return Indexer(root=root, config=Config(languages=["python"], cache_dir=str(cache))).index_all()
+
+
+def _snapshot(root: Path) -> list:
+    return [
+        (path, {k: v for k, v in entry.to_dict().items() if k != "indexed_at"})
+        for path, entry in MapStore.load(root).get_all_files()
+    ]
+
+
+class TestParseCache:
+    def test_round_trip_keeps_full_text(self, tmp_path: Path):
+        cache = ParseCache(tmp_path)
+        long_doc = "Explains at length. " * 20
+        result = ParseResult(
+            symbols=[Symbol(name="f", type="function", lines=(1, 2), signature="(" + "x, " * 60 + ")", docstring=long_doc)],
+            package="pkg",
+            notes=[Note(kind="TODO", text="split f", line=1, column=3, author="bob")],
+        )
+        key = cache.key(b"def f(): pass\n", "a.py", PythonParser())
+
+        cache.put(key, result)
+
+        assert cache.get(key) == result
+        assert cache.get(cache.key(b"def g(): pass\n", "a.py", PythonParser())) is None
+
+    def test_warm_run_skips_parsing(self, tmp_path: Path, monkeypatch):
+        root = tmp_path / "src"
+        root.mkdir()
+        (root / "a.py").write_text("class A:\n    def run(self): pass\n")
+        (root / "b.py").write_text("def b(): pass\n")
+        _index(root, tmp_path / "cache")
+        expected = _snapshot(root)
+
+        def fail(self, source, filepath=""):
+            raise AssertionError(f"parsed {filepath}")
+
+        monkeypatch.setattr(PythonParser, "parse_file", fail)
+        _index(root, tmp_path / "cache")
+
+        assert _snapshot(root) == expected
+
+    def test_changed_file_is_parsed_again(self, tmp_path: Path):
+        (tmp_path / "a.py").write_text("def old(): pass\n")
+        _index(tmp_path, tmp_path / ".cache")
+
+        (tmp_path / "a.py").write_text("def new(): pass\n")
+        _index(tmp_path, tmp_path / ".cache")
+
+        assert [s.name for s in MapStore.load(tmp_path).get_file("a.py").symbols] == ["new"]
+
+    def test_unreadable_entry_is_a_miss(self, tmp_path: Path):
+        cache = ParseCache(tmp_path)
+        key = cache.key(b"x = 1\n", "a.py", PythonParser())
+        cache.put(key, ParseResult(symbols=[]))
+        next(tmp_path.rglob("*.json")).write_text("{not json")
+
+        assert cache.get(key) is None
@@ -0,0 +1,63 @@
+"""Tests for parsing source from a stream."""
+
+import io
+from pathlib import Path
+
+import pytest
+
+from codemap.parsers import parse_reader
+from codemap.parsers.python_parser import PythonParser
+
+FIXTURES = Path(__file__).parent / "fixtures"
+
+
+class TestParseReader:
+    """Tests for parse_reader."""
+
+    def test_go_fixture_matches_disk(self):
+        pytest.importorskip("tree_sitter_go")
+        from codemap.parsers.go_parser import GoParser
+
+        path = FIXTURES / "sample_module.go"
+        from_disk = GoParser().parse_file(path.read_text(), str(path))
+
+        result = parse_reader(io.BytesIO(path.read_bytes()), "sample_module.go")
+
+        assert result.error is None
+        assert result.package == from_disk.package == "sample"
+        assert [s.to_dict() for s in result.symbols] == [s.to_dict() for s in from_disk.symbols]
+
+    def test_text_stream(self):
+        path = FIXTURES / "sample_module.py"
+        expected = PythonParser().parse(path.read_text())
+
+        result = parse_reader(io.StringIO(path.read_text()), "generated/sample_module.py")
+
+        assert [s.to_dict() for s in result.symbols] == [s.to_dict() for s in expected]
+
+    def test_go_syntax_error_keeps_parsed_declarations(self):
+        pytest.importorskip("tree_sitter_go")
+        source = b"""package broken
+
+// Good is fine.
+func Good() int { return 1 }
+
+func Bad( {
+"""
+
+        result = parse_reader(io.BytesIO(source), "broken.go")
+
+        assert result.error is not None
+        assert result.error.startswith("broken.go:")
+        assert result.package == "broken"
+        assert "Good" in [s.name for s in result.symbols]
+
+    def test_python_syntax_error(self):
+        result = parse_reader(io.StringIO("def broken(:\n"), "broken.py")
+
+        assert result.symbols == []
+        assert result.error
+
+    def test_unknown_extension(self):
+        with pytest.raises(ValueError):
+            parse_reader(io.StringIO(""), "notes.unknown")
@@ -118,6 +118,21 @@ class MyClass:
         assert len(symbols) == 1
         assert symbols[0].lines[0] == 2  # Decorator line
 
+    def test_columns(self, parser):
+        source = '''
+class Shape:
+    @property
+    def area(self):
+        return 0
+'''
+        shape = parser.parse(source)[0]
+        area = shape.children[0]
+
+        assert shape.columns == (1, 17)
+        assert area.columns == (5, 17)  # Starts at the decorator's "@"
+        assert str(area.pos("shapes.py")) == "shapes.py:3:5"
+        assert str(area.end_pos("shapes.py")) == "shapes.py:5:17"
+
     def test_parse_function_with_default_args(self, parser):
         source = '''
 def func(a: int, b: str = "default", c: bool = True):
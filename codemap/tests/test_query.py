@@ -0,0 +1,77 @@
+"""Tests for looking up symbols by path and name."""
+
+import shutil
+from pathlib import Path
+
+import pytest
+
+from codemap.core.map_store import MapStore
+from codemap.parsers.base import Field
+
+from .factories import add_file, make_store, make_symbol
+
+FIXTURES = Path(__file__).parent / "fixtures"
+
+
+class TestSymbolLookup:
+    """Tests for MapStore.lookup, find and find_by_name."""
+
+    def test_go_fixture_method_and_field(self, tmp_path: Path):
+        pytest.importorskip("tree_sitter_go")
+        from codemap.core.indexer import Indexer
+
+        shutil.copy(FIXTURES / "sample_module.go", tmp_path / "sample_module.go")
+        Indexer(root=tmp_path, languages=["go"]).index_all()
+        store = MapStore.load(tmp_path)
+
+        method = store.lookup("sample.DefaultService.GetUser")
+        field = store.lookup("sample.User.ID")
+
+        assert (method.symbol.type, method.symbol.receiver, method.file) == ("method", "*DefaultService", "sample_module.go")
+        assert method.field is None
+        assert (field.field.name, field.field.type, field.symbol.name) == ("ID", "int", "User")
+        assert store.lookup("sample.UserService.GetUser").symbol.type == "method"
+        assert store.lookup("sample.Missing") is None
+
+    def test_find_by_name_across_packages(self, tmp_path: Path):
+        store = make_store(tmp_path, {"a/a.go": [make_symbol("New"), make_symbol("helper")]}, package="a")
+        add_file(store, "b/b.go", [make_symbol("New")], package="b")
+        add_file(store, "tools/cli.py", [make_symbol("App", "class", children=[make_symbol("New", "method")])])
+
+        assert [m.path for m in store.find_by_name("New")] == ["a.New", "b.New", "tools.cli.App.New"]
+        assert store.find_by_name("new") == []
+
+    def test_find_with_predicate(self, tmp_path: Path):
+        store = make_store(tmp_path, {"lib.go": [
+            make_symbol("Config", "struct", fields=[Field("Timeout", "int")]),
+            make_symbol("Old", deprecated=True),
+            make_symbol("New"),
+        ]}, package="lib")
+
+        assert [m.path for m in store.find(lambda s: s.type == "function")] == ["lib.Old", "lib.New"]
+        assert [m.name for m in store.find(lambda s: s.deprecated)] == ["Old"]
+
+    def test_paths_for_embedded_fields_duplicates_and_shared_package_names(self, tmp_path: Path):
+        store = make_store(tmp_path, {
+            "x/util/u.go": [
+                make_symbol("Conn", "struct", fields=[Field(None, "*io.Reader", embedded=True)]),
+                make_symbol("init"), make_symbol("init"),
+            ],
+            "y/util/u.go": [make_symbol("Conn", "struct")],
+        }, package="util")
+
+        assert store.lookup("x/util/util.Conn.Reader").field.embedded is True
+        assert store.lookup("x/util/util.init#2") is not None
+        assert store.lookup("y/util/util.Conn").file == "y/util/u.go"
+
+    def test_index_is_rebuilt_after_changes(self, tmp_path: Path):
+        store = make_store(tmp_path, {"lib.go": [make_symbol("Old")]}, package="lib")
+        index = store.symbol_index()
+
+        assert store.symbol_index() is index
+        store.update_file("lib.go", "h2", "go", 1, [make_symbol("New")], package="lib")
+
+        assert store.lookup("lib.Old") is None
+        assert store.lookup("lib.New") is not None
+        store.remove_file("lib.go")
+        assert store.lookup("lib.New") is None
@@ -0,0 +1,95 @@
+"""Tests for symbol statistics and their rendering."""
+
+from pathlib import Path
+
+from codemap.core.map_store import MapStore
+from codemap.formatters import format_stats
+
+from .factories import add_file, go_symbol, make_store, make_symbol
+
+
+def _store(tmp_path: Path) -> MapStore:
+    reader = go_symbol("Reader", "interface", children=[go_symbol("Read", "method"), go_symbol("Close", "method")])
+    store = make_store(tmp_path, {
+        "store/store.go": [
+            go_symbol("Store", "struct"), reader, go_symbol("Get", "method"), go_symbol("open"), go_symbol("New"),
+        ],
+    }, lines=40, package="store")
+    add_file(store, "store/cache.go", [go_symbol("cache", "struct")], package="store")
+    add_file(store, "store/store_test.go", [go_symbol("TestGet", "test")], package="store_test")
+    add_file(store, "main.go", [go_symbol("main")], lines=5, package="main")
+    add_file(store, "tools/gen.py", [make_symbol("run")], lines=5)
+    return store
+
+
+class TestMapStats:
+    """Tests for MapStore.symbol_stats."""
+
+    def test_totals_by_kind(self, tmp_path: Path):
+        stats = _store(tmp_path).symbol_stats()
+
+        assert stats.files == 5
+        assert stats.total == 11
+        # Interface methods count as methods
+        assert stats.kinds["method"].total == 3
+        assert stats.kinds["struct"].exported == 1
+        assert stats.kinds["struct"].unexported == 1
+
+    def test_symbols_without_export_convention(self, tmp_path: Path):
+        stats = _store(tmp_path).symbol_stats()
+
+        # tools/gen.py's run is counted but neither exported nor unexported
+        assert stats.kinds["function"].total == 4
+        assert (stats.kinds["function"].exported, stats.kinds["function"].unexported) == (1, 2)
+        assert stats.exported + stats.unexported == stats.total - 1
+
+    def test_per_package(self, tmp_path: Path):
+        stats = _store(tmp_path).symbol_stats()
+
+        assert [(p.path, p.name) for p in stats.packages] == [
+            (".", "main"), ("store", "store"), ("store", "store_test"), ("tools", None),
+        ]
+        store = stats.packages[1]
+        assert (store.files, store.total, store.exported, store.unexported) == (2, 8, 6, 2)
+        assert store.kinds["method"].total == 3
+
+    def test_largest(self, tmp_path: Path):
+        stats = _store(tmp_path).symbol_stats()
+
+        assert [(p.path, p.name) for p in stats.largest(2)] == [("store", "store"), (".", "main")]
+
+    def test_empty_index(self, tmp_path: Path):
+        stats = MapStore(tmp_path).symbol_stats()
+
+        assert (stats.files, stats.total, stats.packages) == (0, 0, [])
+
+
+class TestFormatStats:
+    """Tests for format_stats."""
+
+    def test_overview(self, tmp_path: Path):
+        text = format_stats(_store(tmp_path).symbol_stats(), top=2)
+
+        assert text.splitlines()[0] == "Symbols: 11 (7 exported, 3 unexported) in 5 files, 4 packages"
+        assert "Type       Total  Exported  Unexported\nfunction       4         1           2" in text
+        assert "Largest packages  Files  Symbols  Exported  Unexported\nstore (store)         2        8" in text
+        assert "main (.)" in text
+        assert "store_test" not in text
+
+    def test_without_packages(self, tmp_path: Path):
+        text = format_stats(_store(tmp_path).symbol_stats(), top=0)
+
+        assert "Largest packages" not in text
+
+    def test_single_package(self, tmp_path: Path):
+        stats = _store(tmp_path).symbol_stats()
+
+        text = format_stats(stats, package=stats.packages[1])
+
+        assert text.splitlines()[0] == "Package store (store): 8 symbols (6 exported, 2 unexported) in 2 files"
+        assert "method         3" in text
+
+    def test_package_without_name(self, tmp_path: Path):
+        stats = _store(tmp_path).symbol_stats()
+
+        assert format_stats(stats, package=stats.packages[3]).startswith("Package tools: 1 symbols")
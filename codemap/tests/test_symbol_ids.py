@@ -0,0 +1,127 @@
+"""Tests for symbol IDs and content hashes."""
+
+from pathlib import Path
+
+import pytest
+
+from codemap.core.indexer import Indexer
+from codemap.core.map_store import MapStore
+from codemap.parsers.base import MAX_SIGNATURE_LENGTH, Field, Param, Symbol
+from codemap.utils.config import Config
+
+
+def _method(signature="(id int) (*User, error)", lines=(10, 14), docstring=None):
+    return Symbol(
+        name="GetUser", type="method", lines=lines, signature=signature,
+        receiver="*DefaultService", docstring=docstring,
+    )
+
+
+class TestContentHash:
+    def test_positions_and_docs_dont_count(self):
+        moved = _method(lines=(40, 44), docstring="GetUser retrieves a user.")
+        moved.columns = (1, 2)
+        moved.complexity = 7
+
+        assert moved.content_hash() == _method().content_hash()
+
+    def test_signature_change(self):
+        assert _method("(id int64) (*User, error)").content_hash() != _method().content_hash()
+        assert _method("(id int) *User").content_hash() != _method().content_hash()
+
+    def test_whitespace_only_change(self):
+        assert _method("(id  int)  (*User,error)").content_hash() == _method().content_hash()
+        # Whitespace inside a string literal is part of the value
+        a = Symbol(name="Sep", type="const", lines=(1, 1), value='"a b"')
+        b = Symbol(name="Sep", type="const", lines=(1, 1), value='"a  b"')
+        assert a.content_hash() != b.content_hash()
+
+    def test_fields_methods_and_children(self):
+        def user(*fields, methods=(), children=()):
+            return Symbol(
+                name="User", type="struct", lines=(1, 5), fields=list(fields),
+                methods=list(methods), children=list(children),
+            )
+
+        base = user(Field(name="ID", type="int"))
+        assert user(Field(name="ID", type="int", lines=(9, 9))).content_hash() == base.content_hash()
+        assert user(Field(name="ID", type="int64")).content_hash() != base.content_hash()
+        assert user(Field(name="ID", type="int", tag='json:"id"')).content_hash() != base.content_hash()
+        assert user(Field(name="ID", type="int"), methods=["String"]).content_hash() != base.content_hash()
+        assert user(Field(name="ID", type="int"), children=[_method()]).content_hash() != base.content_hash()
+
+    def test_round_trip(self):
+        symbol = _method()
+        symbol.id = "example.com/app/sample.DefaultService.GetUser"
+        loaded = Symbol.from_dict(symbol.to_dict())
+
+        assert loaded.id == symbol.id
+        assert loaded.content_hash() == symbol.content_hash()
+
+    def test_change_past_the_stored_signature(self):
+        def handler(last: str) -> Symbol:
+            params = [Param(name, "string") for name in ("tenant", "region", "bucket", "prefix", "owner", "token")]
+            params.append(Param("timeout", last))
+            signature = "(" + ", ".join(str(p) for p in params) + ") (*Result, error)"
+            return Symbol(
+                name="Fetch", type="function", lines=(1, 3), signature=signature, params=params,
+                results=[Param(None, "*Result"), Param(None, "error")],
+            )
+
+        a, b = handler("time.Duration"), handler("int")
+        assert len(a.signature) > MAX_SIGNATURE_LENGTH
+        loaded_a, loaded_b = (Symbol.from_dict(s.to_dict()) for s in (a, b))
+
+        assert loaded_a.signature == loaded_b.signature
+        assert loaded_a.content_hash() == a.content_hash()
+        assert loaded_a.content_hash() != loaded_b.content_hash()
+
+
+class TestIds:
+    def test_python_module_paths(self, tmp_path: Path):
+        (tmp_path / "pkg").mkdir()
+        (tmp_path / "pkg" / "app.py").write_text("class App:\n    def run(self):\n        pass\n\ndef main():\n    pass\n")
+        Indexer(tmp_path, config=Config()).index_all()
+
+        app, main = MapStore.load(tmp_path).get_file("pkg/app.py").symbols
+        assert (app.id, app.children[0].id, main.id) == ("pkg.app.App", "pkg.app.App.run", "pkg.app.main")
+
+    def test_go_import_paths(self, tmp_path: Path):
+        pytest.importorskip("tree_sitter_go")
+        (tmp_path / "go.mod").write_text("module example.com/app\n")
+        (tmp_path / "svc").mkdir()
+        (tmp_path / "svc" / "a.go").write_text(
+            "package svc\n\ntype Store interface {\n\tGet(id int) error\n}\n\nfunc init() {}\n"
+        )
+        (tmp_path / "svc" / "b.go").write_text(
+            "package svc\n\nfunc (s *Service) Get(id int) error { return nil }\n\nfunc init() {}\n\ntype Service struct{}\n"
+        )
+        (tmp_path / "svc" / "svc_test.go").write_text("package svc_test\n\nfunc Helper() {}\n")
+        Indexer(tmp_path, config=Config(include_tests=True)).index_all()
+        store = MapStore.load(tmp_path)
+
+        def ids(path):
+            return [s.id for s in store.get_file(path).symbols]
+
+        assert ids("svc/a.go") == ["example.com/app/svc.Store", "example.com/app/svc.init"]
+        assert store.get_file("svc/a.go").symbols[0].children[0].id == "example.com/app/svc.Store.Get"
+        assert ids("svc/b.go") == [
+            "example.com/app/svc.Service.Get", "example.com/app/svc.init#2", "example.com/app/svc.Service",
+        ]
+        assert ids("svc/svc_test.go") == ["example.com/app/svc_test.Helper"]
+
+    def test_ids_ignore_lines(self, tmp_path: Path):
+        pytest.importorskip("tree_sitter_go")
+        path = tmp_path / "main.go"
+        path.write_text("package main\n\nfunc Run() {}\n")
+        indexer = Indexer(tmp_path, config=Config())
+        indexer.index_all()
+        before = MapStore.load(tmp_path).get_file("main.go").symbols[0]
+
+        path.write_text("package main\n\n// Run runs.\n\nfunc Run()  {}\n")
+        indexer.update_file(path)
+        after = MapStore.load(tmp_path).get_file("main.go").symbols[0]
+
+        assert after.lines != before.lines
+        assert (after.id, after.content_hash()) == (before.id, before.content_hash())
+        assert after.id == "main.Run"
@@ -0,0 +1,104 @@
+"""Tests for retained syntax trees."""
+
+import ast
+
+import pytest
+
+from codemap.parsers.base import Position
+from codemap.parsers.python_parser import PythonParser
+from codemap.parsers.syntax import SyntaxTree
+
+GO_SOURCE = '''package svc
+
+// Greet says hello to "héllo" callers.
+func Greet(name string) string {
+	go log(name)
+	return "héllo " + name
+}
+
+func log(s string) {}
+'''
+
+PY_SOURCE = '''def greet(name):
+    msg = "héllo " + name
+    return msg
+'''
+
+
+def _go_tree() -> SyntaxTree:
+    pytest.importorskip("tree_sitter_go")
+    from codemap.parsers.go_parser import GoParser
+
+    tree = GoParser().syntax_tree(GO_SOURCE, "svc/greet.go")
+    return SyntaxTree("svc/greet.go", "go", GO_SOURCE.encode("utf-8"), tree)
+
+
+def _py_tree() -> SyntaxTree:
+    tree = PythonParser().syntax_tree(PY_SOURCE, "greet.py")
+    return SyntaxTree("greet.py", "python", PY_SOURCE.encode("utf-8"), tree)
+
+
+class TestTreeSitter:
+    def test_walk_in_source_order(self):
+        tree = _go_tree()
+        names = [tree.text(n) for n in tree.walk() if n.type == "identifier"]
+        assert names == ["Greet", "name", "log", "name", "name", "log", "s"]
+        assert next(tree.walk()) is tree.root
+
+    def test_position_and_text(self):
+        tree = _go_tree()
+        [statement] = [n for n in tree.walk() if n.type == "go_statement"]
+        assert tree.position(statement) == Position("svc/greet.go", 5, 2)
+        assert tree.end_position(statement) == Position("svc/greet.go", 5, 14)
+        assert tree.text(statement) == "go log(name)"
+
+    def test_columns_count_bytes(self):
+        tree = _go_tree()
+        [expression] = [n for n in tree.walk() if n.type == "binary_expression"]
+        operand = expression.children[-1]
+        # "\treturn \"héllo \" + " is 20 bytes; é takes two
+        assert tree.position(operand) == Position("svc/greet.go", 6, 21)
+        assert tree.text(operand) == "name"
+
+    def test_inspect_skips_children(self):
+        tree = _go_tree()
+        seen = []
+
+        def visit(node):
+            seen.append(node.type)
+            return node.type != "function_declaration"
+
+        tree.inspect(visit)
+        assert seen.count("function_declaration") == 2
+        assert "go_statement" not in seen
+
+    def test_walk_from_node(self):
+        tree = _go_tree()
+        function = next(n for n in tree.walk() if n.type == "function_declaration")
+        assert any(n.type == "go_statement" for n in tree.walk(function))
+        last = [n for n in tree.walk() if n.type == "function_declaration"][-1]
+        assert not any(n.type == "go_statement" for n in tree.walk(last))
+
+
+class TestPython:
+    def test_walk_and_text(self):
+        tree = _py_tree()
+        assert isinstance(tree.root, ast.Module)
+        names = [n.id for n in tree.walk() if isinstance(n, ast.Name)]
+        assert names == ["msg", "name", "msg"]
+
+    def test_position_and_text(self):
+        tree = _py_tree()
+        [binop] = [n for n in tree.walk() if isinstance(n, ast.BinOp)]
+        assert tree.position(binop) == Position("greet.py", 2, 11)
+        assert tree.end_position(binop) == Position("greet.py", 2, 27)
+        assert tree.text(binop) == '"héllo " + name'
+
+    def test_nodes_without_positions(self):
+        tree = _py_tree()
+        assert tree.position(tree.root) is None
+        assert tree.end_position(tree.root) is None
+        assert tree.text(tree.root) is None
+
+    def test_syntax_error_has_no_tree(self):
+        assert PythonParser().syntax_tree("def broken(:\n") is None
@@ -0,0 +1,139 @@
+"""Tests for resolving package qualifiers in Go types to import paths."""
+
+from pathlib import Path
+
+import pytest
+
+from codemap.analysis import ImportScope, assumed_package_name, collect_go_packages, resolve_type_refs
+from codemap.core.map_store import FileEntry
+from codemap.parsers.base import Field, Import, Param, Symbol, TypeParam
+
+
+def _entry(package, symbols, imports):
+    return FileEntry(hash="h", indexed_at="", language="go", lines=1, symbols=symbols, package=package, imports=imports)
+
+
+class TestAssumedPackageName:
+    def test_last_element(self):
+        assert assumed_package_name("net/http") == "http"
+
+    def test_major_version_and_go_prefix(self):
+        assert assumed_package_name("github.com/x/go-yaml/v3") == "yaml"
+        assert assumed_package_name("gopkg.in/yaml.v3") == "yaml"
+
+
+class TestImportScope:
+    def test_qualified_types(self):
+        scope = ImportScope([Import("github.com/x/other"), Import("net/http", name="h")])
+
+        assert scope.resolve("*other.Thing") == "*github.com/x/other.Thing"
+        assert scope.resolve("map[string][]h.Handler") == "map[string][]net/http.Handler"
+        assert scope.resolve("func(other.A) (other.B, error)") == (
+            "func(github.com/x/other.A) (github.com/x/other.B, error)"
+        )
+        assert scope.resolve("other.List[other.Item]") == "github.com/x/other.List[github.com/x/other.Item]"
+
+    def test_nothing_to_resolve(self):
+        scope = ImportScope([Import("github.com/x/other"), Import("github.com/x/side", name="_")])
+
+        assert scope.resolve("int") is None
+        assert scope.resolve("*User") is None
+        assert scope.resolve("unknown.Thing") is None
+        assert scope.resolve("side.Thing") is None
+
+    def test_dot_import_outside_index(self):
+        scope = ImportScope([Import("example.com/dot", name=".")], local_types=["User"])
+
+        assert scope.resolve("[]Thing") == "[]example.com/dot.Thing"
+        assert scope.resolve("*User") is None
+        assert scope.resolve("T", type_params=["T"]) is None
+        assert scope.resolve("struct{ ID int; Owner Person }") == "struct{ ID int; Owner example.com/dot.Person }"
+        assert scope.resolve("interface{ Close() error }") is None
+
+    def test_several_dot_imports_use_the_index(self):
+        scope = ImportScope(
+            [Import("example.com/a", name="."), Import("example.com/b", name=".")],
+            package_types={"example.com/a": {"Apple"}, "example.com/b": {"Banana"}},
+        )
+
+        assert scope.resolve("Banana") == "example.com/b.Banana"
+        assert scope.resolve("Cherry") is None
+
+
+class TestResolveTypeRefs:
+    def test_uses_declared_package_names(self, tmp_path: Path):
+        (tmp_path / "go.mod").write_text("module example.com/app\n")
+        files = [
+            ("lib/v2/lib.go", _entry("lib", [Symbol(name="Item", type="struct", lines=(1, 1))], [])),
+            ("api/api.go", _entry("api", [
+                Symbol(
+                    name="List", type="function", lines=(1, 1),
+                    type_params=[TypeParam(name="T")],
+                    params=[Param(name="t", type="T")],
+                    results=[Param(name=None, type="[]*lib.Item")],
+                ),
+                Symbol(
+                    name="Server", type="struct", lines=(1, 1),
+                    fields=[Field(name="items", type="map[string]lib.Item"), Field(name="n", type="int")],
+                ),
+            ], [Import("example.com/app/lib/v2")])),
+        ]
+        packages = collect_go_packages(files, tmp_path)
+
+        resolve_type_refs(packages)
+
+        api = files[1][1].symbols
+        assert api[0].params[0].resolved_type is None
+        assert api[0].results[0].resolved_type == "[]*example.com/app/lib/v2.Item"
+        assert [f.resolved_type for f in api[1].fields] == ["map[string]example.com/app/lib/v2.Item", None]
+
+    def test_resolves_embeds(self, tmp_path: Path):
+        (tmp_path / "go.mod").write_text("module example.com/app\n")
+        store = Symbol(name="Store", type="interface", lines=(1, 1), embeds=["Getter", "lib.Closer", "io.Writer"])
+        plain = Symbol(name="Getter", type="interface", lines=(1, 1))
+        files = [("api/api.go", _entry("api", [store, plain], [Import("example.com/app/lib"), Import("io")]))]
+
+        resolve_type_refs(collect_go_packages(files, tmp_path))
+
+        assert store.resolved_embeds == [None, "example.com/app/lib.Closer", None]
+        assert plain.resolved_embeds == []
+
+    def test_go_files(self, tmp_path: Path):
+        pytest.importorskip("tree_sitter_go")
+        from codemap.core.indexer import Indexer
+        from codemap.core.map_store import MapStore
+
+        (tmp_path / "go.mod").write_text("module example.com/app\n")
+        (tmp_path / "store").mkdir()
+        (tmp_path / "store" / "store.go").write_text("package store\n\ntype Record struct{}\n")
+        (tmp_path / "main.go").write_text(
+            "package main\n\n"
+            'import (\n\tdb "example.com/app/store"\n\t. "example.com/dot"\n)\n\n'
+            "func Load(id Key) (*db.Record, error) { return nil, nil }\n"
+        )
+        Indexer(root=tmp_path, languages=["go"]).index_all()
+
+        load = MapStore.load(tmp_path).get_file("main.go").symbols[0]
+
+        assert [p.resolved_type for p in load.params] == ["example.com/dot.Key"]
+        assert [r.type for r in load.results] == ["*db.Record", "error"]
+        assert [r.resolved_type for r in load.results] == ["*example.com/app/store.Record", None]
+
+    def test_go_interface_embeds(self, tmp_path: Path):
+        pytest.importorskip("tree_sitter_go")
+        from codemap.core.indexer import Indexer
+        from codemap.core.map_store import MapStore
+
+        (tmp_path / "go.mod").write_text("module example.com/app\n")
+        (tmp_path / "main.go").write_text(
+            "package main\n\n"
+            'import (\n\t"io"\n\tdb "example.com/app/store"\n)\n\n'
+            "type Store interface {\n\tio.ReadWriteCloser\n\tdb.Getter\n\tFlush() error\n}\n"
+        )
+        Indexer(root=tmp_path, languages=["go"]).index_all()
+
+        store = MapStore.load(tmp_path).get_file("main.go").symbols[0]
+
+        assert store.embeds == ["io.ReadWriteCloser", "db.Getter"]
+        assert store.resolved_embeds == [None, "example.com/app/store.Getter"]
+        assert [c.name for c in store.children] == ["Flush"]
@@ -11,6 +11,7 @@ try:
         CodeMapWatcher,
         CodemapEventHandler,
         watch_directory,
+        watch_maps,
         WATCHDOG_AVAILABLE,
     )
 except ImportError:
@@ -67,6 +68,39 @@ class TestCodemapEventHandler:
         assert not handler._should_process(str(sample_project / "node_modules" / "lib.py"))
         assert not handler._should_process(str(sample_project / "__pycache__" / "cache.py"))
 
+    def test_should_not_process_editor_temp_files(self, handler, sample_project):
+        assert not handler._should_process(str(sample_project / ".#main.py"))
+        assert not handler._should_process(str(sample_project / "main.py~"))
+        assert not handler._should_process(str(sample_project / "#main.py#"))
+
+    def test_should_not_process_unconfigured_language(self, handler, sample_project):
+        handler.config.languages = ["go"]
+        assert not handler._should_process(str(sample_project / "test.py"))
+        assert handler._should_process(str(sample_project / "main.go"))
+
+    def test_should_not_process_files_past_max_depth(self, handler, sample_project):
+        handler.config.max_depth = 1
+        assert handler._should_process(str(sample_project / "src" / "app.py"))
+        assert not handler._should_process(str(sample_project / "src" / "api" / "app.py"))
+
+    def test_should_not_process_ignored_files(self, handler, sample_project):
+        (sample_project / ".gitignore").write_text("gen.py\nbuild_out/\n")
+        (sample_project / "src").mkdir()
+        (sample_project / "src" / ".gitignore").write_text("*.py\n!keep.py\n")
+        (sample_project / "extra.ignore").write_text("scratch/\n")
+        handler.config.ignore_file = "extra.ignore"
+
+        assert not handler._should_process(str(sample_project / "gen.py"))
+        assert not handler._should_process(str(sample_project / "build_out" / "app.py"))
+        assert not handler._should_process(str(sample_project / "src" / "app.py"))
+        assert not handler._should_process(str(sample_project / "scratch" / "app.py"))
+        assert handler._should_process(str(sample_project / "src" / "keep.py"))
+        assert handler._should_process(str(sample_project / "main.py"))
+
+        handler.config.respect_gitignore = False
+        assert handler._should_process(str(sample_project / "gen.py"))
+        assert not handler._should_process(str(sample_project / "scratch" / "app.py"))
+
     def test_should_not_process_directory(self, handler, sample_project):
         (sample_project / "subdir").mkdir()
         assert not handler._should_process(str(sample_project / "subdir"))
@@ -331,3 +365,103 @@ class TestWatchDirectory:
             assert watcher.is_running
         finally:
             watcher.stop()
+
+
+class TestWatchMaps:
+    """Tests for watch_maps."""
+
+    @pytest.fixture
+    def sample_project(self, tmp_path: Path):
+        """Create a sample project with codemap."""
+        from codemap.core.indexer import Indexer
+
+        root = tmp_path / "project"
+        (root / "pkg").mkdir(parents=True)
+        (root / "main.py").write_text("def main(): pass")
+        (root / "pkg" / "lib.py").write_text("def lib(): pass")
+
+        indexer = Indexer(root=root)
+        indexer.index_all()
+        return root
+
+    def _next(self, maps, timeout=2.0):
+        """Get the next map, failing the test instead of blocking forever."""
+        import threading
+
+        result = []
+        thread = threading.Thread(target=lambda: result.append(next(maps, None)), daemon=True)
+        thread.start()
+        thread.join(timeout)
+        assert result, "no map within timeout"
+        return result[0]
+
+    def test_burst_of_writes_yields_one_map(self, sample_project):
+        maps, cancel = watch_maps(sample_project, debounce_seconds=0.3)
+        try:
+            for i in range(5):
+                (sample_project / "main.py").write_text(f"def main(): pass\ndef f{i}(): pass")
+                time.sleep(0.05)
+
+            store = self._next(maps)
+            assert [s.name for s in store.get_file("main.py").symbols] == ["main", "f4"]
+            cancel()
+            assert next(maps, None) is None
+        finally:
+            cancel()
+
+    def test_only_watched_languages_trigger(self, sample_project):
+        pytest.importorskip("tree_sitter_go")
+        maps, cancel = watch_maps(sample_project, debounce_seconds=0.1, languages=["go"])
+        try:
+            (sample_project / "main.py").write_text("def changed(): pass")
+            time.sleep(0.4)
+            (sample_project / "main.go").write_text("package main\n\nfunc Main() {}\n")
+
+            store = self._next(maps)
+            assert store.get_file("main.go") is not None
+            assert [s.name for s in store.get_file("main.py").symbols] == ["main"]
+        finally:
+            cancel()
+
+    def test_directory_moved_in_and_deleted(self, sample_project):
+        import shutil
+
+        outside = sample_project.parent / "outside"
+        (outside / "extra").mkdir(parents=True)
+        (outside / "extra" / "more.py").write_text("def more(): pass")
+
+        maps, cancel = watch_maps(sample_project, debounce_seconds=0.2)
+        try:
+            shutil.move(str(outside / "extra"), str(sample_project / "extra"))
+            assert self._next(maps).get_file("extra/more.py") is not None
+
+            shutil.rmtree(sample_project / "pkg")
+            store = self._next(maps)
+            assert sorted(p for p, _ in store.get_all_files()) == ["extra/more.py", "main.py"]
+        finally:
+            cancel()
+
+    def test_created_directory_is_scanned(self, sample_project):
+        """Files that arrive with a directory, without events of their own, are indexed."""
+        from codemap.core.watcher import CodeMapWatcher
+
+        maps = []
+        watcher = CodeMapWatcher(root=sample_project, on_map=maps.append)
+        (sample_project / "new" / "deep").mkdir(parents=True)
+        (sample_project / "new" / "deep" / "x.py").write_text("def x(): pass")
+        (sample_project / "new" / ".#x.py").write_text("def lock(): pass")
+
+        watcher._handle_change(sample_project / "new", "dir_created")
+        watcher._finish_batch()
+
+        assert [p for p, _ in maps[0].get_all_files() if p.startswith("new/")] == ["new/deep/x.py"]
+        watcher._finish_batch()
+        assert len(maps) == 1
+
+    def test_cancel_ends_iteration(self, sample_project):
+        maps, cancel = watch_maps(sample_project, debounce_seconds=0.1)
+
+        cancel()
+        cancel()
+
+        assert list(maps) == []
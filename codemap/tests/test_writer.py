@@ -0,0 +1,157 @@
+"""Tests for writing exports to text streams."""
+
+import io
+from pathlib import Path
+
+import pytest
+
+from codemap.core.map_store import MapStore
+from codemap.formatters import FORMATTERS, WRITERS, ShortWriteError, TextSink, render
+from codemap.formatters.jsonl_formatter import stream_jsonl
+from codemap.utils.config import Config
+
+from .factories import make_store, make_symbol
+
+
+class _ShortWrites(io.StringIO):
+    """Stream that accepts at most a few characters per write."""
+
+    def __init__(self, limit: int):
+        super().__init__()
+        self.limit = limit
+        self.calls = 0
+
+    def write(self, text: str) -> int:
+        self.calls += 1
+        return super().write(text[: self.limit])
+
+
+class _Stalled(io.StringIO):
+    """Stream that stops accepting anything after the first few characters."""
+
+    def write(self, text: str) -> int:
+        if self.tell() >= 10:
+            return 0
+        return super().write(text[: 10 - self.tell()])
+
+
+class _FailingFlush(io.StringIO):
+    def flush(self):
+        raise OSError("No space left on device")
+
+
+def _store(root: Path) -> MapStore:
+    app = make_symbol(
+        "App", "class", lines=(1, 5), docstring="Runs things.",
+        children=[make_symbol("run", "method", lines=(4, 5), signature="(self) -> None")],
+    )
+    store = make_store(root, {
+        "app.py": [app],
+        "util.py": [make_symbol("helper", lines=(1, 2), signature="(x)")],
+        "lib/text.py": [make_symbol("join", lines=(3, 4), signature="(parts)")],
+    }, lines=5)
+    store.set_metadata(str(root), {})
+    return store
+
+
+def _sources(root: Path) -> None:
+    (root / "app.py").write_text(
+        'class App:\n    """Runs things."""\n\n    def run(self) -> None:\n        pass\n'
+    )
+    (root / "util.py").write_text("def helper(x):\n    return x\n")
+    (root / "lib").mkdir()
+    (root / "lib" / "text.py").write_text('SEP = "\\n"\n\ndef join(parts):\n    return SEP.join(parts)\n')
+
+
+class TestTextSink:
+    """Tests for TextSink."""
+
+    def test_retries_short_writes(self):
+        out = _ShortWrites(limit=3)
+
+        assert TextSink(out).write("hello world") == 11
+
+        assert out.getvalue() == "hello world"
+        assert out.calls == 4
+
+    def test_no_progress_raises(self):
+        with pytest.raises(ShortWriteError, match="10 of 20"):
+            TextSink(_Stalled()).write("x" * 20)
+
+    def test_streams_without_a_count(self):
+        lines = []
+
+        class Collector:
+            def write(self, text):
+                lines.append(text)
+
+        sink = TextSink(Collector())
+        sink.write_lines(["a", "b"])
+        sink.flush()
+
+        assert lines == ["a\n", "b\n"]
+
+
+class TestRender:
+    """Tests for render and the write_* functions."""
+
+    @pytest.mark.parametrize("output_format", sorted(WRITERS))
+    def test_matches_formatter(self, tmp_path: Path, output_format: str):
+        store = _store(tmp_path)
+        out = io.StringIO()
+
+        render(out, store, output_format)
+
+        assert out.getvalue() == FORMATTERS[output_format](store) + "\n"
+
+    @pytest.mark.parametrize("output_format", ["markdown", "compact", "json", "jsonl", "xml"])
+    def test_short_writes(self, tmp_path: Path, output_format: str):
+        store = _store(tmp_path)
+        out = _ShortWrites(limit=7)
+
+        render(out, store, output_format)
+
+        assert out.getvalue() == FORMATTERS[output_format](store) + "\n"
+
+    @pytest.mark.parametrize("output_format", sorted(WRITERS))
+    def test_empty_index(self, tmp_path: Path, output_format: str):
+        store = MapStore.in_memory(tmp_path)
+        store.set_metadata(str(tmp_path), {})
+        out = io.StringIO()
+
+        render(out, store, output_format)
+
+        text = FORMATTERS[output_format](store)
+        assert out.getvalue() == (text + "\n" if text else "")
+
+    def test_options(self, tmp_path: Path):
+        store = _store(tmp_path)
+        out = io.StringIO()
+
+        render(out, store, "json", method_sets=True)
+
+        assert out.getvalue() == FORMATTERS["json"](store, method_sets=True) + "\n"
+
+    @pytest.mark.parametrize("output_format", ["markdown", "compact", "xml"])
+    def test_stalled_stream_raises(self, tmp_path: Path, output_format: str):
+        with pytest.raises(ShortWriteError):
+            render(_Stalled(), _store(tmp_path), output_format)
+
+    @pytest.mark.parametrize("output_format", sorted(WRITERS))
+    def test_flush_error_propagates(self, tmp_path: Path, output_format: str):
+        with pytest.raises(OSError, match="No space left"):
+            render(_FailingFlush(), _store(tmp_path), output_format)
+
+    def test_unknown_format(self, tmp_path: Path):
+        with pytest.raises(ValueError, match="Unknown format 'yaml'"):
+            render(io.StringIO(), _store(tmp_path), "yaml")
+
+    def test_stream_jsonl_short_writes(self, tmp_path: Path):
+        _sources(tmp_path)
+        expected = io.StringIO()
+        stream_jsonl(expected, tmp_path, Config(workers=1))
+        out = _ShortWrites(limit=5)
+
+        stream_jsonl(out, tmp_path, Config(workers=1))
+
+        assert out.getvalue() == expected.getvalue()
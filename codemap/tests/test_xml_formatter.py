@@ -0,0 +1,104 @@
+"""Tests for the XML export formatter."""
+
+import shutil
+import xml.etree.ElementTree as ET
+from pathlib import Path
+
+import pytest
+
+from codemap.core.map_store import MapStore
+from codemap.formatters.json_formatter import build_document
+from codemap.formatters.xml_formatter import NAMESPACE, format_xml, load_xml
+from codemap.parsers.base import Field, Note, Param
+
+from .factories import add_file, go_symbol, make_store, make_symbol
+
+FIXTURES = Path(__file__).parent / "fixtures"
+
+NS = {"c": NAMESPACE}
+
+
+def _store(tmp_path: Path) -> MapStore:
+    symbol = go_symbol(
+        "Parse",
+        lines=(3, 9),
+        columns=(1, 2),
+        signature='(s string, opts ...Option) (map[string]<-chan T, error)',
+        docstring='Parse reads "a < b && c > d".\n\nIt never\x0bfails.',
+        deprecated=True,
+        deprecation="Use Load & co.",
+        params=[Param(name="s", type="string"), Param(name="opts", type="...Option", variadic=True)],
+        results=[Param(name=None, type="map[string]<-chan T"), Param(name=None, type="error")],
+        metadata={"owner": "team-a", "score": [1, 2.5, None]},
+        children=[make_symbol("inner", lines=(4, 5), children=[make_symbol("deepest", lines=(5, 5))])],
+    )
+    config = go_symbol(
+        "Config", "struct", lines=(11, 14), embeds=["*Base"],
+        fields=[Field(name="Name", type="string", tag='json:"name,omitempty"', lines=(12, 12), columns=(2, 30))],
+    )
+    store = make_store(
+        tmp_path, {"pkg/parse.go": [symbol, config]}, lines=14, package="pkg", package_doc="Package pkg parses.",
+        notes=[Note(kind="TODO", text="handle <html> & co", author="ann", line=7, column=2)],
+    )
+    store.set_metadata(str(tmp_path), {})
+    add_file(store, "big/gen.go", lines=900, package="big")
+    store.get_file("big/gen.go").collapsed = {"function": 12, "method": 340}
+    return store
+
+
+class TestXmlFormatter:
+    """Tests for format_xml and load_xml."""
+
+    def test_round_trip(self, tmp_path: Path):
+        store = _store(tmp_path)
+
+        expected = build_document(store)
+        expected["packages"][1]["symbols"][0]["docstring"] = 'Parse reads "a < b && c > d".\n\nIt never�fails.'
+
+        assert load_xml(format_xml(store)) == expected
+
+    def test_round_trip_go_fixture(self, tmp_path: Path):
+        pytest.importorskip("tree_sitter_go")
+        from codemap.core.indexer import Indexer
+
+        shutil.copy(FIXTURES / "sample_module.go", tmp_path / "sample_module.go")
+        Indexer(root=tmp_path, languages=["go"]).index_all()
+        store = MapStore.load(tmp_path)
+
+        assert load_xml(format_xml(store, method_sets=True)) == build_document(store, method_sets=True)
+
+    def test_well_formed_with_namespace(self, tmp_path: Path):
+        text = format_xml(_store(tmp_path))
+
+        assert text.startswith('<?xml version="1.0" encoding="UTF-8"?>\n<codemap xmlns="urn:codemap:export"')
+        root = ET.fromstring(text)
+        assert root.tag == f"{{{NAMESPACE}}}codemap"
+        assert root.get("version") == "1"
+
+    def test_flags_are_attributes(self, tmp_path: Path):
+        root = ET.fromstring(format_xml(_store(tmp_path)))
+
+        parse = root.find("c:packages/c:package[@path='pkg']/c:symbols/c:symbol[@name='Parse']", NS)
+        assert parse.get("exported") == "true"
+        assert parse.get("deprecated") == "true"
+        assert parse.get("in_test") is None
+        assert parse.find("c:lines", NS).attrib == {"start": "3", "end": "9"}
+        assert parse.find("c:signature", NS).text == "(s string, opts ...Option) (map[string]<-chan T, error)"
+        variadic = parse.findall("c:params/c:param", NS)[1]
+        assert variadic.attrib == {"name": "opts", "type": "...Option", "variadic": "true"}
+        assert parse.find("c:children/c:symbol/c:children/c:symbol", NS).get("name") == "deepest"
+        field = root.find(".//c:symbol[@name='Config']/c:fields/c:field", NS)
+        assert field.get("tag") == 'json:"name,omitempty"'
+
+    def test_escapes_markup(self, tmp_path: Path):
+        text = format_xml(_store(tmp_path))
+
+        assert "&lt;-chan T" in text
+        assert "a &lt; b &amp;&amp; c &gt; d" in text
+        assert 'tag="json:&quot;name,omitempty&quot;"' in text
+
+    def test_rejects_other_documents(self):
+        with pytest.raises(ValueError):
+            load_xml("<codemap>")
+        with pytest.raises(ValueError):
+            load_xml("<other/>")
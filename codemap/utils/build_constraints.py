@@ -0,0 +1,255 @@
+"""Evaluation of Go build constraints.
+
+Follows the rules of "go help buildconstraint": a file is excluded when its
+name ends in a GOOS/GOARCH suffix ("_windows.go", "_linux_arm64_test.go")
+for another target, or when a "//go:build" line (or legacy "// +build"
+lines, if there is no "//go:build") in its header evaluates to false.
+Both checks apply, so "net_linux.go" with "//go:build arm64" builds only
+on linux/arm64.
+"""
+
+from __future__ import annotations
+
+import os
+import platform
+import re
+import sys
+from dataclasses import dataclass, field
+from pathlib import Path
+from typing import Callable, Optional
+
+from .config import Config
+
+# From go/build/syslist.go
+KNOWN_OS = {
+    "aix", "android", "darwin", "dragonfly", "freebsd", "hurd", "illumos", "ios", "js",
+    "linux", "nacl", "netbsd", "openbsd", "plan9", "solaris", "wasip1", "windows", "zos",
+}
+UNIX_OS = {
+    "aix", "android", "darwin", "dragonfly", "freebsd", "hurd", "illumos", "ios",
+    "linux", "netbsd", "openbsd", "solaris",
+}
+KNOWN_ARCH = {
+    "386", "amd64", "amd64p32", "arm", "armbe", "arm64", "arm64be", "loong64", "mips",
+    "mipsle", "mips64", "mips64le", "mips64p32", "mips64p32le", "ppc", "ppc64", "ppc64le",
+    "riscv", "riscv64", "s390", "s390x", "sparc", "sparc64", "wasm",
+}
+
+# Header bytes read when looking for constraints; they must precede the package clause
+HEADER_SIZE = 8192
+
+_TOKEN_RE = re.compile(r"\s*(&&|\|\||!|\(|\)|[\w.]+)")
+
+
+@dataclass
+class BuildContext:
+    """Target platform and tags that files are built for, like go/build.Context."""
+
+    goos: str
+    goarch: str
+    tags: list[str] = field(default_factory=list)
+
+    @classmethod
+    def from_config(cls, config: Config) -> "BuildContext":
+        """Create the context of a config.
+
+        GOOS and GOARCH not set in the config are taken from the environment,
+        falling back to the host platform like the go tool.
+        """
+        return cls(
+            goos=config.goos or os.environ.get("GOOS") or _host_os(),
+            goarch=config.goarch or os.environ.get("GOARCH") or _host_arch(),
+            tags=list(config.build_tags),
+        )
+
+    def match_tag(self, tag: str) -> bool:
+        """Check if a single build tag is satisfied.
+
+        Release tags ("go1.21") are always satisfied, as if built with the
+        newest toolchain.
+        """
+        if tag in self.tags or tag in (self.goos, self.goarch):
+            return True
+        if tag == "unix":
+            return self.goos in UNIX_OS
+        if tag == "linux" and self.goos == "android":
+            return True
+        if tag == "solaris" and self.goos == "illumos":
+            return True
+        if tag == "darwin" and self.goos == "ios":
+            return True
+        return re.fullmatch(r"go1(\.\d+)?", tag) is not None
+
+    def skip_reason(self, filepath: Path) -> Optional[str]:
+        """Explain why a Go file is excluded from the build, or return None.
+
+        Args:
+            filepath: Path to a .go file.
+
+        Returns:
+            A reason such as "GOOS/GOARCH suffix _windows" or
+            "build constraint: linux && cgo", or None if the file builds.
+        """
+        suffix = self._bad_suffix(filepath.name)
+        if suffix is not None:
+            return f"GOOS/GOARCH suffix {suffix}"
+        try:
+            with open(filepath, "rb") as f:
+                header = f.read(HEADER_SIZE).decode("utf-8", errors="replace")
+        except OSError:
+            return None
+        expression = _constraint(header)
+        if expression is None:
+            return None
+        try:
+            satisfied = evaluate(expression, self.match_tag)
+        except ValueError:
+            return f"invalid build constraint: {expression}"
+        return None if satisfied else f"build constraint: {expression}"
+
+    def _bad_suffix(self, filename: str) -> Optional[str]:
+        """Return the GOOS/GOARCH file name suffix that excludes a file, if any."""
+        name = filename.split(".", 1)[0]
+        if "_" not in name:
+            return None
+        # Everything before the first "_" is ignored, so "linux.go" has no constraint
+        parts = name[name.index("_"):].split("_")
+        if parts[-1] == "test":
+            parts = parts[:-1]
+        if len(parts) >= 2 and parts[-2] in KNOWN_OS and parts[-1] in KNOWN_ARCH:
+            if not (self.match_tag(parts[-2]) and self.match_tag(parts[-1])):
+                return f"_{parts[-2]}_{parts[-1]}"
+            return None
+        if parts and (parts[-1] in KNOWN_OS or parts[-1] in KNOWN_ARCH):
+            if not self.match_tag(parts[-1]):
+                return f"_{parts[-1]}"
+        return None
+
+
+def evaluate(expression: str, match_tag: Callable[[str], bool]) -> bool:
+    """Evaluate a "//go:build" expression such as "linux && (amd64 || arm64)".
+
+    Args:
+        expression: Constraint expression without the "//go:build" prefix.
+        match_tag: Called with each tag, returning whether it is satisfied.
+
+    Raises:
+        ValueError: If the expression is malformed.
+    """
+    tokens = _tokenize(expression)
+    pos = 0
+
+    def peek() -> Optional[str]:
+        return tokens[pos] if pos < len(tokens) else None
+
+    def take() -> str:
+        nonlocal pos
+        if pos >= len(tokens):
+            raise ValueError(f"unexpected end of build constraint: {expression}")
+        pos += 1
+        return tokens[pos - 1]
+
+    def parse_or() -> bool:
+        result = parse_and()
+        while peek() == "||":
+            take()
+            result = parse_and() or result
+        return result
+
+    def parse_and() -> bool:
+        result = parse_not()
+        while peek() == "&&":
+            take()
+            result = parse_not() and result
+        return result
+
+    def parse_not() -> bool:
+        token = take()
+        if token == "!":
+            return not parse_not()
+        if token == "(":
+            result = parse_or()
+            if take() != ")":
+                raise ValueError(f"missing ) in build constraint: {expression}")
+            return result
+        if token in ("&&", "||", ")"):
+            raise ValueError(f"unexpected {token} in build constraint: {expression}")
+        return match_tag(token)
+
+    result = parse_or()
+    if pos != len(tokens):
+        raise ValueError(f"unexpected {tokens[pos]} in build constraint: {expression}")
+    return result
+
+
+def _tokenize(expression: str) -> list[str]:
+    tokens = []
+    pos = 0
+    expression = expression.rstrip()
+    while pos < len(expression):
+        match = _TOKEN_RE.match(expression, pos)
+        if match is None:
+            raise ValueError(f"invalid build constraint: {expression}")
+        tokens.append(match.group(1))
+        pos = match.end()
+    return tokens
+
+
+def _constraint(header: str) -> Optional[str]:
+    """Find the build constraint of a file header as a "//go:build" expression.
+
+    Only comments before the package clause that are followed by a blank
+    line count, so a constraint can't be mistaken for a doc comment.
+    Legacy "// +build" lines are converted when there is no "//go:build".
+    """
+    lines: list[str] = []
+    in_block = False
+    for line in header.splitlines():
+        stripped = line.strip()
+        if in_block:
+            in_block = "*/" not in stripped
+            lines.append("")
+            continue
+        if stripped.startswith("/*"):
+            in_block = "*/" not in stripped[2:]
+            lines.append("")
+            continue
+        if stripped and not stripped.startswith("//"):
+            break
+        lines.append(stripped)
+    # Drop the comment block directly above the package clause
+    while lines and lines[-1]:
+        lines.pop()
+
+    plus_build: list[str] = []
+    for line in lines:
+        if line.startswith("//go:build") and line[len("//go:build"):][:1] in ("", " ", "\t"):
+            return line[len("//go:build"):].strip()
+        if line.startswith("// +build") or line.startswith("//+build"):
+            plus_build.append(line.split("+build", 1)[1].strip())
+    if not plus_build:
+        return None
+    # "// +build a,b c" means (a && b) || c; separate lines are ANDed
+    clauses = []
+    for line in plus_build:
+        options = [" && ".join(term.split(",")) for term in line.split()]
+        clauses.append(options[0] if len(options) == 1 else "(" + " || ".join(options) + ")")
+    return " && ".join(clauses)
+
+
+def _host_os() -> str:
+    if sys.platform.startswith("win"):
+        return "windows"
+    for goos in ("darwin", "freebsd", "openbsd", "netbsd", "aix"):
+        if sys.platform.startswith(goos):
+            return goos
+    return "linux"
+
+
+def _host_arch() -> str:
+    machine = platform.machine().lower()
+    return {
+        "x86_64": "amd64", "amd64": "amd64", "aarch64": "arm64", "arm64": "arm64",
+        "i386": "386", "i686": "386", "armv7l": "arm", "ppc64le": "ppc64le", "s390x": "s390x",
+        "riscv64": "riscv64",
+    }.get(machine, machine)
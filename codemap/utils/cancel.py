@@ -0,0 +1,90 @@
+"""Cancellation of long-running indexing, in the manner of Go's context.Context.
+
+A CancelToken is passed to the entry points that walk and parse a tree
+(Indexer.index_all, Indexer.index_files, stream_jsonl, load_package). They
+check it between directories and between files, and stop waiting for the
+parse pool as soon as it fires, raising Cancelled, or DeadlineExceeded when
+the token's timeout ran out. Nothing is saved when indexing is cancelled.
+
+    token = CancelToken(timeout=30)
+    threading.Timer(5, token.cancel).start()  # e.g. the client went away
+    try:
+        Indexer(root).index_all(cancel=token)
+    except Cancelled as e:
+        ...
+
+A read that hangs, for example on a stalled network mount, can't be
+interrupted: the file being read in the calling process finishes first,
+and a worker process stuck in one is terminated rather than waited for.
+"""
+
+from __future__ import annotations
+
+import threading
+import time
+from typing import Optional
+
+
+class Cancelled(Exception):
+    """Indexing stopped because its CancelToken was cancelled."""
+
+
+class DeadlineExceeded(Cancelled):
+    """Indexing stopped because its CancelToken's timeout ran out."""
+
+
+class CancelToken:
+    """A flag that stops indexing when set, or when its deadline passes.
+
+    Tokens can be cancelled from any thread, and cancelling twice is harmless.
+    """
+
+    def __init__(self, timeout: Optional[float] = None):
+        """Create a token.
+
+        Args:
+            timeout: Seconds from now after which the token counts as
+                cancelled, or None for no deadline.
+        """
+        self._event = threading.Event()
+        self.deadline = time.monotonic() + timeout if timeout is not None else None  # time.monotonic() value
+
+    def cancel(self) -> None:
+        """Cancel everything checking this token."""
+        self._event.set()
+
+    @property
+    def cancelled(self) -> bool:
+        """Whether the token was cancelled or its deadline passed."""
+        return self.err() is not None
+
+    def remaining(self) -> Optional[float]:
+        """Seconds left until the deadline (0 once passed), or None without one."""
+        if self.deadline is None:
+            return None
+        return max(0.0, self.deadline - time.monotonic())
+
+    def err(self) -> Optional[Cancelled]:
+        """The error to stop with, like ctx.Err(), or None while not cancelled."""
+        if self._event.is_set():
+            return Cancelled("indexing was cancelled")
+        if self.deadline is not None and time.monotonic() >= self.deadline:
+            return DeadlineExceeded("indexing ran past its deadline")
+        return None
+
+    def check(self) -> None:
+        """Raise the token's error if it was cancelled or its deadline passed.
+
+        Raises:
+            Cancelled: If cancel() was called.
+            DeadlineExceeded: If the timeout ran out.
+        """
+        error = self.err()
+        if error is not None:
+            raise error
+
+
+def check(cancel: Optional[CancelToken]) -> None:
+    """Raise if cancel is set and cancelled; a None token never is."""
+    if cancel is not None:
+        cancel.check()
@@ -9,7 +9,8 @@ from typing import Optional
 import yaml
 
 
-# Default patterns
+# Include patterns older versions wrote to .codemaprc without applying them;
+# loading them is the same as having no include patterns
 DEFAULT_INCLUDE_PATTERNS = [
     "**/*.py",
     "**/*.ts",
@@ -35,6 +36,7 @@ DEFAULT_INCLUDE_PATTERNS = [
     "**/*.css",
     "**/*.php",
     "**/*.phtml",
+    "**/*.go",
 ]
 
 DEFAULT_EXCLUDE_PATTERNS = [
@@ -50,6 +52,7 @@ DEFAULT_EXCLUDE_PATTERNS = [
     "**/.tox/**",
     "**/.eggs/**",
     "**/*.egg-info/**",
+    "**/testdata/**",
 ]
 
 
@@ -57,11 +60,36 @@ DEFAULT_EXCLUDE_PATTERNS = [
 class Config:
     """CodeMap configuration."""
 
-    languages: list[str] = field(default_factory=lambda: ["python", "typescript", "javascript", "markdown", "yaml", "kotlin", "swift", "c", "cpp", "html", "css", "php"])
+    languages: list[str] = field(default_factory=lambda: ["python", "typescript", "javascript", "markdown", "yaml", "kotlin", "swift", "c", "cpp", "html", "css", "php", "go"])
     exclude_patterns: list[str] = field(default_factory=lambda: DEFAULT_EXCLUDE_PATTERNS.copy())
-    include_patterns: list[str] = field(default_factory=lambda: DEFAULT_INCLUDE_PATTERNS.copy())
+    include_patterns: list[str] = field(default_factory=list)  # Empty includes every file
+    max_depth: Optional[int] = None  # Deepest directory level to index; 0 is the root only, None has no limit
     max_docstring_length: int = 150
     output: str = ".codemap.json"
+    respect_gitignore: bool = True  # Skip files matched by .gitignore files
+    include_vendor: bool = False  # Index vendor/ and node_modules/
+    include_tests: bool = False  # Index Go _test.go files
+    goos: Optional[str] = None  # Target GOOS for Go build constraints; None uses $GOOS or the host
+    goarch: Optional[str] = None  # Target GOARCH; None uses $GOARCH or the host
+    build_tags: list[str] = field(default_factory=list)  # Extra Go build tags, like "go build -tags"
+    exclude_generated: bool = False  # Skip Go files marked "// Code generated ... DO NOT EDIT."
+    ignore_file: Optional[str] = None  # Extra ignore file, relative to the root
+    workers: Optional[int] = None  # Parser processes; None uses one per CPU
+    cache_dir: Optional[str] = None  # Parse cache directory, relative to the root; None disables it
+    notes: bool = False  # Record TODO/FIXME/XXX/HACK comments of Go files
+    opaque: list[str] = field(default_factory=list)  # Go package prefixes (directories or import paths) indexed without symbols
+    exported_only: bool = False  # Export only exported (public) symbols
+    exclude_deprecated: bool = False  # Leave deprecated symbols out of exports
+    collapse_over: Optional[int] = None  # Export files with more symbols as counts per type
+    min_complexity: Optional[int] = None  # Export only functions and methods at least this complex
+    sort: str = "source"  # Symbol order in exports: source, alpha, grouped or complexity
+    doc_first_sentence: bool = False  # Export only the first sentence of doc comments
+    doc_max_chars: Optional[int] = None  # Cut exported doc comments to this many characters
+    doc_max_lines: Optional[int] = None  # Cut exported doc comments to this many lines
+    doc_strip_name: bool = False  # Drop the leading symbol name from exported doc comments
+    max_tokens: Optional[int] = None  # Token budget for exports
+    tokenizer: str = "default"  # Tokenizer used for token estimates
+    token_reductions: Optional[list[str]] = None  # Budget reduction order; None uses the default
 
     def to_dict(self) -> dict:
         """Convert config to dictionary."""
@@ -69,6 +97,31 @@ class Config:
             "languages": self.languages,
             "exclude_patterns": self.exclude_patterns,
             "include_patterns": self.include_patterns,
+            "max_depth": self.max_depth,
+            "respect_gitignore": self.respect_gitignore,
+            "include_vendor": self.include_vendor,
+            "include_tests": self.include_tests,
+            "goos": self.goos,
+            "goarch": self.goarch,
+            "build_tags": self.build_tags,
+            "exclude_generated": self.exclude_generated,
+            "ignore_file": self.ignore_file,
+            "workers": self.workers,
+            "cache_dir": self.cache_dir,
+            "notes": self.notes,
+            "opaque": self.opaque,
+            "exported_only": self.exported_only,
+            "exclude_deprecated": self.exclude_deprecated,
+            "collapse_over": self.collapse_over,
+            "min_complexity": self.min_complexity,
+            "sort": self.sort,
+            "doc_first_sentence": self.doc_first_sentence,
+            "doc_max_chars": self.doc_max_chars,
+            "doc_max_lines": self.doc_max_lines,
+            "doc_strip_name": self.doc_strip_name,
+            "max_tokens": self.max_tokens,
+            "tokenizer": self.tokenizer,
+            "token_reductions": self.token_reductions,
         }
 
     @classmethod
@@ -77,9 +130,34 @@ class Config:
         return cls(
             languages=data.get("languages", ["python", "typescript", "javascript"]),
             exclude_patterns=data.get("exclude_patterns", DEFAULT_EXCLUDE_PATTERNS.copy()),
-            include_patterns=data.get("include_patterns", DEFAULT_INCLUDE_PATTERNS.copy()),
+            include_patterns=_include_patterns(data.get("include_patterns")),
+            max_depth=data.get("max_depth"),
             max_docstring_length=data.get("max_docstring_length", 150),
             output=data.get("output", ".codemap.json"),
+            respect_gitignore=data.get("respect_gitignore", True),
+            include_vendor=data.get("include_vendor", False),
+            include_tests=data.get("include_tests", False),
+            goos=data.get("goos"),
+            goarch=data.get("goarch"),
+            build_tags=data.get("build_tags", []),
+            exclude_generated=data.get("exclude_generated", False),
+            ignore_file=data.get("ignore_file"),
+            workers=data.get("workers"),
+            cache_dir=data.get("cache_dir"),
+            notes=data.get("notes", False),
+            opaque=data.get("opaque", []),
+            exported_only=data.get("exported_only", False),
+            exclude_deprecated=data.get("exclude_deprecated", False),
+            collapse_over=data.get("collapse_over"),
+            min_complexity=data.get("min_complexity"),
+            sort=data.get("sort", "source"),
+            doc_first_sentence=data.get("doc_first_sentence", False),
+            doc_max_chars=data.get("doc_max_chars"),
+            doc_max_lines=data.get("doc_max_lines"),
+            doc_strip_name=data.get("doc_strip_name", False),
+            max_tokens=data.get("max_tokens"),
+            tokenizer=data.get("tokenizer", "default"),
+            token_reductions=data.get("token_reductions"),
         )
 
 
@@ -114,14 +192,45 @@ def _load_yaml_config(path: Path) -> Config:
         return Config(
             languages=data.get("languages", ["python", "typescript", "javascript"]),
             exclude_patterns=data.get("exclude", DEFAULT_EXCLUDE_PATTERNS.copy()),
-            include_patterns=data.get("include", DEFAULT_INCLUDE_PATTERNS.copy()),
+            include_patterns=_include_patterns(data.get("include")),
+            max_depth=data.get("max_depth"),
             max_docstring_length=data.get("max_docstring_length", 150),
             output=data.get("output", ".codemap.json"),
+            respect_gitignore=data.get("gitignore", True),
+            include_vendor=data.get("vendor", False),
+            include_tests=data.get("tests", False),
+            goos=data.get("goos"),
+            goarch=data.get("goarch"),
+            build_tags=data.get("tags", []),
+            exclude_generated=data.get("exclude_generated", False),
+            ignore_file=data.get("ignore_file"),
+            workers=data.get("workers"),
+            cache_dir=data.get("cache"),
+            notes=data.get("notes", False),
+            opaque=data.get("opaque", []),
+            exported_only=data.get("exported_only", False),
+            exclude_deprecated=data.get("exclude_deprecated", False),
+            collapse_over=data.get("collapse_over"),
+            min_complexity=data.get("min_complexity"),
+            sort=data.get("sort", "source"),
+            doc_first_sentence=data.get("doc_first_sentence", False),
+            doc_max_chars=data.get("doc_max_chars"),
+            doc_max_lines=data.get("doc_max_lines"),
+            doc_strip_name=data.get("doc_strip_name", False),
+            max_tokens=data.get("max_tokens"),
+            tokenizer=data.get("tokenizer", "default"),
+            token_reductions=data.get("token_reductions"),
         )
     except Exception:
         return Config()
 
 
+def _include_patterns(patterns: Optional[list[str]]) -> list[str]:
+    if not patterns or patterns == DEFAULT_INCLUDE_PATTERNS:
+        return []
+    return list(patterns)
+
+
 def save_config(config: Config, root: Path) -> None:
     """Save configuration to .codemaprc file.
 
@@ -133,9 +242,58 @@ def save_config(config: Config, root: Path) -> None:
     data = {
         "languages": config.languages,
         "exclude": config.exclude_patterns,
-        "include": config.include_patterns,
         "max_docstring_length": config.max_docstring_length,
         "output": config.output,
+        "gitignore": config.respect_gitignore,
+        "vendor": config.include_vendor,
     }
+    if config.include_patterns:
+        data["include"] = config.include_patterns
+    if config.max_depth is not None:
+        data["max_depth"] = config.max_depth
+    if config.include_tests:
+        data["tests"] = True
+    if config.goos:
+        data["goos"] = config.goos
+    if config.goarch:
+        data["goarch"] = config.goarch
+    if config.build_tags:
+        data["tags"] = config.build_tags
+    if config.exclude_generated:
+        data["exclude_generated"] = True
+    if config.ignore_file:
+        data["ignore_file"] = config.ignore_file
+    if config.workers:
+        data["workers"] = config.workers
+    if config.cache_dir:
+        data["cache"] = config.cache_dir
+    if config.notes:
+        data["notes"] = True
+    if config.opaque:
+        data["opaque"] = config.opaque
+    if config.exported_only:
+        data["exported_only"] = True
+    if config.exclude_deprecated:
+        data["exclude_deprecated"] = True
+    if config.collapse_over:
+        data["collapse_over"] = config.collapse_over
+    if config.min_complexity is not None:
+        data["min_complexity"] = config.min_complexity
+    if config.sort != "source":
+        data["sort"] = config.sort
+    if config.doc_first_sentence:
+        data["doc_first_sentence"] = True
+    if config.doc_max_chars is not None:
+        data["doc_max_chars"] = config.doc_max_chars
+    if config.doc_max_lines is not None:
+        data["doc_max_lines"] = config.doc_max_lines
+    if config.doc_strip_name:
+        data["doc_strip_name"] = True
+    if config.max_tokens:
+        data["max_tokens"] = config.max_tokens
+    if config.tokenizer != "default":
+        data["tokenizer"] = config.tokenizer
+    if config.token_reductions is not None:
+        data["token_reductions"] = config.token_reductions
     with open(config_path, "w", encoding="utf-8") as f:
         yaml.safe_dump(data, f, default_flow_style=False, sort_keys=False)
@@ -2,17 +2,31 @@
 
 from __future__ import annotations
 
-import fnmatch
-from pathlib import Path
-from typing import Iterator
+import functools
+import os
+import re
+from pathlib import Path, PurePosixPath
+from typing import Iterable, Iterator, Optional
 
+from .cancel import CancelToken, check
 from .config import Config, DEFAULT_EXCLUDE_PATTERNS
+from .gitignore import IgnoreRules, is_ignored
+
+# Third-party code directories, skipped unless Config.include_vendor is set
+VENDOR_DIRS = ("vendor", "node_modules")
+
+# The go tool's marker for generated files, e.g. "// Code generated by protoc-gen-go. DO NOT EDIT."
+GENERATED_RE = re.compile(r"^// Code generated .* DO NOT EDIT\.$")
+
+# Directory names that are always excluded
+_EXCLUDED_DIRS = ("__pycache__", ".venv", "venv", "dist", "build", ".git")
 
 
 def discover_files(
     root: Path,
     config: Config | None = None,
     languages: list[str] | None = None,
+    cancel: Optional[CancelToken] = None,
 ) -> Iterator[Path]:
     """Discover files to index.
 
@@ -20,9 +34,13 @@ def discover_files(
         root: Root directory to scan.
         config: Optional Config object with include/exclude patterns.
         languages: Optional list of languages to filter by.
+        cancel: Optional token checked before each directory is read.
 
     Yields:
         Path objects for files to index.
+
+    Raises:
+        Cancelled: If cancel fires during the walk.
     """
     if config is None:
         config = Config()
@@ -30,35 +48,181 @@ def discover_files(
     # Determine extensions based on languages
     extensions = _get_extensions_for_languages(languages or config.languages)
 
-    for path in root.rglob("*"):
-        if not path.is_file():
-            continue
+    exclude_patterns = config.exclude_patterns
+    if config.include_vendor:
+        exclude_patterns = [p for p in exclude_patterns if not any(f"/{d}/" in p for d in VENDOR_DIRS)]
 
+    rule_sets = _ignore_file_rules(root, config)
+    for path, rel_str in _walk(root, "", rule_sets, config, exclude_patterns, 0, cancel):
         # Check extension
         if not any(path.suffix == ext for ext in extensions):
             continue
 
-        # Get relative path for pattern matching
-        try:
-            rel_path = path.relative_to(root)
-        except ValueError:
+        # Check include and exclude patterns; excludes win
+        if not is_included(rel_str, config.include_patterns):
+            continue
+        if should_exclude(rel_str, exclude_patterns, include_vendor=config.include_vendor):
             continue
 
-        rel_str = str(rel_path)
-
-        # Check exclude patterns
-        if should_exclude(rel_str, config.exclude_patterns):
+        if not config.include_tests and is_go_test_file(rel_str):
             continue
 
         yield path
 
 
-def should_exclude(filepath: str, patterns: list[str] | None = None) -> bool:
+def _ignore_file_rules(root: Path, config: Config) -> list[IgnoreRules]:
+    """Load the rules of config.ignore_file, which apply below every .gitignore."""
+    if not config.ignore_file:
+        return []
+    ignore_path = Path(config.ignore_file)
+    return [IgnoreRules.from_file(ignore_path if ignore_path.is_absolute() else root / ignore_path)]
+
+
+def is_ignored_path(root: Path, rel_path: str, config: Config) -> bool:
+    """Check whether discover_files skips a file because of ignore files.
+
+    Applies config.ignore_file and, with config.respect_gitignore, the
+    .gitignore files from the root down to the file's directory, to the
+    file and to each directory above it, as the walk does.
+
+    Args:
+        root: Project root.
+        rel_path: File path relative to the root, using "/" separators.
+        config: Config with the gitignore and ignore_file options.
+    """
+    rule_sets = _ignore_file_rules(root, config)
+    parts = rel_path.split("/")
+    rel_dir = ""
+    for i, name in enumerate(parts):
+        gitignore = root / rel_dir / ".gitignore"
+        if config.respect_gitignore and gitignore.is_file():
+            rule_sets.append(IgnoreRules.from_file(gitignore, rel_dir))
+        path = f"{rel_dir}/{name}" if rel_dir else name
+        if is_ignored(rule_sets, path, is_dir=i < len(parts) - 1):
+            return True
+        rel_dir = path
+    return False
+
+
+def _walk(
+    directory: Path,
+    rel_dir: str,
+    rule_sets: list[IgnoreRules],
+    config: Config,
+    exclude_patterns: list[str] = (),
+    depth: int = 0,
+    cancel: Optional[CancelToken] = None,
+) -> Iterator[tuple[Path, str]]:
+    """Walk a directory tree, pruning ignored and excluded directories before descending.
+
+    Args:
+        directory: Directory to walk.
+        rel_dir: The directory relative to the project root ("" for the root).
+        rule_sets: Ignore rules in effect, lowest precedence first.
+        config: Config with the gitignore, vendor and max_depth options.
+        exclude_patterns: Globs excluding a directory with everything in it.
+        depth: Level of the directory below the root (0 for the root).
+            Subdirectories past config.max_depth are not read at all.
+        cancel: Optional token checked before each directory is read.
+
+    Yields:
+        (path, relative path with "/" separators) for each file that isn't ignored.
+    """
+    check(cancel)
+    if config.respect_gitignore and (directory / ".gitignore").is_file():
+        rule_sets = rule_sets + [IgnoreRules.from_file(directory / ".gitignore", rel_dir)]
+
+    try:
+        entries = sorted(os.scandir(directory), key=lambda e: e.name)
+    except OSError:
+        return
+
+    for entry in entries:
+        rel_path = f"{rel_dir}/{entry.name}" if rel_dir else entry.name
+        if entry.is_dir(follow_symlinks=False):
+            if config.max_depth is not None and depth >= config.max_depth:
+                continue
+            if entry.name == ".git" or (not config.include_vendor and entry.name in VENDOR_DIRS):
+                continue
+            if is_ignored(rule_sets, rel_path, is_dir=True) or matches_any(rel_path, exclude_patterns):
+                continue
+            yield from _walk(Path(entry.path), rel_path, rule_sets, config, exclude_patterns, depth + 1, cancel)
+        elif entry.is_file() and not is_ignored(rule_sets, rel_path, is_dir=False):
+            yield Path(entry.path), rel_path
+
+
+def within_depth(filepath: str, max_depth: Optional[int]) -> bool:
+    """Check if a relative file path is at most max_depth directories below the root.
+
+    "main.go" is at depth 0 and "cmd/app/main.go" at depth 2; a max_depth
+    of None allows any depth.
+    """
+    return max_depth is None or len(Path(filepath).parts) - 1 <= max_depth
+
+
+def module_path(filepath: str) -> str:
+    """Dotted module path of a relative file path, e.g. "codemap/cli.py" -> "codemap.cli"."""
+    return ".".join(PurePosixPath(filepath).with_suffix("").parts)
+
+
+def is_go_test_file(filepath: str) -> bool:
+    """Check if a path is a Go test file (name ending in _test.go)."""
+    return filepath.endswith("_test.go")
+
+
+def is_editor_temp_file(filepath: str) -> bool:
+    """Check if a path is an editor's swap, backup or lock file, e.g. ".#main.go" or "main.go~"."""
+    name = filepath.replace("\\", "/").rsplit("/", 1)[-1]
+    return (
+        name.startswith(".#")  # Emacs lock files
+        or (name.startswith("#") and name.endswith("#"))  # Emacs auto-saves
+        or name.endswith("~")
+        or name.endswith((".swp", ".swo", ".swx", ".tmp", "___jb_tmp___", "___jb_old___"))
+    )
+
+
+def is_generated(source: str) -> bool:
+    """Check if Go source has a "Code generated ... DO NOT EDIT." marker.
+
+    Like go/ast.IsGenerated, only comment lines before the package clause
+    are checked.
+    """
+    in_block = False
+    for line in source.splitlines():
+        stripped = line.strip()
+        if in_block:
+            in_block = "*/" not in stripped
+        elif stripped.startswith("/*"):
+            in_block = "*/" not in stripped[2:]
+        elif GENERATED_RE.match(line.rstrip("\r")):
+            return True
+        elif stripped and not stripped.startswith("//"):
+            return False
+    return False
+
+
+def is_generated_file(path: Path, size: int = 8192) -> bool:
+    """Check the start of a Go file for the generated-code marker."""
+    try:
+        with open(path, "rb") as f:
+            header = f.read(size).decode("utf-8", errors="replace")
+    except OSError:
+        return False
+    return is_generated(header)
+
+
+def should_exclude(
+    filepath: str,
+    patterns: list[str] | None = None,
+    include_vendor: bool = False,
+) -> bool:
     """Check if a file should be excluded based on patterns.
 
     Args:
         filepath: Relative file path to check.
-        patterns: List of glob patterns to match against.
+        patterns: Globs to match against, see glob_match. A pattern matching
+            one of the file's directories excludes the file too.
+        include_vendor: Don't exclude files in vendor/ and node_modules/.
 
     Returns:
         True if the file should be excluded.
@@ -66,22 +230,99 @@ def should_exclude(filepath: str, patterns: list[str] | None = None) -> bool:
     if patterns is None:
         patterns = DEFAULT_EXCLUDE_PATTERNS
 
-    for pattern in patterns:
-        if fnmatch.fnmatch(filepath, pattern):
-            return True
-        # Also check if any parent directory matches
-        if "**" in pattern:
-            # Handle ** patterns
-            simple_pattern = pattern.replace("**", "*")
-            if fnmatch.fnmatch(filepath, simple_pattern):
-                return True
-        # Check path components
-        parts = filepath.split("/")
-        for part in parts:
-            # Check if directory name matches common excludes
-            if part in ("node_modules", "__pycache__", ".venv", "venv", "dist", "build", ".git"):
-                return True
-    return False
+    filepath = filepath.replace("\\", "/")
+    parts = filepath.split("/")
+    if not include_vendor and any(part in VENDOR_DIRS for part in parts[:-1]):
+        return True
+    if any(part in _EXCLUDED_DIRS for part in parts):
+        return True
+    return _matches_path_or_parent(filepath, patterns)
+
+
+def is_included(filepath: str, patterns: list[str] | None) -> bool:
+    """Check if a file is selected by include globs; with none, every file is.
+
+    A pattern matching one of the file's directories ("src" or "src/**")
+    includes everything under it.
+    """
+    if not patterns:
+        return True
+    return _matches_path_or_parent(filepath.replace("\\", "/"), patterns)
+
+
+def matches_any(path: str, patterns: Iterable[str]) -> bool:
+    """Check if a relative path matches any of the globs."""
+    return any(glob_match(path, pattern) for pattern in patterns)
+
+
+def glob_match(path: str, pattern: str) -> bool:
+    """Match a relative path against a doublestar glob.
+
+    "*" matches within one path element and "**" across any number of them,
+    so "**/*_gen.go" matches "x_gen.go" and "a/b/x_gen.go" while "*.go" only
+    matches files in the root. "?", "[abc]" and "{gen,mock}" work as in
+    shells. Patterns match the whole path, relative to the root, with "/" separators.
+    """
+    return _glob_regex(pattern).fullmatch(path) is not None
+
+
+def _matches_path_or_parent(filepath: str, patterns: Iterable[str]) -> bool:
+    parts = filepath.split("/")
+    candidates = ["/".join(parts[:i]) for i in range(1, len(parts) + 1)]
+    return any(glob_match(candidate, pattern) for pattern in patterns for candidate in candidates)
+
+
+@functools.lru_cache(maxsize=256)
+def _glob_regex(pattern: str) -> re.Pattern:
+    """Translate a doublestar glob to a regular expression."""
+    out = []
+    i, n, braces = 0, len(pattern), 0
+    while i < n:
+        c = pattern[i]
+        if pattern.startswith("**", i):
+            before = i == 0 or pattern[i - 1] == "/"
+            after = i + 2 == n or pattern[i + 2] == "/"
+            if before and after and i + 2 < n:
+                out.append("(?:.*/)?")  # "**/": zero or more directories
+                i += 3
+                continue
+            if before and after and i > 0:
+                out.pop()  # "/**" at the end: the directory itself or anything below
+                out.append("(?:/.*)?")
+                i += 2
+                continue
+            out.append(".*")
+            i += 2
+            continue
+        if c == "*":
+            out.append("[^/]*")
+        elif c == "?":
+            out.append("[^/]")
+        elif c == "[":
+            end = pattern.find("]", i + 1)
+            if end == -1:
+                out.append(re.escape(c))
+            else:
+                body = pattern[i + 1:end]
+                if body.startswith("!"):
+                    body = "^" + body[1:]
+                out.append(f"[{body}]")
+                i = end
+        elif c == "{":
+            braces += 1
+            out.append("(?:")
+        elif c == "}" and braces:
+            braces -= 1
+            out.append(")")
+        elif c == "," and braces:
+            out.append("|")
+        elif c == "\\" and i + 1 < n:
+            i += 1
+            out.append(re.escape(pattern[i]))
+        else:
+            out.append(re.escape(c))
+        i += 1
+    return re.compile("".join(out))
 
 
 def _get_extensions_for_languages(languages: list[str]) -> list[str]:
@@ -106,6 +347,7 @@ def _get_extensions_for_languages(languages: list[str]) -> list[str]:
         "html": [".html", ".htm"],
         "css": [".css"],
         "php": [".php", ".phtml"],
+        "go": [".go"],
     }
 
     extensions = []
@@ -169,5 +411,6 @@ def get_language(filepath: Path) -> str | None:
         ".css": "css",
         ".php": "php",
         ".phtml": "php",
+        ".go": "go",
     }
     return extension_to_lang.get(suffix)
@@ -0,0 +1,176 @@
+"""Matching of .gitignore-style ignore rules."""
+
+from __future__ import annotations
+
+import re
+from dataclasses import dataclass
+from pathlib import Path
+from typing import Optional
+
+
+@dataclass
+class IgnoreRule:
+    """A single compiled ignore pattern."""
+
+    regex: re.Pattern
+    negated: bool = False
+    dir_only: bool = False
+
+
+class IgnoreRules:
+    """Rules from one ignore file, scoped to the directory containing it.
+
+    Supports the gitignore syntax used in practice: comments, "!" negation,
+    trailing "/" for directories, patterns anchored by a "/", and the "*",
+    "?", "[...]" and "**" wildcards.
+    """
+
+    def __init__(self, base: str = "", rules: Optional[list[IgnoreRule]] = None):
+        """Initialize the rule set.
+
+        Args:
+            base: Directory the rules apply to, relative to the project root
+                ("" for the root itself).
+            rules: Compiled rules, in file order.
+        """
+        self.base = base.strip("/")
+        self.rules = rules or []
+
+    @classmethod
+    def from_file(cls, path: Path, base: str = "") -> "IgnoreRules":
+        """Load rules from an ignore file.
+
+        Args:
+            path: Path to the ignore file.
+            base: Directory the rules apply to, relative to the project root.
+
+        Returns:
+            IgnoreRules for the file (empty if it can't be read).
+        """
+        try:
+            text = path.read_text(encoding="utf-8", errors="replace")
+        except OSError:
+            return cls(base)
+        return cls.from_lines(text.splitlines(), base)
+
+    @classmethod
+    def from_lines(cls, lines: list[str], base: str = "") -> "IgnoreRules":
+        """Compile rules from the lines of an ignore file."""
+        rules = [rule for rule in (_compile(line) for line in lines) if rule is not None]
+        return cls(base, rules)
+
+    def match(self, rel_path: str, is_dir: bool) -> Optional[bool]:
+        """Check a path against these rules.
+
+        Args:
+            rel_path: Path relative to the project root, using "/" separators.
+            is_dir: Whether the path is a directory.
+
+        Returns:
+            True if ignored, False if re-included by a negated rule, or None
+            if no rule matches (or the path is outside this rule set's base).
+        """
+        if self.base:
+            if not rel_path.startswith(self.base + "/"):
+                return None
+            rel_path = rel_path[len(self.base) + 1:]
+
+        result = None
+        for rule in self.rules:  # Last matching rule wins
+            if rule.dir_only and not is_dir:
+                continue
+            if rule.regex.match(rel_path):
+                result = not rule.negated
+        return result
+
+
+def is_ignored(rule_sets: list[IgnoreRules], rel_path: str, is_dir: bool) -> bool:
+    """Check a path against ignore files from the root down to its directory.
+
+    Deeper ignore files take precedence over the ones above them, as in git.
+
+    Args:
+        rule_sets: Rule sets ordered from lowest to highest precedence.
+        rel_path: Path relative to the project root, using "/" separators.
+        is_dir: Whether the path is a directory.
+
+    Returns:
+        True if the path is ignored.
+    """
+    for rules in reversed(rule_sets):
+        result = rules.match(rel_path, is_dir)
+        if result is not None:
+            return result
+    return False
+
+
+def _compile(line: str) -> Optional[IgnoreRule]:
+    """Compile one ignore-file line, or return None for blanks and comments."""
+    line = line.rstrip("\n")
+    if not line.strip() or line.startswith("#"):
+        return None
+    # Trailing spaces are ignored unless escaped
+    stripped = line.rstrip()
+    if stripped.endswith("\\") and len(line) > len(stripped):
+        stripped += " "
+    line = stripped
+
+    negated = line.startswith("!")
+    if negated:
+        line = line[1:]
+    elif line.startswith("\\!") or line.startswith("\\#"):
+        line = line[1:]
+
+    dir_only = line.endswith("/")
+    line = line.rstrip("/")
+    if not line:
+        return None
+
+    # A slash anywhere but the end anchors the pattern to the base directory
+    anchored = "/" in line
+    line = line.lstrip("/")
+    pattern = _translate(line)
+    if not anchored:
+        pattern = "(?:.*/)?" + pattern
+    return IgnoreRule(regex=re.compile(pattern + "$"), negated=negated, dir_only=dir_only)
+
+
+def _translate(glob: str) -> str:
+    """Translate a gitignore glob into a regular expression."""
+    parts = []
+    i = 0
+    while i < len(glob):
+        char = glob[i]
+        if glob.startswith("**/", i):
+            parts.append("(?:.*/)?")
+            i += 3
+        elif glob.startswith("/**", i) and i + 3 == len(glob):
+            parts.append("/.*")
+            i += 3
+        elif glob.startswith("**", i):
+            parts.append(".*")
+            i += 2
+        elif char == "*":
+            parts.append("[^/]*")
+            i += 1
+        elif char == "?":
+            parts.append("[^/]")
+            i += 1
+        elif char == "[":
+            end = glob.find("]", i + 2)
+            if end == -1:
+                parts.append(re.escape(char))
+                i += 1
+                continue
+            body = glob[i + 1:end]
+            if body.startswith("!"):
+                body = "^" + body[1:]
+            parts.append(f"[{body}]")
+            i = end + 1
+        elif char == "\\" and i + 1 < len(glob):
+            parts.append(re.escape(glob[i + 1]))
+            i += 2
+        else:
+            parts.append(re.escape(char))
+            i += 1
+    return "".join(parts)